@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"time"
+	_ "time/tzdata" // embed the IANA database so schedule time zones resolve on minimal images
 
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/database"
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	AggregatorFormatSandataJSON = "sandata_json"
+	AggregatorFormatCSV         = "csv"
+
+	// AggregatorTransportDirectory writes each batch to a local directory
+	AggregatorTransportDirectory = "directory"
+	// AggregatorTransportHTTP posts each batch to the aggregator's endpoint
+	AggregatorTransportHTTP = "http"
+)
+
+// AggregatorConfig controls the export of verified visits to the state EVV aggregator
+type AggregatorConfig struct {
+	Format    string `koanf:"format"`
+	Transport string `koanf:"transport"`
+	// ProviderID identifies the agency to the aggregator
+	ProviderID string `koanf:"provider_id"`
+	// Directory receives batch files when Transport is "directory"
+	Directory string `koanf:"directory"`
+	// URL and APIKey of the aggregator endpoint when Transport is "http"
+	URL     string        `koanf:"url"`
+	APIKey  string        `koanf:"api_key"`
+	Timeout time.Duration `koanf:"timeout"`
+	// BatchSize caps the number of visits in one batch
+	BatchSize int `koanf:"batch_size"`
+	// ExportInterval runs the export periodically; 0 leaves it to the API
+	ExportInterval time.Duration `koanf:"export_interval"`
+}
+
+func DefaultAggregatorConfig() *AggregatorConfig {
+	return &AggregatorConfig{
+		Format:    AggregatorFormatSandataJSON,
+		Transport: AggregatorTransportDirectory,
+		Directory: "exports/aggregator",
+		Timeout:   30 * time.Second,
+		BatchSize: 500,
+	}
+}
+
+func (c *AggregatorConfig) Validate() error {
+	if c.Format != AggregatorFormatSandataJSON && c.Format != AggregatorFormatCSV {
+		return fmt.Errorf("invalid format: %s (must be one of: %s, %s)", c.Format, AggregatorFormatSandataJSON, AggregatorFormatCSV)
+	}
+
+	switch c.Transport {
+	case AggregatorTransportDirectory:
+		if c.Directory == "" {
+			return fmt.Errorf("directory is required for the %s transport", AggregatorTransportDirectory)
+		}
+	case AggregatorTransportHTTP:
+		if c.URL == "" {
+			return fmt.Errorf("url is required for the %s transport", AggregatorTransportHTTP)
+		}
+	default:
+		return fmt.Errorf("invalid transport: %s (must be one of: %s, %s)", c.Transport, AggregatorTransportDirectory, AggregatorTransportHTTP)
+	}
+
+	if c.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	if c.BatchSize < 1 || c.BatchSize > 10000 {
+		return fmt.Errorf("batch_size must be between 1 and 10000")
+	}
+
+	if c.ExportInterval != 0 && c.ExportInterval < time.Minute {
+		return fmt.Errorf("export_interval must be 0 or at least 1m")
+	}
+
+	return nil
+}
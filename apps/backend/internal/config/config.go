@@ -37,6 +37,14 @@ type Config struct {
 	IntegrationResendAPIKey string `koanf:"integration_resend_api_key" validate:"required"`
 
 	Observability *ObservabilityConfig `koanf:"observability"`
+
+	EVV *EVVConfig `koanf:"evv"`
+
+	Aggregator *AggregatorConfig `koanf:"aggregator"`
+
+	Idempotency *IdempotencyConfig `koanf:"idempotency"`
+
+	Timesheet *TimesheetConfig `koanf:"timesheet"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -79,5 +87,41 @@ func LoadConfig() (*Config, error) {
 		logger.Fatal().Err(err).Msg("invalid observability config")
 	}
 
+	// Set default EVV config if not provided
+	if mainConfig.EVV == nil {
+		mainConfig.EVV = DefaultEVVConfig()
+	}
+
+	if err := mainConfig.EVV.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid evv config")
+	}
+
+	// Set default aggregator config if not provided
+	if mainConfig.Aggregator == nil {
+		mainConfig.Aggregator = DefaultAggregatorConfig()
+	}
+
+	if err := mainConfig.Aggregator.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid aggregator config")
+	}
+
+	// Set default idempotency config if not provided
+	if mainConfig.Idempotency == nil {
+		mainConfig.Idempotency = DefaultIdempotencyConfig()
+	}
+
+	if err := mainConfig.Idempotency.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid idempotency config")
+	}
+
+	// Set default timesheet config if not provided
+	if mainConfig.Timesheet == nil {
+		mainConfig.Timesheet = DefaultTimesheetConfig()
+	}
+
+	if err := mainConfig.Timesheet.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid timesheet config")
+	}
+
 	return mainConfig, nil
 }
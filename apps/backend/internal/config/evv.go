@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// GeofencePolicyReject refuses clock events recorded outside the geofence
+	GeofencePolicyReject = "reject"
+	// GeofencePolicyFlag accepts clock events outside the geofence but marks the visit
+	GeofencePolicyFlag = "flag"
+)
+
+type EVVConfig struct {
+	GeofenceRadiusMeters int    `koanf:"geofence_radius_meters"`
+	GeofencePolicy       string `koanf:"geofence_policy"`
+	// A schedule with no visit this long after its scheduled start is marked missed
+	MissedVisitGracePeriod  time.Duration `koanf:"missed_visit_grace_period"`
+	MissedVisitScanInterval time.Duration `koanf:"missed_visit_scan_interval"`
+	// CoordinatorEmail receives missed visit alerts; alerts are only logged when empty
+	CoordinatorEmail string `koanf:"coordinator_email"`
+	// Recurring series are materialized into schedules this many days ahead
+	SeriesMaterializeDays     int           `koanf:"series_materialize_days"`
+	SeriesMaterializeInterval time.Duration `koanf:"series_materialize_interval"`
+	// Clock events further than this from the scheduled window raise a visit exception
+	LateClockInThreshold   time.Duration `koanf:"late_clock_in_threshold"`
+	EarlyClockOutThreshold time.Duration `koanf:"early_clock_out_threshold"`
+	// Synced offline events are flagged when the device clock is off by more
+	// than SyncClockSkewThreshold or they arrive later than SyncLateArrivalThreshold
+	SyncClockSkewThreshold   time.Duration `koanf:"sync_clock_skew_threshold"`
+	SyncLateArrivalThreshold time.Duration `koanf:"sync_late_arrival_threshold"`
+}
+
+func DefaultEVVConfig() *EVVConfig {
+	return &EVVConfig{
+		GeofenceRadiusMeters:      150,
+		GeofencePolicy:            GeofencePolicyFlag,
+		MissedVisitGracePeriod:    15 * time.Minute,
+		MissedVisitScanInterval:   5 * time.Minute,
+		SeriesMaterializeDays:     14,
+		SeriesMaterializeInterval: time.Hour,
+		LateClockInThreshold:      10 * time.Minute,
+		EarlyClockOutThreshold:    10 * time.Minute,
+		SyncClockSkewThreshold:    2 * time.Minute,
+		SyncLateArrivalThreshold:  15 * time.Minute,
+	}
+}
+
+func (c *EVVConfig) Validate() error {
+	if c.GeofenceRadiusMeters <= 0 {
+		return fmt.Errorf("geofence_radius_meters must be positive")
+	}
+
+	if c.GeofencePolicy != GeofencePolicyReject && c.GeofencePolicy != GeofencePolicyFlag {
+		return fmt.Errorf("invalid geofence_policy: %s (must be one of: %s, %s)", c.GeofencePolicy, GeofencePolicyReject, GeofencePolicyFlag)
+	}
+
+	if c.MissedVisitGracePeriod <= 0 {
+		return fmt.Errorf("missed_visit_grace_period must be positive")
+	}
+
+	if c.MissedVisitScanInterval < time.Minute {
+		return fmt.Errorf("missed_visit_scan_interval must be at least 1m")
+	}
+
+	if c.SeriesMaterializeDays < 1 || c.SeriesMaterializeDays > 365 {
+		return fmt.Errorf("series_materialize_days must be between 1 and 365")
+	}
+
+	if c.SeriesMaterializeInterval < time.Minute {
+		return fmt.Errorf("series_materialize_interval must be at least 1m")
+	}
+
+	if c.LateClockInThreshold < 0 {
+		return fmt.Errorf("late_clock_in_threshold must not be negative")
+	}
+
+	if c.EarlyClockOutThreshold < 0 {
+		return fmt.Errorf("early_clock_out_threshold must not be negative")
+	}
+
+	if c.SyncClockSkewThreshold <= 0 {
+		return fmt.Errorf("sync_clock_skew_threshold must be positive")
+	}
+
+	if c.SyncLateArrivalThreshold <= 0 {
+		return fmt.Errorf("sync_late_arrival_threshold must be positive")
+	}
+
+	return nil
+}
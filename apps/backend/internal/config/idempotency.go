@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// IdempotencyConfig controls the replay of responses to requests retried with an Idempotency-Key
+type IdempotencyConfig struct {
+	// TTL is how long a response is kept for replay
+	TTL time.Duration `koanf:"ttl"`
+	// LockTimeout bounds how long a key is held by a request that is still running
+	LockTimeout time.Duration `koanf:"lock_timeout"`
+}
+
+func DefaultIdempotencyConfig() *IdempotencyConfig {
+	return &IdempotencyConfig{
+		TTL:         24 * time.Hour,
+		LockTimeout: time.Minute,
+	}
+}
+
+func (c *IdempotencyConfig) Validate() error {
+	if c.TTL < time.Minute {
+		return fmt.Errorf("ttl must be at least 1m")
+	}
+
+	if c.LockTimeout < time.Second {
+		return fmt.Errorf("lock_timeout must be at least 1s")
+	}
+
+	if c.LockTimeout > c.TTL {
+		return fmt.Errorf("lock_timeout must not be longer than ttl")
+	}
+
+	return nil
+}
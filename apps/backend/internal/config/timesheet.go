@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	PayPeriodWeekly   = "weekly"
+	PayPeriodBiweekly = "biweekly"
+)
+
+// TimesheetConfig controls how completed visits add up to caregiver hours
+type TimesheetConfig struct {
+	PayPeriod string `koanf:"pay_period"`
+	// PeriodStart is the first day of any pay period, as YYYY-MM-DD; every
+	// other period starts a whole number of periods before or after it
+	PeriodStart string `koanf:"period_start"`
+	// TimeZone in which pay periods and workdays start at midnight
+	TimeZone string `koanf:"time_zone"`
+	// Hours past these in a workday or a 7-day workweek are overtime; 0 disables the rule
+	DailyOvertimeThreshold  time.Duration `koanf:"daily_overtime_threshold"`
+	WeeklyOvertimeThreshold time.Duration `koanf:"weekly_overtime_threshold"`
+}
+
+func DefaultTimesheetConfig() *TimesheetConfig {
+	return &TimesheetConfig{
+		PayPeriod:               PayPeriodWeekly,
+		PeriodStart:             "2024-01-01",
+		TimeZone:                "UTC",
+		WeeklyOvertimeThreshold: 40 * time.Hour,
+	}
+}
+
+func (c *TimesheetConfig) Validate() error {
+	if c.PayPeriod != PayPeriodWeekly && c.PayPeriod != PayPeriodBiweekly {
+		return fmt.Errorf("invalid pay_period: %s (must be one of: %s, %s)", c.PayPeriod, PayPeriodWeekly, PayPeriodBiweekly)
+	}
+
+	location, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return fmt.Errorf("invalid time_zone: %w", err)
+	}
+
+	if _, err := time.ParseInLocation(time.DateOnly, c.PeriodStart, location); err != nil {
+		return fmt.Errorf("period_start must be a YYYY-MM-DD date")
+	}
+
+	if c.DailyOvertimeThreshold < 0 || c.DailyOvertimeThreshold >= 24*time.Hour {
+		return fmt.Errorf("daily_overtime_threshold must be between 0 and 24h")
+	}
+
+	if c.WeeklyOvertimeThreshold < 0 || c.WeeklyOvertimeThreshold >= 7*24*time.Hour {
+		return fmt.Errorf("weekly_overtime_threshold must be between 0 and 168h")
+	}
+
+	return nil
+}
+
+// PeriodDays is the length of a pay period in days
+func (c *TimesheetConfig) PeriodDays() int {
+	if c.PayPeriod == PayPeriodBiweekly {
+		return 14
+	}
+	return 7
+}
+
+// Location is the time zone of pay periods; the config has been validated
+func (c *TimesheetConfig) Location() *time.Location {
+	location, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// Anchor is the start of the pay period that begins on PeriodStart
+func (c *TimesheetConfig) Anchor() time.Time {
+	anchor, _ := time.ParseInLocation(time.DateOnly, c.PeriodStart, c.Location())
+	return anchor
+}
@@ -0,0 +1,77 @@
+CREATE EXTENSION IF NOT EXISTS "pgcrypto";
+
+CREATE OR REPLACE FUNCTION trigger_set_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+	NEW.updated_at = NOW();
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TABLE schedules (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	client_name TEXT NOT NULL,
+	shift_time TEXT NOT NULL,
+	location TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'upcoming',
+	visit_id UUID,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE visits (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	schedule_id UUID NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+	start_time TIMESTAMPTZ NOT NULL,
+	end_time TIMESTAMPTZ,
+	start_latitude DOUBLE PRECISION NOT NULL,
+	start_longitude DOUBLE PRECISION NOT NULL,
+	end_latitude DOUBLE PRECISION,
+	end_longitude DOUBLE PRECISION,
+	status TEXT NOT NULL DEFAULT 'in_progress',
+	duration_minutes INTEGER,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE schedules
+	ADD CONSTRAINT schedules_visit_id_fkey FOREIGN KEY (visit_id) REFERENCES visits(id) ON DELETE SET NULL;
+
+CREATE TABLE tasks (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	schedule_id UUID NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	description TEXT,
+	status TEXT NOT NULL DEFAULT 'pending',
+	reason TEXT,
+	completed_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_schedules_status ON schedules(status);
+CREATE INDEX idx_schedules_created_at ON schedules(created_at);
+CREATE INDEX idx_visits_schedule_id ON visits(schedule_id);
+CREATE INDEX idx_visits_status ON visits(status);
+CREATE INDEX idx_tasks_schedule_id ON tasks(schedule_id);
+CREATE INDEX idx_tasks_status ON tasks(status);
+
+CREATE TRIGGER set_updated_at_schedules
+	BEFORE UPDATE ON schedules
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TRIGGER set_updated_at_visits
+	BEFORE UPDATE ON visits
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TRIGGER set_updated_at_tasks
+	BEFORE UPDATE ON tasks
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS tasks;
+ALTER TABLE IF EXISTS schedules DROP CONSTRAINT IF EXISTS schedules_visit_id_fkey;
+DROP TABLE IF EXISTS visits;
+DROP TABLE IF EXISTS schedules;
+DROP FUNCTION IF EXISTS trigger_set_updated_at();
@@ -0,0 +1,25 @@
+ALTER TABLE schedules
+	ADD COLUMN latitude DOUBLE PRECISION,
+	ADD COLUMN longitude DOUBLE PRECISION,
+	ADD COLUMN geofence_radius_meters INTEGER CHECK (geofence_radius_meters > 0);
+
+ALTER TABLE visits
+	ADD COLUMN distance_meters DOUBLE PRECISION,
+	ADD COLUMN end_distance_meters DOUBLE PRECISION,
+	ADD COLUMN within_geofence BOOLEAN;
+
+CREATE INDEX idx_visits_within_geofence ON visits(within_geofence) WHERE within_geofence = FALSE;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_visits_within_geofence;
+
+ALTER TABLE visits
+	DROP COLUMN IF EXISTS within_geofence,
+	DROP COLUMN IF EXISTS end_distance_meters,
+	DROP COLUMN IF EXISTS distance_meters;
+
+ALTER TABLE schedules
+	DROP COLUMN IF EXISTS geofence_radius_meters,
+	DROP COLUMN IF EXISTS longitude,
+	DROP COLUMN IF EXISTS latitude;
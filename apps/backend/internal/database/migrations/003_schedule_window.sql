@@ -0,0 +1,54 @@
+ALTER TABLE schedules
+	ADD COLUMN scheduled_start TIMESTAMPTZ,
+	ADD COLUMN scheduled_end TIMESTAMPTZ,
+	ADD COLUMN time_zone TEXT NOT NULL DEFAULT 'UTC';
+
+-- Backfill the shift window from the free-text shift time ("09:00 - 12:00"),
+-- interpreted on the local date the schedule was created. Legacy schedules
+-- never recorded a time zone, so every existing row is read as UTC, the
+-- time_zone it is given here. Only valid clock times are cast; anything else,
+-- e.g. "25:99", falls through to the fallback below instead of aborting.
+UPDATE schedules
+SET
+	scheduled_start = ((created_at AT TIME ZONE time_zone)::date
+		+ trim(split_part(shift_time, '-', 1))::time) AT TIME ZONE time_zone,
+	scheduled_end = ((created_at AT TIME ZONE time_zone)::date
+		+ trim(split_part(shift_time, '-', 2))::time) AT TIME ZONE time_zone
+WHERE shift_time ~ '^\s*([01]?\d|2[0-3]):[0-5]\d\s*-\s*([01]?\d|2[0-3]):[0-5]\d\s*$';
+
+-- Overnight shifts end on the following day
+UPDATE schedules
+SET scheduled_end = scheduled_end + INTERVAL '1 day'
+WHERE scheduled_end <= scheduled_start;
+
+-- Rows whose shift time could not be parsed keep their creation time as the start
+UPDATE schedules
+SET
+	scheduled_start = created_at,
+	scheduled_end = created_at + INTERVAL '1 hour'
+WHERE scheduled_start IS NULL OR scheduled_end IS NULL;
+
+ALTER TABLE schedules
+	ALTER COLUMN scheduled_start SET NOT NULL,
+	ALTER COLUMN scheduled_end SET NOT NULL,
+	ADD CONSTRAINT schedules_scheduled_window_check CHECK (scheduled_end > scheduled_start),
+	DROP COLUMN shift_time;
+
+CREATE INDEX idx_schedules_scheduled_start ON schedules(scheduled_start);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_schedules_scheduled_start;
+
+ALTER TABLE schedules ADD COLUMN shift_time TEXT;
+
+UPDATE schedules
+SET shift_time = to_char(scheduled_start AT TIME ZONE time_zone, 'HH24:MI')
+	|| ' - ' || to_char(scheduled_end AT TIME ZONE time_zone, 'HH24:MI');
+
+ALTER TABLE schedules
+	ALTER COLUMN shift_time SET NOT NULL,
+	DROP CONSTRAINT IF EXISTS schedules_scheduled_window_check,
+	DROP COLUMN time_zone,
+	DROP COLUMN scheduled_end,
+	DROP COLUMN scheduled_start;
@@ -0,0 +1,29 @@
+CREATE TABLE caregivers (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	-- Clerk user ID of the caregiver's login
+	user_id TEXT NOT NULL UNIQUE,
+	first_name TEXT NOT NULL,
+	last_name TEXT NOT NULL,
+	email TEXT,
+	phone TEXT,
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER set_updated_at_caregivers
+	BEFORE UPDATE ON caregivers
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+ALTER TABLE schedules
+	ADD COLUMN caregiver_id UUID REFERENCES caregivers(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_schedules_caregiver_id ON schedules(caregiver_id, scheduled_start);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_schedules_caregiver_id;
+
+ALTER TABLE schedules DROP COLUMN IF EXISTS caregiver_id;
+
+DROP TABLE IF EXISTS caregivers;
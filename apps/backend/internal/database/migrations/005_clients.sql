@@ -0,0 +1,74 @@
+CREATE TABLE clients (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	first_name TEXT NOT NULL,
+	last_name TEXT NOT NULL DEFAULT '',
+	date_of_birth DATE,
+	gender TEXT,
+	medicaid_id TEXT UNIQUE,
+	address_line1 TEXT NOT NULL DEFAULT '',
+	address_line2 TEXT,
+	city TEXT NOT NULL DEFAULT '',
+	state TEXT NOT NULL DEFAULT '',
+	postal_code TEXT NOT NULL DEFAULT '',
+	latitude DOUBLE PRECISION,
+	longitude DOUBLE PRECISION,
+	phone TEXT,
+	emergency_contacts JSONB NOT NULL DEFAULT '[]',
+	status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'inactive')),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_clients_status ON clients(status);
+CREATE INDEX idx_clients_name ON clients(last_name, first_name);
+
+CREATE TRIGGER set_updated_at_clients
+	BEFORE UPDATE ON clients
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+-- One client per distinct client name, addressed at the most recent schedule's location
+ALTER TABLE clients ADD COLUMN legacy_name TEXT;
+
+INSERT INTO clients (first_name, last_name, address_line1, latitude, longitude, legacy_name, created_at)
+SELECT DISTINCT ON (trim(client_name))
+	COALESCE(substring(trim(client_name) FROM '^\S+'), trim(client_name)),
+	COALESCE(substring(trim(client_name) FROM '^\S+\s+(.*)$'), ''),
+	location,
+	latitude,
+	longitude,
+	trim(client_name),
+	created_at
+FROM schedules
+ORDER BY trim(client_name), created_at DESC;
+
+ALTER TABLE schedules ADD COLUMN client_id UUID REFERENCES clients(id) ON DELETE RESTRICT;
+
+UPDATE schedules s
+SET client_id = c.id
+FROM clients c
+WHERE c.legacy_name = trim(s.client_name);
+
+ALTER TABLE schedules
+	ALTER COLUMN client_id SET NOT NULL,
+	DROP COLUMN client_name;
+
+ALTER TABLE clients DROP COLUMN legacy_name;
+
+CREATE INDEX idx_schedules_client_id ON schedules(client_id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_schedules_client_id;
+
+ALTER TABLE schedules ADD COLUMN client_name TEXT;
+
+UPDATE schedules s
+SET client_name = trim(c.first_name || ' ' || c.last_name)
+FROM clients c
+WHERE c.id = s.client_id;
+
+ALTER TABLE schedules
+	ALTER COLUMN client_name SET NOT NULL,
+	DROP COLUMN client_id;
+
+DROP TABLE IF EXISTS clients;
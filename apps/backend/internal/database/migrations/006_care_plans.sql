@@ -0,0 +1,45 @@
+CREATE TABLE care_plans (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	client_id UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A client has at most one active care plan
+CREATE UNIQUE INDEX idx_care_plans_active_client ON care_plans(client_id) WHERE is_active;
+
+CREATE TRIGGER set_updated_at_care_plans
+	BEFORE UPDATE ON care_plans
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TABLE care_plan_tasks (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	care_plan_id UUID NOT NULL REFERENCES care_plans(id) ON DELETE CASCADE,
+	position INTEGER NOT NULL CHECK (position > 0),
+	name TEXT NOT NULL,
+	description TEXT,
+	is_required BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	UNIQUE (care_plan_id, position)
+);
+
+ALTER TABLE tasks
+	ADD COLUMN is_required BOOLEAN NOT NULL DEFAULT TRUE,
+	ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN care_plan_task_id UUID REFERENCES care_plan_tasks(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_schedule_sort_order ON tasks(schedule_id, sort_order);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_tasks_schedule_sort_order;
+
+ALTER TABLE tasks
+	DROP COLUMN IF EXISTS care_plan_task_id,
+	DROP COLUMN IF EXISTS sort_order,
+	DROP COLUMN IF EXISTS is_required;
+
+DROP TABLE IF EXISTS care_plan_tasks;
+DROP TABLE IF EXISTS care_plans;
@@ -0,0 +1,50 @@
+CREATE TABLE schedule_series (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	client_id UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+	caregiver_id UUID REFERENCES caregivers(id) ON DELETE SET NULL,
+	-- RFC 5545 recurrence pattern; the series bounds live in end_date and occurrence_count
+	rrule TEXT NOT NULL,
+	start_date DATE NOT NULL,
+	-- Local wall-clock start of every occurrence in time_zone
+	start_time TIME NOT NULL,
+	duration_minutes INTEGER NOT NULL CHECK (duration_minutes > 0),
+	time_zone TEXT NOT NULL,
+	end_date DATE,
+	occurrence_count INTEGER CHECK (occurrence_count > 0),
+	exception_dates DATE[] NOT NULL DEFAULT '{}',
+	location TEXT NOT NULL,
+	latitude DOUBLE PRECISION,
+	longitude DOUBLE PRECISION,
+	geofence_radius_meters INTEGER,
+	-- Occurrences up to and including this date have been created as schedules
+	materialized_through DATE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	CHECK (end_date IS NULL OR occurrence_count IS NULL)
+);
+
+CREATE INDEX idx_schedule_series_client_id ON schedule_series(client_id);
+
+CREATE TRIGGER set_updated_at_schedule_series
+	BEFORE UPDATE ON schedule_series
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+ALTER TABLE schedules
+	ADD COLUMN series_id UUID REFERENCES schedule_series(id) ON DELETE SET NULL,
+	-- Occurrence date the schedule was generated for
+	ADD COLUMN series_date DATE,
+	-- Set when a single occurrence was edited; series edits leave it alone
+	ADD COLUMN is_series_exception BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE UNIQUE INDEX idx_schedules_series_occurrence ON schedules(series_id, series_date);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_schedules_series_occurrence;
+
+ALTER TABLE schedules
+	DROP COLUMN IF EXISTS is_series_exception,
+	DROP COLUMN IF EXISTS series_date,
+	DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS schedule_series;
@@ -0,0 +1,52 @@
+CREATE TABLE exception_reason_codes (
+	code TEXT PRIMARY KEY CHECK (code ~ '^[A-Z][A-Z0-9_]*$'),
+	description TEXT NOT NULL,
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER set_updated_at_exception_reason_codes
+	BEFORE UPDATE ON exception_reason_codes
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+INSERT INTO exception_reason_codes (code, description) VALUES
+	('CAREGIVER_LATE', 'Caregiver arrived late'),
+	('CLIENT_REQUESTED_CHANGE', 'Client requested a change to the visit time'),
+	('CLIENT_UNAVAILABLE', 'Client was unavailable or refused service'),
+	('SERVICE_OUTSIDE_HOME', 'Service was provided in the community, away from the home'),
+	('GPS_UNAVAILABLE', 'Location could not be determined accurately'),
+	('DEVICE_ISSUE', 'Mobile device or app problem'),
+	('FORGOT_TO_CLOCK', 'Caregiver forgot to clock in or out'),
+	('EMERGENCY', 'Emergency during the visit'),
+	('OTHER', 'Other reason, see explanation');
+
+CREATE TABLE visit_exceptions (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	visit_id UUID NOT NULL REFERENCES visits(id) ON DELETE CASCADE,
+	type TEXT NOT NULL CHECK (type IN ('late_clock_in', 'early_clock_out', 'clock_in_outside_geofence', 'clock_out_outside_geofence', 'manual_entry')),
+	-- What was detected, e.g. how late the clock-in was
+	detail TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'pending_review', 'approved', 'rejected')),
+	reason_code TEXT REFERENCES exception_reason_codes(code),
+	explanation TEXT,
+	explained_by TEXT,
+	explained_at TIMESTAMPTZ,
+	reviewed_by TEXT,
+	reviewed_at TIMESTAMPTZ,
+	review_comment TEXT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	UNIQUE (visit_id, type)
+);
+
+CREATE INDEX idx_visit_exceptions_status ON visit_exceptions(status, created_at);
+
+CREATE TRIGGER set_updated_at_visit_exceptions
+	BEFORE UPDATE ON visit_exceptions
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS visit_exceptions;
+DROP TABLE IF EXISTS exception_reason_codes;
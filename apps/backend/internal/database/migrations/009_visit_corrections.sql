@@ -0,0 +1,35 @@
+ALTER TABLE visits
+	ADD COLUMN is_manual_entry BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Every coordinator change to a visit's clock data, with the values before and
+-- after it. The previous values of a visit's first correction are its originals.
+CREATE TABLE visit_corrections (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	visit_id UUID NOT NULL REFERENCES visits(id) ON DELETE CASCADE,
+	kind TEXT NOT NULL CHECK (kind IN ('manual_entry', 'correction')),
+	reason_code TEXT NOT NULL REFERENCES exception_reason_codes(code),
+	reason TEXT NOT NULL,
+	corrected_by TEXT NOT NULL,
+	previous_start_time TIMESTAMPTZ,
+	previous_end_time TIMESTAMPTZ,
+	previous_start_latitude DOUBLE PRECISION,
+	previous_start_longitude DOUBLE PRECISION,
+	previous_end_latitude DOUBLE PRECISION,
+	previous_end_longitude DOUBLE PRECISION,
+	corrected_start_time TIMESTAMPTZ,
+	corrected_end_time TIMESTAMPTZ,
+	corrected_start_latitude DOUBLE PRECISION,
+	corrected_start_longitude DOUBLE PRECISION,
+	corrected_end_latitude DOUBLE PRECISION,
+	corrected_end_longitude DOUBLE PRECISION,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_visit_corrections_visit_id ON visit_corrections(visit_id, created_at);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS visit_corrections;
+
+ALTER TABLE visits
+	DROP COLUMN IF EXISTS is_manual_entry;
@@ -0,0 +1,98 @@
+-- Append-only trail of every write to schedules, visits and tasks. Rows are
+-- written by trigger in the same transaction as the change; the actor and
+-- request ID are read from the audit.actor and audit.request_id settings the
+-- application sets on the transaction.
+CREATE TABLE audit_events (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	entity TEXT NOT NULL,
+	entity_id UUID NOT NULL,
+	action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+	-- Clerk user ID, system:<job> for background jobs, NULL when unknown
+	actor TEXT,
+	request_id TEXT,
+	-- Changed columns only: their old values in before, new values in after
+	before JSONB,
+	after JSONB,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX idx_audit_events_entity ON audit_events(entity, entity_id, created_at);
+CREATE INDEX idx_audit_events_actor ON audit_events(actor, created_at);
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
+
+CREATE OR REPLACE FUNCTION trigger_record_audit_event()
+RETURNS TRIGGER AS $$
+DECLARE
+	old_row JSONB;
+	new_row JSONB;
+	before_diff JSONB;
+	after_diff JSONB;
+BEGIN
+	IF TG_OP <> 'INSERT' THEN
+		old_row := to_jsonb(OLD) - 'updated_at';
+	END IF;
+	IF TG_OP <> 'DELETE' THEN
+		new_row := to_jsonb(NEW) - 'updated_at';
+	END IF;
+
+	IF TG_OP = 'UPDATE' THEN
+		SELECT jsonb_object_agg(key, value) INTO before_diff
+		FROM jsonb_each(old_row) WHERE new_row -> key IS DISTINCT FROM value;
+		SELECT jsonb_object_agg(key, value) INTO after_diff
+		FROM jsonb_each(new_row) WHERE old_row -> key IS DISTINCT FROM value;
+
+		-- Nothing but updated_at changed
+		IF before_diff IS NULL THEN
+			RETURN NULL;
+		END IF;
+	ELSE
+		before_diff := old_row;
+		after_diff := new_row;
+	END IF;
+
+	INSERT INTO audit_events (entity, entity_id, action, actor, request_id, before, after)
+	VALUES (
+		TG_ARGV[0],
+		(COALESCE(new_row, old_row) ->> 'id')::uuid,
+		CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+		NULLIF(current_setting('audit.actor', TRUE), ''),
+		NULLIF(current_setting('audit.request_id', TRUE), ''),
+		before_diff,
+		after_diff
+	);
+
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_schedules
+	AFTER INSERT OR UPDATE OR DELETE ON schedules
+	FOR EACH ROW EXECUTE FUNCTION trigger_record_audit_event('schedule');
+
+CREATE TRIGGER audit_visits
+	AFTER INSERT OR UPDATE OR DELETE ON visits
+	FOR EACH ROW EXECUTE FUNCTION trigger_record_audit_event('visit');
+
+CREATE TRIGGER audit_tasks
+	AFTER INSERT OR UPDATE OR DELETE ON tasks
+	FOR EACH ROW EXECUTE FUNCTION trigger_record_audit_event('task');
+
+CREATE OR REPLACE FUNCTION trigger_prevent_audit_change()
+RETURNS TRIGGER AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+	BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_events
+	FOR EACH STATEMENT EXECUTE FUNCTION trigger_prevent_audit_change();
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS audit_tasks ON tasks;
+DROP TRIGGER IF EXISTS audit_visits ON visits;
+DROP TRIGGER IF EXISTS audit_schedules ON schedules;
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS trigger_prevent_audit_change();
+DROP FUNCTION IF EXISTS trigger_record_audit_event();
@@ -0,0 +1,21 @@
+-- Type of service, the first of the six data elements the Cures Act requires
+-- for every EVV record
+ALTER TABLE schedules ADD COLUMN service_code TEXT;
+ALTER TABLE schedule_series ADD COLUMN service_code TEXT;
+
+-- Latest completeness check of each completed visit against the six elements
+CREATE TABLE visit_compliance (
+	visit_id UUID PRIMARY KEY REFERENCES visits(id) ON DELETE CASCADE,
+	is_compliant BOOLEAN NOT NULL,
+	-- Elements the visit record lacks; empty when compliant
+	missing_elements TEXT[] NOT NULL DEFAULT '{}',
+	checked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_visit_compliance_non_compliant ON visit_compliance(visit_id) WHERE NOT is_compliant;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS visit_compliance;
+ALTER TABLE schedule_series DROP COLUMN IF EXISTS service_code;
+ALTER TABLE schedules DROP COLUMN IF EXISTS service_code;
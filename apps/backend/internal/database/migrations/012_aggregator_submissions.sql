@@ -0,0 +1,45 @@
+-- Batches of verified visits exported to the state EVV aggregator
+CREATE TABLE aggregator_batches (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	format TEXT NOT NULL,
+	transport TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+	file_name TEXT NOT NULL,
+	visit_count INTEGER NOT NULL,
+	-- Aggregator's reference for the batch, or the file path for a directory
+	reference TEXT,
+	error TEXT,
+	delivered_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_aggregator_batches_created_at ON aggregator_batches(created_at);
+
+CREATE TRIGGER set_updated_at_aggregator_batches
+	BEFORE UPDATE ON aggregator_batches
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+-- Each visit's submission in a batch and the aggregator's verdict on it
+CREATE TABLE visit_submissions (
+	batch_id UUID NOT NULL REFERENCES aggregator_batches(id) ON DELETE CASCADE,
+	visit_id UUID NOT NULL REFERENCES visits(id) ON DELETE CASCADE,
+	status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'submitted', 'accepted', 'rejected', 'failed')),
+	-- Rejection or delivery failure messages
+	messages TEXT[] NOT NULL DEFAULT '{}',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (batch_id, visit_id)
+);
+
+CREATE INDEX idx_visit_submissions_visit_id ON visit_submissions(visit_id, created_at DESC);
+CREATE INDEX idx_visit_submissions_status ON visit_submissions(status, created_at);
+
+CREATE TRIGGER set_updated_at_visit_submissions
+	BEFORE UPDATE ON visit_submissions
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS visit_submissions;
+DROP TABLE IF EXISTS aggregator_batches;
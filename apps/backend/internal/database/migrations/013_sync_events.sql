@@ -0,0 +1,36 @@
+-- Clock and task events recorded on a device, possibly offline, and synced in
+-- batches. The client's event ID deduplicates events a device resends.
+CREATE TABLE sync_events (
+	user_id TEXT NOT NULL,
+	client_event_id UUID NOT NULL,
+	type TEXT NOT NULL CHECK (type IN ('clock_in', 'clock_out', 'task_status')),
+	device_time TIMESTAMPTZ NOT NULL,
+	received_at TIMESTAMPTZ NOT NULL,
+	-- Event payload as sent; not foreign keys since the event may refer to rows that do not exist
+	schedule_id UUID,
+	task_id UUID,
+	latitude DOUBLE PRECISION,
+	longitude DOUBLE PRECISION,
+	task_status TEXT,
+	reason TEXT,
+	status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'applied', 'rejected')),
+	flags TEXT[] NOT NULL DEFAULT '{}',
+	clock_skew_seconds INTEGER,
+	error_code TEXT,
+	error TEXT,
+	visit_id UUID REFERENCES visits(id) ON DELETE SET NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (user_id, client_event_id)
+);
+
+CREATE INDEX idx_sync_events_received_at ON sync_events(received_at DESC);
+CREATE INDEX idx_sync_events_flags ON sync_events USING GIN (flags);
+
+CREATE TRIGGER set_updated_at_sync_events
+	BEFORE UPDATE ON sync_events
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS sync_events;
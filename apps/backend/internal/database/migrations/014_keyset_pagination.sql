@@ -0,0 +1,12 @@
+-- Listings page by (created_at, id) cursors, newest first
+DROP INDEX IF EXISTS idx_schedules_created_at;
+CREATE INDEX idx_schedules_created_at_id ON schedules(created_at, id);
+CREATE INDEX idx_visits_created_at_id ON visits(created_at, id);
+CREATE INDEX idx_tasks_created_at_id ON tasks(created_at, id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_tasks_created_at_id;
+DROP INDEX IF EXISTS idx_visits_created_at_id;
+DROP INDEX IF EXISTS idx_schedules_created_at_id;
+CREATE INDEX idx_schedules_created_at ON schedules(created_at);
@@ -0,0 +1,80 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Searchable text of each schedule: its client's name, its location and the
+-- names of its tasks. Kept in its own table so that refreshing it neither
+-- touches schedules.updated_at nor shows up in the audit trail.
+CREATE TABLE schedule_search (
+	schedule_id UUID PRIMARY KEY REFERENCES schedules(id) ON DELETE CASCADE,
+	client_name TEXT NOT NULL DEFAULT '',
+	location TEXT NOT NULL DEFAULT '',
+	task_names TEXT NOT NULL DEFAULT '',
+	-- Names are not stemmed, so the simple configuration is used throughout
+	document TSVECTOR GENERATED ALWAYS AS (
+		setweight(to_tsvector('simple', client_name), 'A') ||
+		setweight(to_tsvector('simple', location), 'B') ||
+		setweight(to_tsvector('simple', task_names), 'C')
+	) STORED
+);
+
+CREATE INDEX idx_schedule_search_document ON schedule_search USING GIN (document);
+CREATE INDEX idx_schedule_search_client_name_trgm ON schedule_search USING GIN (client_name gin_trgm_ops);
+CREATE INDEX idx_schedule_search_location_trgm ON schedule_search USING GIN (location gin_trgm_ops);
+CREATE INDEX idx_schedule_search_task_names_trgm ON schedule_search USING GIN (task_names gin_trgm_ops);
+
+-- Rebuild the search row of a schedule; a schedule that no longer exists is skipped
+CREATE OR REPLACE FUNCTION refresh_schedule_search(target_id UUID)
+RETURNS VOID AS $$
+	INSERT INTO schedule_search (schedule_id, client_name, location, task_names)
+	SELECT
+		s.id,
+		COALESCE((SELECT trim(c.first_name || ' ' || c.last_name) FROM clients c WHERE c.id = s.client_id), ''),
+		s.location,
+		COALESCE((SELECT string_agg(t.name, ' ' ORDER BY t.sort_order, t.created_at) FROM tasks t WHERE t.schedule_id = s.id), '')
+	FROM schedules s
+	WHERE s.id = target_id
+	ON CONFLICT (schedule_id) DO UPDATE
+	SET client_name = EXCLUDED.client_name, location = EXCLUDED.location, task_names = EXCLUDED.task_names;
+$$ LANGUAGE sql;
+
+CREATE OR REPLACE FUNCTION trigger_refresh_schedule_search()
+RETURNS TRIGGER AS $$
+BEGIN
+	IF TG_TABLE_NAME = 'schedules' THEN
+		PERFORM refresh_schedule_search(NEW.id);
+	ELSIF TG_TABLE_NAME = 'tasks' THEN
+		IF TG_OP <> 'INSERT' THEN
+			PERFORM refresh_schedule_search(OLD.schedule_id);
+		END IF;
+		IF TG_OP <> 'DELETE' AND (TG_OP = 'INSERT' OR NEW.schedule_id <> OLD.schedule_id) THEN
+			PERFORM refresh_schedule_search(NEW.schedule_id);
+		END IF;
+	ELSIF TG_TABLE_NAME = 'clients' THEN
+		PERFORM refresh_schedule_search(s.id) FROM schedules s WHERE s.client_id = NEW.id;
+	END IF;
+
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER refresh_schedule_search_schedules
+	AFTER INSERT OR UPDATE OF client_id, location ON schedules
+	FOR EACH ROW EXECUTE FUNCTION trigger_refresh_schedule_search();
+
+CREATE TRIGGER refresh_schedule_search_tasks
+	AFTER INSERT OR UPDATE OF name, schedule_id, sort_order OR DELETE ON tasks
+	FOR EACH ROW EXECUTE FUNCTION trigger_refresh_schedule_search();
+
+CREATE TRIGGER refresh_schedule_search_clients
+	AFTER UPDATE OF first_name, last_name ON clients
+	FOR EACH ROW EXECUTE FUNCTION trigger_refresh_schedule_search();
+
+SELECT refresh_schedule_search(id) FROM schedules;
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS refresh_schedule_search_clients ON clients;
+DROP TRIGGER IF EXISTS refresh_schedule_search_tasks ON tasks;
+DROP TRIGGER IF EXISTS refresh_schedule_search_schedules ON schedules;
+DROP FUNCTION IF EXISTS trigger_refresh_schedule_search();
+DROP FUNCTION IF EXISTS refresh_schedule_search(UUID);
+DROP TABLE IF EXISTS schedule_search;
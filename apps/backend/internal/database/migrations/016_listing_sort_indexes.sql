@@ -0,0 +1,7 @@
+-- Visits can be listed by start time; schedules.scheduled_start and the
+-- status columns are already indexed
+CREATE INDEX idx_visits_start_time_id ON visits(start_time, id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_visits_start_time_id;
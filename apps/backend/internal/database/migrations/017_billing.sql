@@ -0,0 +1,43 @@
+-- Payer billed for a client's visits, e.g. a state Medicaid program or an MCO
+ALTER TABLE clients ADD COLUMN payer TEXT;
+
+-- Rate per billable unit of a payer's service code. A rate for a caregiver
+-- overrides the payer's general rate for that caregiver's visits.
+CREATE TABLE billing_rates (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	payer TEXT NOT NULL,
+	service_code TEXT NOT NULL,
+	caregiver_id UUID REFERENCES caregivers(id) ON DELETE CASCADE,
+	unit_minutes INTEGER NOT NULL DEFAULT 15 CHECK (unit_minutes > 0),
+	rounding TEXT NOT NULL CHECK (rounding IN ('eight_minute', 'round_down', 'round_up')),
+	unit_rate_cents BIGINT NOT NULL CHECK (unit_rate_cents >= 0),
+	-- Service dates the rate applies to; both inclusive, open-ended without an end
+	effective_from DATE NOT NULL,
+	effective_to DATE CHECK (effective_to >= effective_from),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_billing_rates_rate ON billing_rates(payer, service_code, COALESCE(caregiver_id, '00000000-0000-0000-0000-000000000000'), effective_from);
+
+CREATE TRIGGER set_updated_at_billing_rates
+	BEFORE UPDATE ON billing_rates
+	FOR EACH ROW EXECUTE FUNCTION trigger_set_updated_at();
+
+-- Latest billing calculation of a completed visit. billing_issue says why a
+-- visit could not be billed; units and amount are empty then. It is kept out
+-- of visits so that billing a visit again does not change the visit.
+CREATE TABLE visit_billing (
+	visit_id UUID PRIMARY KEY REFERENCES visits(id) ON DELETE CASCADE,
+	billing_rate_id UUID REFERENCES billing_rates(id) ON DELETE SET NULL,
+	billable_units INTEGER,
+	billed_amount_cents BIGINT,
+	billing_issue TEXT CHECK (billing_issue IN ('missing_payer', 'missing_service_code', 'missing_rate')),
+	billed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS visit_billing;
+DROP TABLE IF EXISTS billing_rates;
+ALTER TABLE clients DROP COLUMN IF EXISTS payer;
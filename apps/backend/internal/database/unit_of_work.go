@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DBTX is the query interface shared by the connection pool and a
+// transaction, so a repository can run on either. Begin on a transaction
+// starts a savepoint.
+type DBTX interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// UnitOfWork runs a callback against repositories that share one
+// transaction. R is the set of repositories, built for each transaction.
+type UnitOfWork[R any] struct {
+	pool         *pgxpool.Pool
+	repositories func(db DBTX) R
+}
+
+func NewUnitOfWork[R any](pool *pgxpool.Pool, repositories func(db DBTX) R) *UnitOfWork[R] {
+	return &UnitOfWork[R]{
+		pool:         pool,
+		repositories: repositories,
+	}
+}
+
+// Do runs fn in a transaction. The transaction is committed when fn returns
+// nil and rolled back when it returns an error or panics; fn's error is
+// returned as is.
+func (u *UnitOfWork[R]) Do(ctx context.Context, fn func(repos R) error) error {
+	tx, err := u.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(u.repositories(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
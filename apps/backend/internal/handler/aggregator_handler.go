@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
+)
+
+type AggregatorHandler struct {
+	Handler
+	aggregatorService *service.AggregatorService
+}
+
+func NewAggregatorHandler(s *server.Server, aggregatorService *service.AggregatorService) *AggregatorHandler {
+	return &AggregatorHandler{
+		Handler:           NewHandler(s),
+		aggregatorService: aggregatorService,
+	}
+}
+
+// Export the visits that are due to the aggregator now
+func (h *AggregatorHandler) ExportVisits(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.EmptyRequest) ([]model.AggregatorBatch, error) {
+			return h.aggregatorService.ExportVisits(c.Request().Context())
+		},
+		http.StatusOK,
+		&validation.EmptyRequest{},
+	)(c)
+}
+
+// Get aggregator batches
+func (h *AggregatorHandler) GetAggregatorBatches(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListAggregatorBatchesQuery) (*model.PaginatedResponse[model.AggregatorBatch], error) {
+			return h.aggregatorService.GetAggregatorBatches(c.Request().Context(), req.Page, req.Limit, req.Status)
+		},
+		http.StatusOK,
+		&validation.ListAggregatorBatchesQuery{},
+	)(c)
+}
+
+// Get an aggregator batch with its visit submissions
+func (h *AggregatorHandler) GetAggregatorBatchById(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.AggregatorBatchIDParam) (*model.AggregatorBatchWithSubmissions, error) {
+			return h.aggregatorService.GetAggregatorBatchByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.AggregatorBatchIDParam{},
+	)(c)
+}
+
+// Get visit submissions, e.g. the rejected ones
+func (h *AggregatorHandler) GetSubmissions(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListVisitSubmissionsQuery) (*model.PaginatedResponse[model.VisitSubmission], error) {
+			return h.aggregatorService.GetSubmissions(c.Request().Context(), req.Page, req.Limit, req.Status)
+		},
+		http.StatusOK,
+		&validation.ListVisitSubmissionsQuery{},
+	)(c)
+}
+
+// Get a visit's submissions to the aggregator
+func (h *AggregatorHandler) GetVisitSubmissions(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.VisitIDParam) ([]model.VisitSubmission, error) {
+			return h.aggregatorService.GetVisitSubmissions(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.VisitIDParam{},
+	)(c)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
+)
+
+type AuditHandler struct {
+	Handler
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(s *server.Server, auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		Handler:      NewHandler(s),
+		auditService: auditService,
+	}
+}
+
+// Get the audit trail filtered by entity, actor and time range
+func (h *AuditHandler) GetAuditEvents(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListAuditEventsQuery) (*model.PaginatedResponse[model.AuditEvent], error) {
+			filter := model.AuditEventFilter{
+				Entity:   req.Entity,
+				EntityID: req.EntityID,
+				Actor:    req.Actor,
+			}
+			if req.From != "" {
+				from, _ := time.Parse(time.RFC3339, req.From)
+				filter.From = &from
+			}
+			if req.To != "" {
+				to, _ := time.Parse(time.RFC3339, req.To)
+				filter.To = &to
+			}
+
+			return h.auditService.GetAuditEvents(c.Request().Context(), filter, req.Page, req.Limit)
+		},
+		http.StatusOK,
+		&validation.ListAuditEventsQuery{},
+	)(c)
+}
@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"mime"
+	"net/http"
+	"reflect"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/newrelic/go-agent/v3/integrations/nrpkgerrors"
 	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/audit"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/etag"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/middleware"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
@@ -27,6 +33,10 @@ type HandlerFunc[Req validation.Validatable, Res any] func(c echo.Context, req R
 // HandlerFuncNoContent represents a typed handler function that processes a request without returning content
 type HandlerFuncNoContent[Req validation.Validatable] func(c echo.Context, req Req) error
 
+// VersionLoader loads the current representation of the resource a request
+// writes, for its If-Match precondition to be checked against
+type VersionLoader[Req validation.Validatable] func(c echo.Context, req Req) (etag.Tagged, error)
+
 // ResponseHandler defines the interface for handling different response types
 type ResponseHandler interface {
 	Handle(c echo.Context, result interface{}) error
@@ -68,17 +78,23 @@ func (h NoContentResponseHandler) AddAttributes(txn *newrelic.Transaction, resul
 	// http.status_code is already set by tracing middleware
 }
 
+// File is a download; its name and content type may depend on the request,
+// e.g. on the format it asks for
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
 // FileResponseHandler handles file responses
 type FileResponseHandler struct {
-	status      int
-	filename    string
-	contentType string
+	status int
 }
 
 func (h FileResponseHandler) Handle(c echo.Context, result interface{}) error {
-	data := result.([]byte)
-	c.Response().Header().Set("Content-Disposition", "attachment; filename="+h.filename)
-	return c.Blob(h.status, h.contentType, data)
+	file := result.(*File)
+	c.Response().Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	return c.Blob(h.status, file.ContentType, file.Data)
 }
 
 func (h FileResponseHandler) GetOperation() string {
@@ -86,13 +102,11 @@ func (h FileResponseHandler) GetOperation() string {
 }
 
 func (h FileResponseHandler) AddAttributes(txn *newrelic.Transaction, result interface{}) {
-	if txn != nil {
-		// http.status_code is already set by tracing middleware
-		txn.AddAttribute("file.name", h.filename)
-		txn.AddAttribute("file.content_type", h.contentType)
-		if data, ok := result.([]byte); ok {
-			txn.AddAttribute("file.size_bytes", len(data))
-		}
+	// http.status_code is already set by tracing middleware
+	if file, ok := result.(*File); ok && txn != nil && file != nil {
+		txn.AddAttribute("file.name", file.Name)
+		txn.AddAttribute("file.content_type", file.ContentType)
+		txn.AddAttribute("file.size_bytes", len(file.Data))
 	}
 }
 
@@ -101,9 +115,11 @@ func handleRequest[Req validation.Validatable](
 	c echo.Context,
 	req Req,
 	handler func(c echo.Context, req Req) (interface{}, error),
+	load VersionLoader[Req],
 	responseHandler ResponseHandler,
 ) error {
 	start := time.Now()
+	req = newRequest(req)
 	method := c.Request().Method
 	path := c.Path()
 	route := path
@@ -117,25 +133,23 @@ func handleRequest[Req validation.Validatable](
 	}
 
 	// Get context-enhanced logger
-	loggerBuilder := middleware.GetLogger(c).With().
+	logger := middleware.GetLogger(c).With().
 		Str("operation", responseHandler.GetOperation()).
 		Str("method", method).
 		Str("path", path).
-		Str("route", route)
-	
-	// Add file-specific fields to logger if it's a file handler
-	if fileHandler, ok := responseHandler.(FileResponseHandler); ok {
-		loggerBuilder = loggerBuilder.
-			Str("filename", fileHandler.filename).
-			Str("content_type", fileHandler.contentType)
-	}
-	
-	logger := loggerBuilder.Logger()
+		Str("route", route).
+		Logger()
 
 	// user.id is already set by tracing middleware
 
 	logger.Info().Msg("handling request")
 
+	// Every write is attributed to a user in the audit trail, so none may run
+	// without one, even on a route registered without RequireAuth
+	if method != http.MethodGet && method != http.MethodHead && middleware.GetUserID(c) == "" {
+		return errs.NewUnauthorizedError("Unauthorized", false)
+	}
+
 	// Validation with observability
 	validationStart := time.Now()
 	if err := validation.BindAndValidate(c, req); err != nil {
@@ -164,6 +178,34 @@ func handleRequest[Req validation.Validatable](
 		Dur("validation_duration", validationDuration).
 		Msg("request validation successful")
 
+	// Writes made by the handler are attributed to the user and request in the audit trail
+	ctx := audit.WithActor(c.Request().Context(), audit.Actor{
+		UserID:    middleware.GetUserID(c),
+		RequestID: middleware.GetRequestID(c),
+	})
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	// The If-Match precondition is checked against the version the route's
+	// loader finds, and repositories only write that version. A header that
+	// would go unchecked is refused rather than ignored.
+	ifMatch := c.Request().Header.Get(etag.IfMatchHeader)
+	if ifMatch != "" && (method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete) {
+		if load == nil {
+			return etag.NotSupportedError()
+		}
+
+		ctx = etag.WithIfMatch(ctx, ifMatch)
+		c.SetRequest(c.Request().WithContext(ctx))
+		current, err := load(c, req)
+		if err != nil {
+			return err
+		}
+		if ctx, err = etag.Check(ctx, current); err != nil {
+			return err
+		}
+		c.SetRequest(c.Request().WithContext(ctx))
+	}
+
 	// Execute handler with observability
 	handlerStart := time.Now()
 	result, err := handler(c, req)
@@ -197,15 +239,47 @@ func handleRequest[Req validation.Validatable](
 		responseHandler.AddAttributes(txn, result)
 	}
 
-	logger.Info().
+	completed := logger.Info().
 		Dur("handler_duration", handlerDuration).
 		Dur("validation_duration", validationDuration).
-		Dur("total_duration", totalDuration).
-		Msg("request completed successfully")
+		Dur("total_duration", totalDuration)
+	// Files are only named once the handler has run
+	if file, ok := result.(*File); ok && file != nil {
+		completed = completed.
+			Str("filename", file.Name).
+			Str("content_type", file.ContentType)
+	}
+	completed.Msg("request completed successfully")
+
+	// Versioned resources are tagged, and a read of an unchanged version is answered with 304
+	if tagged, ok := result.(etag.Tagged); ok && !isNilPointer(result) {
+		tag := tagged.ETag()
+		c.Response().Header().Set(etag.Header, tag)
+
+		ifNoneMatch := c.Request().Header.Get(etag.IfNoneMatchHeader)
+		if (method == http.MethodGet || method == http.MethodHead) && ifNoneMatch != "" && etag.Matches(ifNoneMatch, tag) {
+			return c.NoContent(http.StatusNotModified)
+		}
+	}
 
 	return responseHandler.Handle(c, result)
 }
 
+func isNilPointer(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// newRequest returns a fresh zero value of the request type so bound fields
+// never leak between requests served by the same route
+func newRequest[Req validation.Validatable](req Req) Req {
+	t := reflect.TypeOf(req)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return req
+	}
+	return reflect.New(t.Elem()).Interface().(Req)
+}
+
 // Handle wraps a handler with validation, error handling, logging, metrics, and tracing
 func Handle[Req validation.Validatable, Res any](
 	h Handler,
@@ -216,26 +290,21 @@ func Handle[Req validation.Validatable, Res any](
 	return func(c echo.Context) error {
 		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
 			return handler(c, req)
-		}, JSONResponseHandler{status: status})
+		}, nil, JSONResponseHandler{status: status})
 	}
 }
 
+// HandleFile wraps a handler that returns a download with validation, error handling, logging, metrics, and tracing
 func HandleFile[Req validation.Validatable](
 	h Handler,
-	handler HandlerFunc[Req, []byte],
+	handler HandlerFunc[Req, *File],
 	status int,
 	req Req,
-	filename string,
-	contentType string,
 ) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
 			return handler(c, req)
-		}, FileResponseHandler{
-			status:      status,
-			filename:    filename,
-			contentType: contentType,
-		})
+		}, nil, FileResponseHandler{status: status})
 	}
 }
 
@@ -250,6 +319,22 @@ func HandleNoContent[Req validation.Validatable](
 		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
 			err := handler(c, req)
 			return nil, err
-		}, NoContentResponseHandler{status: status})
+		}, nil, NoContentResponseHandler{status: status})
+	}
+}
+
+// HandleVersioned wraps a handler like Handle, for a write that honours the
+// If-Match precondition against the resource load returns
+func HandleVersioned[Req validation.Validatable, Res any](
+	h Handler,
+	handler HandlerFunc[Req, Res],
+	load VersionLoader[Req],
+	status int,
+	req Req,
+) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
+			return handler(c, req)
+		}, load, JSONResponseHandler{status: status})
 	}
 }
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
+)
+
+type BillingHandler struct {
+	Handler
+	billingService *service.BillingService
+}
+
+func NewBillingHandler(s *server.Server, billingService *service.BillingService) *BillingHandler {
+	return &BillingHandler{
+		Handler:        NewHandler(s),
+		billingService: billingService,
+	}
+}
+
+// Get billing rates
+func (h *BillingHandler) GetBillingRates(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListBillingRatesQuery) (*model.PaginatedResponse[model.BillingRate], error) {
+			return h.billingService.GetBillingRates(c.Request().Context(), req.Page, req.Limit, req.Payer, req.ServiceCode)
+		},
+		http.StatusOK,
+		&validation.ListBillingRatesQuery{},
+	)(c)
+}
+
+// Get billing rate by ID
+func (h *BillingHandler) GetBillingRateById(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.BillingRateIDParam) (*model.BillingRate, error) {
+			return h.billingService.GetBillingRateByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.BillingRateIDParam{},
+	)(c)
+}
+
+// Create a billing rate
+func (h *BillingHandler) CreateBillingRate(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CreateBillingRateRequest) (*model.BillingRate, error) {
+			// Dates were checked by validation
+			effectiveFrom, _ := time.Parse(validation.DateLayout, req.EffectiveFrom)
+			var effectiveTo *time.Time
+			if req.EffectiveTo != nil {
+				if parsed, err := time.Parse(validation.DateLayout, *req.EffectiveTo); err == nil {
+					effectiveTo = &parsed
+				}
+			}
+
+			return h.billingService.CreateBillingRate(c.Request().Context(), model.BillingRateCreate{
+				Payer:         req.Payer,
+				ServiceCode:   req.ServiceCode,
+				CaregiverID:   req.CaregiverID,
+				UnitMinutes:   req.UnitMinutes,
+				Rounding:      req.Rounding,
+				UnitRateCents: req.UnitRateCents,
+				EffectiveFrom: effectiveFrom,
+				EffectiveTo:   effectiveTo,
+			})
+		},
+		http.StatusCreated,
+		&validation.CreateBillingRateRequest{},
+	)(c)
+}
+
+// Get the latest billing calculation of a visit
+func (h *BillingHandler) GetVisitBilling(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.VisitIDParam) (*model.VisitBilling, error) {
+			return h.billingService.GetVisitBilling(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.VisitIDParam{},
+	)(c)
+}
+
+// Bill a completed visit again
+func (h *BillingHandler) BillVisit(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.VisitIDParam) (*model.VisitBilling, error) {
+			return h.billingService.BillVisit(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.VisitIDParam{},
+	)(c)
+}
+
+// Bill the completed visits in a service date range again
+func (h *BillingHandler) BillVisits(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.BillVisitsRequest) (*model.VisitBillingRun, error) {
+			from, _ := time.Parse(validation.DateLayout, req.From)
+			to, _ := time.Parse(validation.DateLayout, req.To)
+			return h.billingService.BillVisits(c.Request().Context(), from, to)
+		},
+		http.StatusOK,
+		&validation.BillVisitsRequest{},
+	)(c)
+}
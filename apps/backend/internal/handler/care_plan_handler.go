@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
+)
+
+type CarePlanHandler struct {
+	Handler
+	carePlanService *service.CarePlanService
+}
+
+func NewCarePlanHandler(s *server.Server, carePlanService *service.CarePlanService) *CarePlanHandler {
+	return &CarePlanHandler{
+		Handler:         NewHandler(s),
+		carePlanService: carePlanService,
+	}
+}
+
+// Get all care plans for a client
+func (h *CarePlanHandler) GetClientCarePlans(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ClientIDParam) ([]model.CarePlan, error) {
+			return h.carePlanService.GetCarePlansByClient(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.ClientIDParam{},
+	)(c)
+}
+
+// Get the client's active care plan
+func (h *CarePlanHandler) GetActiveCarePlan(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ClientIDParam) (*model.CarePlan, error) {
+			return h.carePlanService.GetActiveCarePlan(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.ClientIDParam{},
+	)(c)
+}
+
+// Create a care plan for a client
+func (h *CarePlanHandler) CreateCarePlan(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CreateCarePlanRequest) (*model.CarePlan, error) {
+			tasks := make([]model.CarePlanTaskCreate, len(req.Tasks))
+			for i, task := range req.Tasks {
+				tasks[i] = model.CarePlanTaskCreate{
+					Name:        task.Name,
+					Description: task.Description,
+					IsRequired:  task.IsRequired == nil || *task.IsRequired,
+				}
+			}
+
+			return h.carePlanService.CreateCarePlan(c.Request().Context(), model.CarePlanCreate{
+				ClientID: req.ClientID,
+				Name:     req.Name,
+				Tasks:    tasks,
+			})
+		},
+		http.StatusCreated,
+		&validation.CreateCarePlanRequest{},
+	)(c)
+}
+
+// Get care plan by ID
+func (h *CarePlanHandler) GetCarePlanById(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CarePlanIDParam) (*model.CarePlan, error) {
+			return h.carePlanService.GetCarePlanByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.CarePlanIDParam{},
+	)(c)
+}
+
+// Make a care plan the client's active plan
+func (h *CarePlanHandler) ActivateCarePlan(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CarePlanIDParam) (*model.CarePlan, error) {
+			return h.carePlanService.ActivateCarePlan(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.CarePlanIDParam{},
+	)(c)
+}
+
+// Deactivate a care plan
+func (h *CarePlanHandler) DeactivateCarePlan(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CarePlanIDParam) (*model.CarePlan, error) {
+			return h.carePlanService.DeactivateCarePlan(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.CarePlanIDParam{},
+	)(c)
+}
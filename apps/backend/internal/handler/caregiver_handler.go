@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/etag"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/middleware"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
+)
+
+type CaregiverHandler struct {
+	Handler
+	caregiverService *service.CaregiverService
+	scheduleService  *service.ScheduleService
+}
+
+func NewCaregiverHandler(s *server.Server, caregiverService *service.CaregiverService, scheduleService *service.ScheduleService) *CaregiverHandler {
+	return &CaregiverHandler{
+		Handler:          NewHandler(s),
+		caregiverService: caregiverService,
+		scheduleService:  scheduleService,
+	}
+}
+
+// Get all caregivers with pagination
+func (h *CaregiverHandler) GetCaregivers(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.PaginationQuery) (*model.PaginatedResponse[model.Caregiver], error) {
+			return h.caregiverService.GetCaregivers(c.Request().Context(), req.Page, req.Limit)
+		},
+		http.StatusOK,
+		&validation.PaginationQuery{},
+	)(c)
+}
+
+// Get caregiver by ID
+func (h *CaregiverHandler) GetCaregiverById(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CaregiverIDParam) (*model.Caregiver, error) {
+			return h.caregiverService.GetCaregiverByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.CaregiverIDParam{},
+	)(c)
+}
+
+// Create caregiver
+func (h *CaregiverHandler) CreateCaregiver(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CreateCaregiverRequest) (*model.Caregiver, error) {
+			return h.caregiverService.CreateCaregiver(c.Request().Context(), model.CaregiverCreate{
+				UserID:    req.UserID,
+				FirstName: req.FirstName,
+				LastName:  req.LastName,
+				Email:     req.Email,
+				Phone:     req.Phone,
+			})
+		},
+		http.StatusCreated,
+		&validation.CreateCaregiverRequest{},
+	)(c)
+}
+
+// Assign a caregiver to an unassigned schedule
+func (h *CaregiverHandler) AssignCaregiver(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.AssignCaregiverRequest) (*model.Schedule, error) {
+			return h.caregiverService.AssignCaregiver(c.Request().Context(), req.ScheduleID, req.CaregiverID)
+		},
+		http.StatusOK,
+		&validation.AssignCaregiverRequest{},
+	)(c)
+}
+
+// Reassign a schedule to a different caregiver
+func (h *CaregiverHandler) ReassignCaregiver(c echo.Context) error {
+	return HandleVersioned(
+		h.Handler,
+		func(c echo.Context, req *validation.AssignCaregiverRequest) (*model.Schedule, error) {
+			return h.caregiverService.ReassignCaregiver(c.Request().Context(), req.ScheduleID, req.CaregiverID)
+		},
+		func(c echo.Context, req *validation.AssignCaregiverRequest) (etag.Tagged, error) {
+			return h.scheduleService.GetScheduleByID(c.Request().Context(), req.ScheduleID)
+		},
+		http.StatusOK,
+		&validation.AssignCaregiverRequest{},
+	)(c)
+}
+
+// Get the authenticated caregiver's own schedules
+func (h *CaregiverHandler) GetMySchedules(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListMySchedulesQuery) (*model.PaginatedResponse[model.Schedule], error) {
+			return h.caregiverService.GetMySchedules(c.Request().Context(), middleware.GetUserID(c), req.Page, req.Limit, req.Status)
+		},
+		http.StatusOK,
+		&validation.ListMySchedulesQuery{},
+	)(c)
+}
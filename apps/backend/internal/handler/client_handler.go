@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/etag"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
+)
+
+type ClientHandler struct {
+	Handler
+	clientService *service.ClientService
+}
+
+func NewClientHandler(s *server.Server, clientService *service.ClientService) *ClientHandler {
+	return &ClientHandler{
+		Handler:       NewHandler(s),
+		clientService: clientService,
+	}
+}
+
+// Get all clients with pagination and filtering
+func (h *ClientHandler) GetClients(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListClientsQuery) (*model.PaginatedResponse[model.Client], error) {
+			return h.clientService.GetClients(c.Request().Context(), req.Page, req.Limit, req.Status, req.Search)
+		},
+		http.StatusOK,
+		&validation.ListClientsQuery{},
+	)(c)
+}
+
+// Get client by ID
+func (h *ClientHandler) GetClientById(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ClientIDParam) (*model.Client, error) {
+			return h.clientService.GetClientByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.ClientIDParam{},
+	)(c)
+}
+
+// Create client
+func (h *ClientHandler) CreateClient(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CreateClientRequest) (*model.Client, error) {
+			return h.clientService.CreateClient(c.Request().Context(), clientFields(&req.ClientRequest))
+		},
+		http.StatusCreated,
+		&validation.CreateClientRequest{},
+	)(c)
+}
+
+// Update client
+func (h *ClientHandler) UpdateClient(c echo.Context) error {
+	return HandleVersioned(
+		h.Handler,
+		func(c echo.Context, req *validation.UpdateClientRequest) (*model.Client, error) {
+			return h.clientService.UpdateClient(c.Request().Context(), req.ID, model.ClientUpdate{
+				ClientCreate: clientFields(&req.ClientRequest),
+				Status:       req.Status,
+			})
+		},
+		func(c echo.Context, req *validation.UpdateClientRequest) (etag.Tagged, error) {
+			return h.clientService.GetClientByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.UpdateClientRequest{},
+	)(c)
+}
+
+// Delete client
+func (h *ClientHandler) DeleteClient(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, req *validation.ClientIDParam) error {
+			return h.clientService.DeleteClient(c.Request().Context(), req.ID)
+		},
+		http.StatusNoContent,
+		&validation.ClientIDParam{},
+	)(c)
+}
+
+// clientFields maps a validated client request onto the model
+func clientFields(req *validation.ClientRequest) model.ClientCreate {
+	var dateOfBirth *time.Time
+	if req.DateOfBirth != nil {
+		// The format was already checked by validation
+		if parsed, err := time.Parse(validation.DateLayout, *req.DateOfBirth); err == nil {
+			dateOfBirth = &parsed
+		}
+	}
+
+	contacts := make([]model.EmergencyContact, 0, len(req.EmergencyContacts))
+	for _, contact := range req.EmergencyContacts {
+		contacts = append(contacts, model.EmergencyContact{
+			Name:         contact.Name,
+			Relationship: contact.Relationship,
+			Phone:        contact.Phone,
+		})
+	}
+
+	return model.ClientCreate{
+		FirstName:         req.FirstName,
+		LastName:          req.LastName,
+		DateOfBirth:       dateOfBirth,
+		Gender:            req.Gender,
+		MedicaidID:        req.MedicaidID,
+		Payer:             req.Payer,
+		AddressLine1:      req.AddressLine1,
+		AddressLine2:      req.AddressLine2,
+		City:              req.City,
+		State:             req.State,
+		PostalCode:        req.PostalCode,
+		Latitude:          req.Latitude,
+		Longitude:         req.Longitude,
+		Phone:             req.Phone,
+		EmergencyContacts: contacts,
+	}
+}
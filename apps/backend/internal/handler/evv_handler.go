@@ -2,144 +2,379 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/etag"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/middleware"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
 )
 
 type EVVHandler struct {
+	Handler
 	scheduleService *service.ScheduleService
 	visitService    *service.VisitService
 	taskService     *service.TaskService
+	seriesService   *service.ScheduleSeriesService
 }
 
-// Mock data generator methods - simplified
-func (h *EVVHandler) getMockSchedulesResponse(c echo.Context) error {
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"schedules": []map[string]interface{}{
-			{
-				"id":          "1",
-				"clientName":  "John Smith",
-				"shiftTime":   "09:00 - 12:00",
-				"location":    "123 Main St, Anytown",
-				"status":      "upcoming",
-				"visitId":     nil,
-				"visit":       nil,
-				"tasks":       []map[string]interface{}{},
-			},
-		},
-	})
-}
-
-func NewEVVHandler(scheduleService *service.ScheduleService, visitService *service.VisitService, taskService *service.TaskService) *EVVHandler {
+func NewEVVHandler(s *server.Server, scheduleService *service.ScheduleService, visitService *service.VisitService, taskService *service.TaskService, seriesService *service.ScheduleSeriesService) *EVVHandler {
 	return &EVVHandler{
+		Handler:         NewHandler(s),
 		scheduleService: scheduleService,
 		visitService:    visitService,
 		taskService:     taskService,
+		seriesService:   seriesService,
 	}
 }
 
-// Get all schedules with pagination and filtering
+// Get schedules with cursor pagination and filtering
 func (h *EVVHandler) GetSchedules(c echo.Context) error {
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page < 1 {
-		page = 1
-	}
-
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit < 1 || limit > 100 {
-		limit = 10
-	}
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListSchedulesQuery) (*model.CursorPaginatedResponse[model.Schedule], error) {
+			return h.scheduleService.GetSchedules(c.Request().Context(), req.Page(), req.Listing)
+		},
+		http.StatusOK,
+		&validation.ListSchedulesQuery{},
+	)(c)
+}
 
-	_ = c.QueryParam("status") // Keep parameter for future use
+// Get visits with cursor pagination
+func (h *EVVHandler) GetVisits(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListVisitsQuery) (*model.CursorPaginatedResponse[model.Visit], error) {
+			return h.visitService.GetVisits(c.Request().Context(), req.Page(), req.Listing)
+		},
+		http.StatusOK,
+		&validation.ListVisitsQuery{},
+	)(c)
+}
 
-	// Return mock data for now
-	return h.getMockSchedulesResponse(c)
+// Get tasks with cursor pagination
+func (h *EVVHandler) GetTasks(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListTasksQuery) (*model.CursorPaginatedResponse[model.Task], error) {
+			return h.taskService.GetTasks(c.Request().Context(), req.Page(), req.Listing)
+		},
+		http.StatusOK,
+		&validation.ListTasksQuery{},
+	)(c)
 }
 
 // Get today's schedules
 func (h *EVVHandler) GetTodaySchedules(c echo.Context) error {
-	// Return mock data for now
-	todaySchedules := map[string]interface{}{
-		"data":  []map[string]interface{}{},
-		"total": 0,
-	}
-
-	return c.JSON(http.StatusOK, todaySchedules)
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.EmptyRequest) ([]model.Schedule, error) {
+			return h.scheduleService.GetTodaySchedules(c.Request().Context())
+		},
+		http.StatusOK,
+		&validation.EmptyRequest{},
+	)(c)
 }
 
 // Get schedule by ID
 func (h *EVVHandler) GetScheduleById(c echo.Context) error {
-	id := c.Param("id")
-	
-	// Return mock data for now
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"id":         id,
-		"clientName": "John Smith",
-		"shiftTime":  "09:00 - 12:00",
-		"location":   "123 Main St, Anytown",
-		"status":     "upcoming",
-		"visit":      nil,
-		"tasks":      []map[string]interface{}{},
-	})
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ScheduleIDParam) (*model.ScheduleWithTasks, error) {
+			return h.scheduleService.GetScheduleByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.ScheduleIDParam{},
+	)(c)
 }
 
 // Create schedule
 func (h *EVVHandler) CreateSchedule(c echo.Context) error {
-	// Return success response for now
-	return c.JSON(http.StatusCreated, map[string]interface{}{
-		"message": "Schedule created successfully",
-		"id":      "new-schedule-id",
-	})
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CreateScheduleRequest) (*model.ScheduleWithTasks, error) {
+			return h.scheduleService.CreateSchedule(c.Request().Context(), model.ScheduleCreate{
+				ClientID:             req.ClientID,
+				Location:             req.Location,
+				ServiceCode:          req.ServiceCode,
+				ScheduledStart:       req.ScheduledStart,
+				ScheduledEnd:         req.ScheduledEnd,
+				TimeZone:             req.TimeZone,
+				Latitude:             req.Latitude,
+				Longitude:            req.Longitude,
+				GeofenceRadiusMeters: req.GeofenceRadiusMeters,
+			})
+		},
+		http.StatusCreated,
+		&validation.CreateScheduleRequest{},
+	)(c)
+}
+
+// Update schedule; scope=future also edits every later occurrence of its series
+func (h *EVVHandler) UpdateSchedule(c echo.Context) error {
+	return HandleVersioned(
+		h.Handler,
+		func(c echo.Context, req *validation.UpdateScheduleRequest) (*model.ScheduleWithTasks, error) {
+			update := model.ScheduleUpdate{
+				ClientID:       req.ClientID,
+				Location:       req.Location,
+				ServiceCode:    req.ServiceCode,
+				ScheduledStart: req.ScheduledStart,
+				ScheduledEnd:   req.ScheduledEnd,
+				TimeZone:       req.TimeZone,
+			}
+
+			if req.Scope == validation.ScheduleEditScopeFuture {
+				if _, err := h.seriesService.UpdateFutureOccurrences(c.Request().Context(), req.ID, update); err != nil {
+					return nil, err
+				}
+			} else if _, err := h.scheduleService.UpdateSchedule(c.Request().Context(), req.ID, update); err != nil {
+				return nil, err
+			}
+
+			return h.scheduleService.GetScheduleByID(c.Request().Context(), req.ID)
+		},
+		func(c echo.Context, req *validation.UpdateScheduleRequest) (etag.Tagged, error) {
+			return h.scheduleService.GetScheduleByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.UpdateScheduleRequest{},
+	)(c)
 }
 
 // Update schedule status
 func (h *EVVHandler) UpdateScheduleStatus(c echo.Context) error {
-	id := c.Param("id")
-	
-	// Return success response for now
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"message": "Schedule status updated successfully",
-		"id":      id,
-	})
+	return HandleVersioned(
+		h.Handler,
+		func(c echo.Context, req *validation.UpdateScheduleStatusRequest) (*model.ScheduleWithTasks, error) {
+			if err := h.scheduleService.UpdateScheduleStatus(c.Request().Context(), req.ID, req.Status); err != nil {
+				return nil, err
+			}
+			return h.scheduleService.GetScheduleByID(c.Request().Context(), req.ID)
+		},
+		func(c echo.Context, req *validation.UpdateScheduleStatusRequest) (etag.Tagged, error) {
+			return h.scheduleService.GetScheduleByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.UpdateScheduleStatusRequest{},
+	)(c)
 }
 
 // Search schedules
 func (h *EVVHandler) SearchSchedules(c echo.Context) error {
-	// Return mock data for now
-	return h.getMockSchedulesResponse(c)
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.SearchQuery) (*model.PaginatedResponse[model.ScheduleSearchResult], error) {
+			filter := model.ScheduleSearchFilter{
+				Query:  req.Query,
+				Status: req.Status,
+			}
+			if req.From != "" {
+				from, _ := time.Parse(time.RFC3339, req.From)
+				filter.From = &from
+			}
+			if req.To != "" {
+				to, _ := time.Parse(time.RFC3339, req.To)
+				filter.To = &to
+			}
+
+			return h.scheduleService.SearchSchedules(c.Request().Context(), filter, req.Page, req.Limit)
+		},
+		http.StatusOK,
+		&validation.SearchQuery{},
+	)(c)
+}
+
+// Get schedule stats
+func (h *EVVHandler) GetScheduleStats(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.EmptyRequest) (*model.ScheduleStats, error) {
+			return h.scheduleService.GetScheduleStats(c.Request().Context())
+		},
+		http.StatusOK,
+		&validation.EmptyRequest{},
+	)(c)
 }
 
-// Get mock stats
-func (h *EVVHandler) GetMockStats(c echo.Context) error {
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"totalSchedules": 10,
-		"completedTasks": 5,
-		"pendingTasks":   3,
-		"upcomingVisits": 2,
-	})
+// Get a schedule's visit and task completion analytics
+func (h *EVVHandler) GetScheduleAnalytics(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ScheduleIDParam) (map[string]interface{}, error) {
+			return h.scheduleService.GetScheduleAnalytics(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.ScheduleIDParam{},
+	)(c)
 }
 
-// Get task stats
+// Clock in: start the visit for a schedule
+func (h *EVVHandler) StartVisit(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.StartVisitRequest) (*model.Visit, error) {
+			return h.visitService.StartVisit(c.Request().Context(), req.ScheduleID, req.StartTime, req.StartLat, req.StartLong)
+		},
+		http.StatusCreated,
+		&validation.StartVisitRequest{},
+	)(c)
+}
+
+// Clock out: end the visit for a schedule
+func (h *EVVHandler) EndVisit(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.EndVisitRequest) (*model.Visit, error) {
+			return h.visitService.EndVisit(c.Request().Context(), req.ScheduleID, req.EndTime, req.EndLat, req.EndLong)
+		},
+		http.StatusOK,
+		&validation.EndVisitRequest{},
+	)(c)
+}
+
+// Enter a visit after the fact for a schedule nobody clocked in to
+func (h *EVVHandler) CreateManualVisit(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CreateManualVisitRequest) (*model.Visit, error) {
+			return h.visitService.CreateManualVisit(c.Request().Context(), req.ScheduleID, model.ManualVisitEntry{
+				StartTime:      req.StartTime,
+				EndTime:        req.EndTime,
+				StartLatitude:  req.StartLat,
+				StartLongitude: req.StartLong,
+				EndLatitude:    req.EndLat,
+				EndLongitude:   req.EndLong,
+				ReasonCode:     req.ReasonCode,
+				Reason:         req.Reason,
+			}, middleware.GetUserID(c))
+		},
+		http.StatusCreated,
+		&validation.CreateManualVisitRequest{},
+	)(c)
+}
+
+// Correct a visit's clock times or coordinates
+func (h *EVVHandler) CorrectVisit(c echo.Context) error {
+	return HandleVersioned(
+		h.Handler,
+		func(c echo.Context, req *validation.CorrectVisitRequest) (*model.Visit, error) {
+			return h.visitService.CorrectVisit(c.Request().Context(), req.ID, model.VisitCorrectionUpdate{
+				VisitClockData: model.VisitClockData{
+					StartTime:      req.StartTime,
+					EndTime:        req.EndTime,
+					StartLatitude:  req.StartLat,
+					StartLongitude: req.StartLong,
+					EndLatitude:    req.EndLat,
+					EndLongitude:   req.EndLong,
+				},
+				ReasonCode: req.ReasonCode,
+				Reason:     req.Reason,
+			}, middleware.GetUserID(c))
+		},
+		func(c echo.Context, req *validation.CorrectVisitRequest) (etag.Tagged, error) {
+			return h.visitService.GetVisitByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.CorrectVisitRequest{},
+	)(c)
+}
+
+// Get a visit's manual entry and corrections with original and corrected values
+func (h *EVVHandler) GetVisitCorrections(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.VisitIDParam) ([]model.VisitCorrection, error) {
+			return h.visitService.GetVisitCorrections(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.VisitIDParam{},
+	)(c)
+}
+
+// Get the visit recorded for a schedule
+func (h *EVVHandler) GetVisit(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ScheduleIDParam) (*model.Visit, error) {
+			return h.visitService.GetVisitSummary(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.ScheduleIDParam{},
+	)(c)
+}
+
+// Get the tasks of a schedule
+func (h *EVVHandler) GetScheduleTasks(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ScheduleIDParam) ([]model.Task, error) {
+			return h.taskService.GetScheduleTasks(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.ScheduleIDParam{},
+	)(c)
+}
+
+// Add a task to a schedule
+func (h *EVVHandler) CreateTask(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CreateTaskRequest) (*model.Task, error) {
+			description := ""
+			if req.Description != nil {
+				description = *req.Description
+			}
+			return h.taskService.CreateTask(c.Request().Context(), req.ScheduleID, req.Name, description)
+		},
+		http.StatusCreated,
+		&validation.CreateTaskRequest{},
+	)(c)
+}
+
+// Get task stats across all schedules
 func (h *EVVHandler) GetTaskStats(c echo.Context) error {
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"totalTasks":      25,
-		"completedTasks":  15,
-		"pendingTasks":    8,
-		"notCompletedTasks": 2,
-	})
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.EmptyRequest) (*model.TaskStats, error) {
+			return h.taskService.GetOverallTaskStats(c.Request().Context())
+		},
+		http.StatusOK,
+		&validation.EmptyRequest{},
+	)(c)
 }
 
 // Update task status
 func (h *EVVHandler) UpdateTaskStatus(c echo.Context) error {
-	scheduleId := c.Param("scheduleId")
-	taskId := c.Param("taskId")
-
-	// Return success response for now
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"message": "Task status updated successfully",
-		"scheduleId": scheduleId,
-		"taskId": taskId,
-	})
+	return HandleVersioned(
+		h.Handler,
+		func(c echo.Context, req *validation.UpdateTaskStatusRequest) (*model.Task, error) {
+			if _, err := h.scheduleTask(c, req); err != nil {
+				return nil, err
+			}
+
+			return h.taskService.UpdateTaskStatus(c.Request().Context(), req.TaskID, req.Status, req.Reason)
+		},
+		func(c echo.Context, req *validation.UpdateTaskStatusRequest) (etag.Tagged, error) {
+			return h.scheduleTask(c, req)
+		},
+		http.StatusOK,
+		&validation.UpdateTaskStatusRequest{},
+	)(c)
+}
+
+// scheduleTask gets the task a request addresses, which has to belong to its schedule
+func (h *EVVHandler) scheduleTask(c echo.Context, req *validation.UpdateTaskStatusRequest) (*model.Task, error) {
+	task, err := h.taskService.GetTaskByID(c.Request().Context(), req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.ScheduleID != req.ScheduleID {
+		return nil, errs.NewNotFoundError("Task not found", true, nil)
+	}
+	return task, nil
 }
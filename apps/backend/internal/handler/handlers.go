@@ -21,6 +21,18 @@ type Handlers struct {
 	Health    *HealthHandler
 	OpenAPI   *OpenAPIHandler
 	EVV       *EVVHandler
+	Caregiver *CaregiverHandler
+	Client    *ClientHandler
+	CarePlan  *CarePlanHandler
+	ScheduleSeries *ScheduleSeriesHandler
+	VisitException *VisitExceptionHandler
+	Audit          *AuditHandler
+	VisitCompliance *VisitComplianceHandler
+	Aggregator      *AggregatorHandler
+	Sync            *SyncHandler
+	Report          *ReportHandler
+	Timesheet       *TimesheetHandler
+	Billing         *BillingHandler
 	Swagger   *SwaggerHandler
 	Mock      *MockAPIHandler
 }
@@ -29,7 +41,19 @@ func NewHandlers(s *server.Server, services *service.Services) *Handlers {
 	return &Handlers{
 		Health:    NewHealthHandler(s),
 		OpenAPI:   NewOpenAPIHandler(s),
-		EVV:       NewEVVHandler(services.ScheduleService, services.VisitService, services.TaskService),
+		EVV:       NewEVVHandler(s, services.ScheduleService, services.VisitService, services.TaskService, services.ScheduleSeriesService),
+		Caregiver: NewCaregiverHandler(s, services.CaregiverService, services.ScheduleService),
+		Client:    NewClientHandler(s, services.ClientService),
+		CarePlan:  NewCarePlanHandler(s, services.CarePlanService),
+		ScheduleSeries: NewScheduleSeriesHandler(s, services.ScheduleSeriesService),
+		VisitException: NewVisitExceptionHandler(s, services.VisitExceptionService),
+		Audit:          NewAuditHandler(s, services.AuditService),
+		VisitCompliance: NewVisitComplianceHandler(s, services.VisitComplianceService),
+		Aggregator:      NewAggregatorHandler(s, services.AggregatorService),
+		Sync:            NewSyncHandler(s, services.SyncService),
+		Report:          NewReportHandler(s, services.ReportService),
+		Timesheet:       NewTimesheetHandler(s, services.TimesheetService),
+		Billing:         NewBillingHandler(s, services.BillingService),
 		Swagger:   NewSwaggerHandler(),
 		Mock: &MockAPIHandler{
 			GetMockSchedules:    GetMockSchedules,
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/report"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
+)
+
+type ReportHandler struct {
+	Handler
+	reportService *service.ReportService
+}
+
+func NewReportHandler(s *server.Server, reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		Handler:       NewHandler(s),
+		reportService: reportService,
+	}
+}
+
+// Download the visit log of a period
+func (h *ReportHandler) GetVisitLogReport(c echo.Context) error {
+	return h.handleReport(c, "visit-log", h.reportService.VisitLog)
+}
+
+// Download the task completion of the schedules in a period
+func (h *ReportHandler) GetTaskCompletionReport(c echo.Context) error {
+	return h.handleReport(c, "task-completion", h.reportService.TaskCompletion)
+}
+
+// Download the visit exceptions detected in a period
+func (h *ReportHandler) GetExceptionsReport(c echo.Context) error {
+	return h.handleReport(c, "visit-exceptions", h.reportService.Exceptions)
+}
+
+// handleReport renders a report in the format chosen by ?format= or the Accept header
+func (h *ReportHandler) handleReport(c echo.Context, name string, build func(ctx context.Context, from, to time.Time) (*report.Table, error)) error {
+	return HandleFile(
+		h.Handler,
+		func(c echo.Context, req *validation.ReportQuery) (*File, error) {
+			format, err := report.Negotiate(req.Format, c.Request().Header.Get(echo.HeaderAccept))
+			if err != nil {
+				return nil, errs.NewBadRequestError(err.Error(), true, nil, nil, nil)
+			}
+
+			from, _ := time.Parse(time.RFC3339, req.From)
+			to, _ := time.Parse(time.RFC3339, req.To)
+			table, err := build(c.Request().Context(), from, to)
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := report.Render(format, table)
+			if err != nil {
+				return nil, err
+			}
+
+			return &File{
+				Name:        report.Filename(name+"-"+from.Format("20060102")+"-"+to.Format("20060102"), format),
+				ContentType: format.ContentType(),
+				Data:        data,
+			}, nil
+		},
+		http.StatusOK,
+		&validation.ReportQuery{},
+	)(c)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
+)
+
+type ScheduleSeriesHandler struct {
+	Handler
+	seriesService *service.ScheduleSeriesService
+}
+
+func NewScheduleSeriesHandler(s *server.Server, seriesService *service.ScheduleSeriesService) *ScheduleSeriesHandler {
+	return &ScheduleSeriesHandler{
+		Handler:       NewHandler(s),
+		seriesService: seriesService,
+	}
+}
+
+// Create a recurring schedule series
+func (h *ScheduleSeriesHandler) CreateScheduleSeries(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CreateScheduleSeriesRequest) (*model.ScheduleSeries, error) {
+			// Date formats were already checked by validation
+			startDate, _ := time.Parse(validation.DateLayout, req.StartDate)
+
+			var endDate *time.Time
+			if req.EndDate != nil {
+				if parsed, err := time.Parse(validation.DateLayout, *req.EndDate); err == nil {
+					endDate = &parsed
+				}
+			}
+
+			exceptionDates := make([]time.Time, 0, len(req.ExceptionDates))
+			for _, date := range req.ExceptionDates {
+				if parsed, err := time.Parse(validation.DateLayout, date); err == nil {
+					exceptionDates = append(exceptionDates, parsed)
+				}
+			}
+
+			return h.seriesService.CreateScheduleSeries(c.Request().Context(), model.ScheduleSeriesCreate{
+				ClientID:             req.ClientID,
+				CaregiverID:          req.CaregiverID,
+				RRule:                req.RRule,
+				StartDate:            startDate,
+				StartTime:            req.StartTime,
+				DurationMinutes:      req.DurationMinutes,
+				TimeZone:             req.TimeZone,
+				EndDate:              endDate,
+				Count:                req.Count,
+				ExceptionDates:       exceptionDates,
+				Location:             req.Location,
+				ServiceCode:          req.ServiceCode,
+				Latitude:             req.Latitude,
+				Longitude:            req.Longitude,
+				GeofenceRadiusMeters: req.GeofenceRadiusMeters,
+			})
+		},
+		http.StatusCreated,
+		&validation.CreateScheduleSeriesRequest{},
+	)(c)
+}
+
+// Get schedule series by ID
+func (h *ScheduleSeriesHandler) GetScheduleSeriesById(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ScheduleSeriesIDParam) (*model.ScheduleSeries, error) {
+			return h.seriesService.GetScheduleSeriesByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.ScheduleSeriesIDParam{},
+	)(c)
+}
+
+// Get the schedules generated from a series
+func (h *ScheduleSeriesHandler) GetScheduleSeriesSchedules(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ScheduleSeriesIDParam) ([]model.Schedule, error) {
+			return h.seriesService.GetScheduleSeriesSchedules(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.ScheduleSeriesIDParam{},
+	)(c)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/middleware"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
+)
+
+type SyncHandler struct {
+	Handler
+	syncService *service.SyncService
+}
+
+func NewSyncHandler(s *server.Server, syncService *service.SyncService) *SyncHandler {
+	return &SyncHandler{
+		Handler:     NewHandler(s),
+		syncService: syncService,
+	}
+}
+
+// Apply a batch of clock and task events recorded offline on the user's device
+func (h *SyncHandler) SyncEvents(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.SyncEventsRequest) (*model.SyncBatchResult, error) {
+			events := make([]model.SyncEvent, len(req.Events))
+			for i, event := range req.Events {
+				events[i] = model.SyncEvent{
+					ClientEventID: event.ClientEventID,
+					Type:          event.Type,
+					DeviceTime:    event.DeviceTime,
+					ScheduleID:    event.ScheduleID,
+					TaskID:        event.TaskID,
+					Latitude:      event.Latitude,
+					Longitude:     event.Longitude,
+					TaskStatus:    event.Status,
+					Reason:        event.Reason,
+				}
+			}
+
+			return h.syncService.SyncEvents(c.Request().Context(), middleware.GetUserID(c), req.SentAt, events)
+		},
+		http.StatusOK,
+		&validation.SyncEventsRequest{},
+	)(c)
+}
+
+// Get synced events filtered by user, outcome and flag
+func (h *SyncHandler) GetSyncEvents(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListSyncEventsQuery) (*model.PaginatedResponse[model.SyncEvent], error) {
+			return h.syncService.GetSyncEvents(c.Request().Context(), model.SyncEventFilter{
+				UserID: req.UserID,
+				Status: req.Status,
+				Flag:   req.Flag,
+			}, req.Page, req.Limit)
+		},
+		http.StatusOK,
+		&validation.ListSyncEventsQuery{},
+	)(c)
+}
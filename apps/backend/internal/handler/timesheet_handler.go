@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/report"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
+)
+
+type TimesheetHandler struct {
+	Handler
+	timesheetService *service.TimesheetService
+}
+
+func NewTimesheetHandler(s *server.Server, timesheetService *service.TimesheetService) *TimesheetHandler {
+	return &TimesheetHandler{
+		Handler:          NewHandler(s),
+		timesheetService: timesheetService,
+	}
+}
+
+// Get caregiver timesheets of a pay period
+func (h *TimesheetHandler) GetTimesheets(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.TimesheetQuery) (*model.TimesheetReport, error) {
+			return h.timesheetService.GetTimesheets(c.Request().Context(), req.Date, req.CaregiverID)
+		},
+		http.StatusOK,
+		&validation.TimesheetQuery{},
+	)(c)
+}
+
+// Download caregiver timesheets of a pay period as CSV
+func (h *TimesheetHandler) ExportTimesheets(c echo.Context) error {
+	return HandleFile(
+		h.Handler,
+		func(c echo.Context, req *validation.TimesheetQuery) (*File, error) {
+			timesheets, err := h.timesheetService.GetTimesheets(c.Request().Context(), req.Date, req.CaregiverID)
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := report.Render(report.CSV, h.timesheetService.TimesheetTable(timesheets))
+			if err != nil {
+				return nil, err
+			}
+
+			start, end := h.timesheetService.PeriodDates(timesheets.Period)
+			return &File{
+				Name:        report.Filename("timesheets-"+strings.ReplaceAll(start, "-", "")+"-"+strings.ReplaceAll(end, "-", ""), report.CSV),
+				ContentType: report.CSV.ContentType(),
+				Data:        data,
+			}, nil
+		},
+		http.StatusOK,
+		&validation.TimesheetQuery{},
+	)(c)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
+)
+
+type VisitComplianceHandler struct {
+	Handler
+	complianceService *service.VisitComplianceService
+}
+
+func NewVisitComplianceHandler(s *server.Server, complianceService *service.VisitComplianceService) *VisitComplianceHandler {
+	return &VisitComplianceHandler{
+		Handler:           NewHandler(s),
+		complianceService: complianceService,
+	}
+}
+
+// Get non-compliant visits in a service date range
+func (h *VisitComplianceHandler) GetNonCompliantVisits(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListNonCompliantVisitsQuery) (*model.PaginatedResponse[model.VisitCompliance], error) {
+			from, _ := time.Parse(validation.DateLayout, req.From)
+			to, _ := time.Parse(validation.DateLayout, req.To)
+			return h.complianceService.GetNonCompliantVisits(c.Request().Context(), from, to, req.Page, req.Limit)
+		},
+		http.StatusOK,
+		&validation.ListNonCompliantVisitsQuery{},
+	)(c)
+}
+
+// Check the completed visits in a service date range again
+func (h *VisitComplianceHandler) CheckVisits(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CheckVisitComplianceRequest) (*model.VisitComplianceRun, error) {
+			from, _ := time.Parse(validation.DateLayout, req.From)
+			to, _ := time.Parse(validation.DateLayout, req.To)
+			return h.complianceService.CheckVisits(c.Request().Context(), from, to)
+		},
+		http.StatusOK,
+		&validation.CheckVisitComplianceRequest{},
+	)(c)
+}
+
+// Get the latest compliance check of a visit
+func (h *VisitComplianceHandler) GetVisitCompliance(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.VisitIDParam) (*model.VisitCompliance, error) {
+			return h.complianceService.GetVisitCompliance(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.VisitIDParam{},
+	)(c)
+}
+
+// Check a completed visit again
+func (h *VisitComplianceHandler) CheckVisit(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.VisitIDParam) (*model.VisitCompliance, error) {
+			return h.complianceService.CheckVisit(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.VisitIDParam{},
+	)(c)
+}
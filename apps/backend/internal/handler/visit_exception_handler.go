@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/etag"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/middleware"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/service"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/validation"
+)
+
+type VisitExceptionHandler struct {
+	Handler
+	exceptionService *service.VisitExceptionService
+}
+
+func NewVisitExceptionHandler(s *server.Server, exceptionService *service.VisitExceptionService) *VisitExceptionHandler {
+	return &VisitExceptionHandler{
+		Handler:          NewHandler(s),
+		exceptionService: exceptionService,
+	}
+}
+
+// Get exception reason codes
+func (h *VisitExceptionHandler) GetExceptionReasonCodes(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListExceptionReasonCodesQuery) ([]model.ExceptionReasonCode, error) {
+			return h.exceptionService.GetExceptionReasonCodes(c.Request().Context(), req.IncludeInactive)
+		},
+		http.StatusOK,
+		&validation.ListExceptionReasonCodesQuery{},
+	)(c)
+}
+
+// Create an exception reason code
+func (h *VisitExceptionHandler) CreateExceptionReasonCode(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CreateExceptionReasonCodeRequest) (*model.ExceptionReasonCode, error) {
+			return h.exceptionService.CreateExceptionReasonCode(c.Request().Context(), req.Code, req.Description)
+		},
+		http.StatusCreated,
+		&validation.CreateExceptionReasonCodeRequest{},
+	)(c)
+}
+
+// Update an exception reason code
+func (h *VisitExceptionHandler) UpdateExceptionReasonCode(c echo.Context) error {
+	return HandleVersioned(
+		h.Handler,
+		func(c echo.Context, req *validation.UpdateExceptionReasonCodeRequest) (*model.ExceptionReasonCode, error) {
+			return h.exceptionService.UpdateExceptionReasonCode(c.Request().Context(), req.Code, req.Description, req.IsActive)
+		},
+		func(c echo.Context, req *validation.UpdateExceptionReasonCodeRequest) (etag.Tagged, error) {
+			return h.exceptionService.GetExceptionReasonCode(c.Request().Context(), req.Code)
+		},
+		http.StatusOK,
+		&validation.UpdateExceptionReasonCodeRequest{},
+	)(c)
+}
+
+// Get the exceptions recorded for a visit
+func (h *VisitExceptionHandler) GetVisitExceptionsByVisit(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.VisitIDParam) ([]model.VisitException, error) {
+			return h.exceptionService.GetVisitExceptionsByVisit(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.VisitIDParam{},
+	)(c)
+}
+
+// Get the exception review queue
+func (h *VisitExceptionHandler) GetVisitExceptions(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListVisitExceptionsQuery) (*model.PaginatedResponse[model.VisitException], error) {
+			filter := model.VisitExceptionFilter{Status: req.Status}
+			if filter.Status == validation.VisitExceptionStatusAll {
+				filter.Status = ""
+			}
+			if req.From != "" {
+				from, _ := time.Parse(time.RFC3339, req.From)
+				filter.From = &from
+			}
+			if req.To != "" {
+				to, _ := time.Parse(time.RFC3339, req.To)
+				filter.To = &to
+			}
+
+			return h.exceptionService.GetVisitExceptions(c.Request().Context(), filter, req.Page, req.Limit)
+		},
+		http.StatusOK,
+		&validation.ListVisitExceptionsQuery{},
+	)(c)
+}
+
+// Get visit exception by ID
+func (h *VisitExceptionHandler) GetVisitExceptionById(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.VisitExceptionIDParam) (*model.VisitException, error) {
+			return h.exceptionService.GetVisitExceptionByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.VisitExceptionIDParam{},
+	)(c)
+}
+
+// Explain a visit exception with a reason code
+func (h *VisitExceptionHandler) ExplainVisitException(c echo.Context) error {
+	return HandleVersioned(
+		h.Handler,
+		func(c echo.Context, req *validation.ExplainVisitExceptionRequest) (*model.VisitException, error) {
+			return h.exceptionService.ExplainVisitException(c.Request().Context(), req.ID, req.ReasonCode, req.Explanation, middleware.GetUserID(c))
+		},
+		func(c echo.Context, req *validation.ExplainVisitExceptionRequest) (etag.Tagged, error) {
+			return h.exceptionService.GetVisitExceptionByID(c.Request().Context(), req.ID)
+		},
+		http.StatusOK,
+		&validation.ExplainVisitExceptionRequest{},
+	)(c)
+}
+
+// Approve an explained visit exception
+func (h *VisitExceptionHandler) ApproveVisitException(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ApproveVisitExceptionRequest) (*model.VisitException, error) {
+			return h.exceptionService.ApproveVisitException(c.Request().Context(), req.ID, middleware.GetUserID(c), req.Comment)
+		},
+		http.StatusOK,
+		&validation.ApproveVisitExceptionRequest{},
+	)(c)
+}
+
+// Reject an explained visit exception
+func (h *VisitExceptionHandler) RejectVisitException(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.RejectVisitExceptionRequest) (*model.VisitException, error) {
+			return h.exceptionService.RejectVisitException(c.Request().Context(), req.ID, middleware.GetUserID(c), req.Comment)
+		},
+		http.StatusOK,
+		&validation.RejectVisitExceptionRequest{},
+	)(c)
+}
@@ -0,0 +1,132 @@
+// Package aggregator renders verified visits into the batch formats state EVV
+// aggregators accept and delivers the batches to them.
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
+)
+
+// Record is one completed, verified visit as submitted to the aggregator
+type Record struct {
+	VisitID     uuid.UUID
+	ProviderID  string
+	ServiceCode string
+	// Individual receiving the service
+	ClientID         uuid.UUID
+	ClientMedicaidID string
+	ClientFirstName  string
+	ClientLastName   string
+	// Individual providing the service
+	CaregiverID        uuid.UUID
+	CaregiverFirstName string
+	CaregiverLastName  string
+	// TimeZone is the IANA zone the visit was worked in
+	TimeZone       string
+	StartTime      time.Time
+	EndTime        time.Time
+	StartLatitude  float64
+	StartLongitude float64
+	EndLatitude    float64
+	EndLongitude   float64
+	// IsManualEntry marks a visit entered or corrected by a coordinator;
+	// ReasonCodes are the reason codes given for its exceptions
+	IsManualEntry bool
+	ReasonCodes   []string
+}
+
+// ServiceDate returns the day the visit started in its time zone
+func (r *Record) ServiceDate() time.Time {
+	loc, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	start := r.StartTime.In(loc)
+	return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Batch is a set of records submitted together
+type Batch struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	Records   []Record
+}
+
+// Document is a rendered batch ready for delivery
+type Document struct {
+	BatchID     uuid.UUID
+	FileName    string
+	ContentType string
+	Body        []byte
+}
+
+// Result is the aggregator's verdict on one visit of a batch
+type Result struct {
+	VisitID  uuid.UUID `json:"visitId"`
+	Accepted bool      `json:"accepted"`
+	Messages []string  `json:"messages,omitempty"`
+}
+
+// Receipt acknowledges a delivered batch. Results are only present when the
+// aggregator validates the batch while receiving it.
+type Receipt struct {
+	Reference string   `json:"reference"`
+	Results   []Result `json:"results"`
+}
+
+// Format renders a batch in an aggregator's file layout
+type Format interface {
+	Name() string
+	FileExtension() string
+	ContentType() string
+	Render(batch *Batch) ([]byte, error)
+}
+
+// Transport delivers a rendered batch to the aggregator
+type Transport interface {
+	Name() string
+	Deliver(ctx context.Context, doc *Document) (*Receipt, error)
+}
+
+// NewFormat returns the format registered under name
+func NewFormat(name string) (Format, error) {
+	switch name {
+	case config.AggregatorFormatSandataJSON:
+		return SandataJSON{}, nil
+	case config.AggregatorFormatCSV:
+		return CSV{}, nil
+	default:
+		return nil, fmt.Errorf("unknown aggregator format: %s", name)
+	}
+}
+
+// NewTransport returns the transport the config selects
+func NewTransport(cfg *config.AggregatorConfig) (Transport, error) {
+	switch cfg.Transport {
+	case config.AggregatorTransportDirectory:
+		return NewDirectoryTransport(cfg.Directory), nil
+	case config.AggregatorTransportHTTP:
+		return NewHTTPTransport(cfg.URL, cfg.APIKey, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown aggregator transport: %s", cfg.Transport)
+	}
+}
+
+// Render renders a batch into a document named after the batch
+func Render(format Format, batch *Batch) (*Document, error) {
+	body, err := format.Render(batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s batch: %w", format.Name(), err)
+	}
+
+	return &Document{
+		BatchID:     batch.ID,
+		FileName:    fmt.Sprintf("evv-%s-%s.%s", batch.CreatedAt.UTC().Format("20060102T150405Z"), batch.ID, format.FileExtension()),
+		ContentType: format.ContentType(),
+		Body:        body,
+	}, nil
+}
@@ -0,0 +1,171 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRecord() Record {
+	start := time.Date(2024, 3, 4, 3, 30, 0, 0, time.UTC)
+	return Record{
+		VisitID:            uuid.New(),
+		ProviderID:         "PRV-100",
+		ServiceCode:        "T1019",
+		ClientID:           uuid.New(),
+		ClientMedicaidID:   "MA123456",
+		ClientFirstName:    "Ada",
+		ClientLastName:     "Lovelace",
+		CaregiverID:        uuid.New(),
+		CaregiverFirstName: "Grace",
+		CaregiverLastName:  "Hopper",
+		TimeZone:           "America/New_York",
+		StartTime:          start,
+		EndTime:            start.Add(4 * time.Hour),
+		StartLatitude:      40.7128,
+		StartLongitude:     -74.006,
+		EndLatitude:        40.7129,
+		EndLongitude:       -74.0061,
+	}
+}
+
+func newTestBatch(records ...Record) *Batch {
+	return &Batch{ID: uuid.New(), CreatedAt: time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC), Records: records}
+}
+
+func TestSandataJSON_Render(t *testing.T) {
+	record := newTestRecord()
+	record.IsManualEntry = true
+	record.ReasonCodes = []string{"FORGOT_TO_CLOCK"}
+
+	body, err := SandataJSON{}.Render(newTestBatch(record))
+	require.NoError(t, err)
+
+	var visits []sandataVisit
+	require.NoError(t, json.Unmarshal(body, &visits))
+	require.Len(t, visits, 1)
+
+	visit := visits[0]
+	assert.Equal(t, record.VisitID.String(), visit.VisitOtherID)
+	assert.Equal(t, "PRV-100", visit.ProviderIdentification.ProviderID)
+	assert.Equal(t, "MA123456", visit.ClientID)
+	assert.Equal(t, "T1019", visit.ProcedureCode)
+	require.Len(t, visit.Calls, 2)
+	assert.Equal(t, sandataCallTimeIn, visit.Calls[0].CallAssignment)
+	assert.Equal(t, "2024-03-04T03:30:00Z", visit.Calls[0].CallDateTime)
+	assert.Equal(t, sandataCallManual, visit.Calls[1].CallType)
+	require.Len(t, visit.VisitChanges, 1)
+	assert.Equal(t, "FORGOT_TO_CLOCK", visit.VisitChanges[0].ReasonCode)
+}
+
+func TestCSV_Render(t *testing.T) {
+	record := newTestRecord()
+
+	body, err := CSV{}.Render(newTestBatch(record))
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(string(body))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, record.VisitID.String(), rows[1][0])
+	// The visit started late on March 3 in New York
+	assert.Equal(t, "2024-03-03", rows[1][11])
+	assert.Equal(t, "40.712800", rows[1][14])
+}
+
+func TestRender_NamesDocumentAfterBatch(t *testing.T) {
+	batch := newTestBatch(newTestRecord())
+
+	doc, err := Render(CSV{}, batch)
+	require.NoError(t, err)
+
+	assert.Equal(t, "evv-20240305T120000Z-"+batch.ID.String()+".csv", doc.FileName)
+	assert.Equal(t, "text/csv", doc.ContentType)
+	assert.Equal(t, batch.ID, doc.BatchID)
+}
+
+func TestDirectoryTransport_Deliver(t *testing.T) {
+	dir := t.TempDir() + "/exports"
+	doc, err := Render(SandataJSON{}, newTestBatch(newTestRecord()))
+	require.NoError(t, err)
+
+	receipt, err := NewDirectoryTransport(dir).Deliver(context.Background(), doc)
+	require.NoError(t, err)
+
+	written, err := os.ReadFile(receipt.Reference)
+	require.NoError(t, err)
+	assert.Equal(t, doc.Body, written)
+	assert.Empty(t, receipt.Results)
+}
+
+func TestHTTPTransport_DeliverToStub(t *testing.T) {
+	for _, format := range []Format{SandataJSON{}, CSV{}} {
+		t.Run(format.Name(), func(t *testing.T) {
+			stub := NewStubAggregator()
+			stub.APIKey = "secret"
+			server := httptest.NewServer(stub)
+			defer server.Close()
+
+			valid := newTestRecord()
+			invalid := newTestRecord()
+			invalid.ServiceCode = ""
+			doc, err := Render(format, newTestBatch(valid, invalid))
+			require.NoError(t, err)
+
+			receipt, err := NewHTTPTransport(server.URL, "secret", time.Second).Deliver(context.Background(), doc)
+			require.NoError(t, err)
+
+			assert.Equal(t, "stub-"+doc.BatchID.String(), receipt.Reference)
+			require.Len(t, receipt.Results, 2)
+			assert.Equal(t, valid.VisitID, receipt.Results[0].VisitID)
+			assert.True(t, receipt.Results[0].Accepted)
+			assert.False(t, receipt.Results[1].Accepted)
+			assert.Len(t, receipt.Results[1].Messages, 1)
+
+			require.Len(t, stub.Batches(), 1)
+			assert.Equal(t, doc.FileName, stub.Batches()[0].FileName)
+		})
+	}
+}
+
+func TestHTTPTransport_DeliverRejected(t *testing.T) {
+	stub := NewStubAggregator()
+	stub.APIKey = "secret"
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	doc, err := Render(SandataJSON{}, newTestBatch(newTestRecord()))
+	require.NoError(t, err)
+
+	_, err = NewHTTPTransport(server.URL, "wrong", time.Second).Deliver(context.Background(), doc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+	assert.Empty(t, stub.Batches())
+}
+
+func TestHTTPTransport_EmptyReceipt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Reference", "ref-1")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	doc, err := Render(CSV{}, newTestBatch(newTestRecord()))
+	require.NoError(t, err)
+
+	receipt, err := NewHTTPTransport(server.URL, "", time.Second).Deliver(context.Background(), doc)
+	require.NoError(t, err)
+	assert.Equal(t, "ref-1", receipt.Reference)
+	assert.Empty(t, receipt.Results)
+}
@@ -0,0 +1,72 @@
+package aggregator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
+)
+
+// csvHeader is the column layout of the CSV format, one visit per row
+var csvHeader = []string{
+	"visit_id", "provider_id", "service_code",
+	"client_id", "client_medicaid_id", "client_first_name", "client_last_name",
+	"caregiver_id", "caregiver_first_name", "caregiver_last_name",
+	"time_zone", "service_date", "start_time", "end_time",
+	"start_latitude", "start_longitude", "end_latitude", "end_longitude",
+	"manual_entry", "reason_codes",
+}
+
+// CSV renders a batch as a CSV file with a header row and one visit per row
+type CSV struct{}
+
+func (CSV) Name() string {
+	return config.AggregatorFormatCSV
+}
+
+func (CSV) FileExtension() string {
+	return "csv"
+}
+
+func (CSV) ContentType() string {
+	return "text/csv"
+}
+
+func (CSV) Render(batch *Batch) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+
+	for _, record := range batch.Records {
+		row := []string{
+			record.VisitID.String(), record.ProviderID, record.ServiceCode,
+			record.ClientID.String(), record.ClientMedicaidID, record.ClientFirstName, record.ClientLastName,
+			record.CaregiverID.String(), record.CaregiverFirstName, record.CaregiverLastName,
+			record.TimeZone, record.ServiceDate().Format("2006-01-02"),
+			record.StartTime.UTC().Format(time.RFC3339), record.EndTime.UTC().Format(time.RFC3339),
+			formatCoordinate(record.StartLatitude), formatCoordinate(record.StartLongitude),
+			formatCoordinate(record.EndLatitude), formatCoordinate(record.EndLongitude),
+			strconv.FormatBool(record.IsManualEntry), strings.Join(record.ReasonCodes, ";"),
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
@@ -0,0 +1,129 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
+)
+
+// Call assignments and types of the Sandata visit layout
+const (
+	sandataCallTimeIn  = "Time In"
+	sandataCallTimeOut = "Time Out"
+	sandataCallMobile  = "Mobile"
+	sandataCallManual  = "Manual"
+)
+
+// SandataJSON renders a batch as a JSON array of visits in the Sandata
+// alternate EVV layout
+type SandataJSON struct{}
+
+type sandataVisit struct {
+	ProviderIdentification  sandataProvider      `json:"ProviderIdentification"`
+	VisitOtherID            string               `json:"VisitOtherID"`
+	SequenceID              string               `json:"SequenceID"`
+	EmployeeQualifier       string               `json:"EmployeeQualifier"`
+	EmployeeOtherID         string               `json:"EmployeeOtherID"`
+	EmployeeFirstName       string               `json:"EmployeeFirstName"`
+	EmployeeLastName        string               `json:"EmployeeLastName"`
+	ClientIDQualifier       string               `json:"ClientIDQualifier"`
+	ClientID                string               `json:"ClientID"`
+	ClientOtherID           string               `json:"ClientOtherID"`
+	ClientFirstName         string               `json:"ClientFirstName"`
+	ClientLastName          string               `json:"ClientLastName"`
+	ProcedureCode           string               `json:"ProcedureCode"`
+	VisitTimeZone           string               `json:"VisitTimeZone"`
+	VisitCancelledIndicator bool                 `json:"VisitCancelledIndicator"`
+	Calls                   []sandataCall        `json:"Calls"`
+	VisitChanges            []sandataVisitChange `json:"VisitChanges,omitempty"`
+}
+
+type sandataProvider struct {
+	ProviderQualifier string `json:"ProviderQualifier"`
+	ProviderID        string `json:"ProviderID"`
+}
+
+type sandataCall struct {
+	CallExternalID string  `json:"CallExternalID"`
+	CallDateTime   string  `json:"CallDateTime"`
+	CallAssignment string  `json:"CallAssignment"`
+	CallType       string  `json:"CallType"`
+	CallLatitude   float64 `json:"CallLatitude"`
+	CallLongitude  float64 `json:"CallLongitude"`
+}
+
+type sandataVisitChange struct {
+	SequenceID string `json:"SequenceID"`
+	ReasonCode string `json:"ReasonCode"`
+}
+
+func (SandataJSON) Name() string {
+	return config.AggregatorFormatSandataJSON
+}
+
+func (SandataJSON) FileExtension() string {
+	return "json"
+}
+
+func (SandataJSON) ContentType() string {
+	return "application/json"
+}
+
+func (SandataJSON) Render(batch *Batch) ([]byte, error) {
+	// Sandata orders updates to the same visit by sequence ID
+	sequenceID := batch.CreatedAt.UTC().Format("20060102150405")
+
+	visits := make([]sandataVisit, 0, len(batch.Records))
+	for _, record := range batch.Records {
+		callType := sandataCallMobile
+		if record.IsManualEntry {
+			callType = sandataCallManual
+		}
+
+		visit := sandataVisit{
+			ProviderIdentification: sandataProvider{
+				ProviderQualifier: "Other",
+				ProviderID:        record.ProviderID,
+			},
+			VisitOtherID:      record.VisitID.String(),
+			SequenceID:        sequenceID,
+			EmployeeQualifier: "EmployeeCustomID",
+			EmployeeOtherID:   record.CaregiverID.String(),
+			EmployeeFirstName: record.CaregiverFirstName,
+			EmployeeLastName:  record.CaregiverLastName,
+			ClientIDQualifier: "ClientMedicaidID",
+			ClientID:          record.ClientMedicaidID,
+			ClientOtherID:     record.ClientID.String(),
+			ClientFirstName:   record.ClientFirstName,
+			ClientLastName:    record.ClientLastName,
+			ProcedureCode:     record.ServiceCode,
+			VisitTimeZone:     record.TimeZone,
+			Calls: []sandataCall{
+				{
+					CallExternalID: record.VisitID.String() + "-in",
+					CallDateTime:   record.StartTime.UTC().Format(time.RFC3339),
+					CallAssignment: sandataCallTimeIn,
+					CallType:       callType,
+					CallLatitude:   record.StartLatitude,
+					CallLongitude:  record.StartLongitude,
+				},
+				{
+					CallExternalID: record.VisitID.String() + "-out",
+					CallDateTime:   record.EndTime.UTC().Format(time.RFC3339),
+					CallAssignment: sandataCallTimeOut,
+					CallType:       callType,
+					CallLatitude:   record.EndLatitude,
+					CallLongitude:  record.EndLongitude,
+				},
+			},
+		}
+		for _, code := range record.ReasonCodes {
+			visit.VisitChanges = append(visit.VisitChanges, sandataVisitChange{SequenceID: sequenceID, ReasonCode: code})
+		}
+
+		visits = append(visits, visit)
+	}
+
+	return json.MarshalIndent(visits, "", "  ")
+}
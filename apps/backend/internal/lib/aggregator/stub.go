@@ -0,0 +1,174 @@
+package aggregator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// StubAggregator is a local stand-in for a state aggregator's HTTP endpoint.
+// It accepts both formats, checks each visit for the fields an aggregator
+// requires and answers with a receipt. Serve it with httptest.NewServer.
+type StubAggregator struct {
+	// APIKey, when set, is required as a bearer token
+	APIKey string
+
+	mu      sync.Mutex
+	batches []StubBatch
+}
+
+// StubBatch is a batch the stub received
+type StubBatch struct {
+	BatchID     string
+	FileName    string
+	ContentType string
+	Body        []byte
+	Results     []Result
+}
+
+func NewStubAggregator() *StubAggregator {
+	return &StubAggregator{}
+}
+
+// Batches returns the batches received so far
+func (s *StubAggregator) Batches() []StubBatch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]StubBatch(nil), s.batches...)
+}
+
+func (s *StubAggregator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.APIKey != "" && r.Header.Get("Authorization") != "Bearer "+s.APIKey {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var results []Result
+	switch contentType {
+	case "application/json":
+		results, err = checkSandataBatch(body)
+	case "text/csv":
+		results, err = checkCSVBatch(body)
+	default:
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	batchID := r.Header.Get("X-Batch-ID")
+	s.mu.Lock()
+	s.batches = append(s.batches, StubBatch{
+		BatchID:     batchID,
+		FileName:    r.Header.Get("X-File-Name"),
+		ContentType: contentType,
+		Body:        body,
+		Results:     results,
+	})
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Receipt{Reference: "stub-" + batchID, Results: results})
+}
+
+func checkSandataBatch(body []byte) ([]Result, error) {
+	var visits []sandataVisit
+	if err := json.Unmarshal(body, &visits); err != nil {
+		return nil, fmt.Errorf("invalid JSON batch: %w", err)
+	}
+
+	results := make([]Result, 0, len(visits))
+	for _, visit := range visits {
+		id, err := uuid.Parse(visit.VisitOtherID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VisitOtherID %q", visit.VisitOtherID)
+		}
+
+		var messages []string
+		messages = requireField(messages, "ProviderID", visit.ProviderIdentification.ProviderID)
+		messages = requireField(messages, "ProcedureCode", visit.ProcedureCode)
+		messages = requireField(messages, "ClientID", visit.ClientID)
+		messages = requireField(messages, "EmployeeOtherID", nonNilUUID(visit.EmployeeOtherID))
+		if len(visit.Calls) < 2 {
+			messages = append(messages, "Calls must include a Time In and a Time Out call")
+		}
+
+		results = append(results, Result{VisitID: id, Accepted: len(messages) == 0, Messages: messages})
+	}
+
+	return results, nil
+}
+
+func checkCSVBatch(body []byte) ([]Result, error) {
+	rows, err := csv.NewReader(strings.NewReader(string(body))).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV batch: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("CSV batch has no header row")
+	}
+
+	columns := make(map[string]int, len(rows[0]))
+	for i, name := range rows[0] {
+		columns[name] = i
+	}
+	for _, name := range csvHeader {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV batch is missing column %s", name)
+		}
+	}
+
+	results := make([]Result, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		id, err := uuid.Parse(row[columns["visit_id"]])
+		if err != nil {
+			return nil, fmt.Errorf("invalid visit_id %q", row[columns["visit_id"]])
+		}
+
+		var messages []string
+		messages = requireField(messages, "provider_id", row[columns["provider_id"]])
+		messages = requireField(messages, "service_code", row[columns["service_code"]])
+		messages = requireField(messages, "client_medicaid_id", row[columns["client_medicaid_id"]])
+		messages = requireField(messages, "caregiver_id", nonNilUUID(row[columns["caregiver_id"]]))
+		messages = requireField(messages, "end_time", row[columns["end_time"]])
+
+		results = append(results, Result{VisitID: id, Accepted: len(messages) == 0, Messages: messages})
+	}
+
+	return results, nil
+}
+
+func requireField(messages []string, name, value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return append(messages, name+" is required")
+	}
+	return messages
+}
+
+// nonNilUUID treats the nil UUID as a missing value
+func nonNilUUID(value string) string {
+	if value == uuid.Nil.String() {
+		return ""
+	}
+	return value
+}
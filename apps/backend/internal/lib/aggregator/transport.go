@@ -0,0 +1,113 @@
+package aggregator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
+)
+
+// maxReceiptBytes bounds the aggregator response read into memory
+const maxReceiptBytes = 10 << 20
+
+// DirectoryTransport writes each batch to a file in a local directory, for
+// aggregators that collect files from a drop folder. It cannot see whether
+// the aggregator accepts the visits.
+type DirectoryTransport struct {
+	dir string
+}
+
+func NewDirectoryTransport(dir string) *DirectoryTransport {
+	return &DirectoryTransport{dir: dir}
+}
+
+func (t *DirectoryTransport) Name() string {
+	return config.AggregatorTransportDirectory
+}
+
+func (t *DirectoryTransport) Deliver(ctx context.Context, doc *Document) (*Receipt, error) {
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	// Write under a temporary name so a collector never picks up a partial file
+	path := filepath.Join(t.dir, doc.FileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, doc.Body, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write batch file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to move batch file into place: %w", err)
+	}
+
+	return &Receipt{Reference: path}, nil
+}
+
+// HTTPTransport posts each batch to the aggregator's endpoint. A 2xx response
+// may carry a JSON receipt with the aggregator's verdict on every visit.
+type HTTPTransport struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewHTTPTransport(url, apiKey string, timeout time.Duration) *HTTPTransport {
+	return &HTTPTransport{
+		url:    url,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (t *HTTPTransport) Name() string {
+	return config.AggregatorTransportHTTP
+}
+
+func (t *HTTPTransport) Deliver(ctx context.Context, doc *Document) (*Receipt, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(doc.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build aggregator request: %w", err)
+	}
+	req.Header.Set("Content-Type", doc.ContentType)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Batch-ID", doc.BatchID.String())
+	req.Header.Set("X-File-Name", doc.FileName)
+	if t.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+t.apiKey)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach aggregator: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxReceiptBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aggregator response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("aggregator responded %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	receipt := &Receipt{}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, receipt); err != nil {
+			return nil, fmt.Errorf("failed to parse aggregator receipt: %w", err)
+		}
+	}
+	if receipt.Reference == "" {
+		receipt.Reference = resp.Header.Get("X-Reference")
+	}
+
+	return receipt, nil
+}
@@ -0,0 +1,32 @@
+// Package audit carries who made a change, and in which request, from the
+// HTTP layer down to the database transaction that records it.
+package audit
+
+import "context"
+
+// SystemActorPrefix marks changes made by background jobs rather than a user
+const SystemActorPrefix = "system:"
+
+// Actor identifies the user and request behind a change
+type Actor struct {
+	UserID    string
+	RequestID string
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// WithSystemActor returns a copy of ctx attributing changes to a background job
+func WithSystemActor(ctx context.Context, job, runID string) context.Context {
+	return WithActor(ctx, Actor{UserID: SystemActorPrefix + job, RequestID: runID})
+}
+
+// ActorFromContext returns the actor carried by ctx, or the zero Actor
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
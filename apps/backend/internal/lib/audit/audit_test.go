@@ -0,0 +1,20 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorFromContext(t *testing.T) {
+	assert.Equal(t, Actor{}, ActorFromContext(context.Background()))
+
+	ctx := WithActor(context.Background(), Actor{UserID: "user_123", RequestID: "req-1"})
+	assert.Equal(t, Actor{UserID: "user_123", RequestID: "req-1"}, ActorFromContext(ctx))
+}
+
+func TestWithSystemActor(t *testing.T) {
+	ctx := WithSystemActor(context.Background(), "missed_visit_scan", "task-1")
+	assert.Equal(t, Actor{UserID: "system:missed_visit_scan", RequestID: "task-1"}, ActorFromContext(ctx))
+}
@@ -12,3 +12,19 @@ func (c *Client) SendWelcomeEmail(to, firstName string) error {
 		data,
 	)
 }
+
+func (c *Client) SendMissedVisitEmail(to, clientName, location, scheduledStart, scheduleID string) error {
+	data := map[string]string{
+		"ClientName":     clientName,
+		"Location":       location,
+		"ScheduledStart": scheduledStart,
+		"ScheduleID":     scheduleID,
+	}
+
+	return c.SendEmail(
+		to,
+		"Missed visit: "+clientName,
+		TemplateMissedVisit,
+		data,
+	)
+}
@@ -4,4 +4,10 @@ var PreviewData = map[string]map[string]string{
 	"welcome": {
 		"UserFirstName": "John",
 	},
+	"missed_visit": {
+		"ClientName":     "Jane Doe",
+		"Location":       "123 Main St, Springfield, IL 62701",
+		"ScheduledStart": "Mon Mar 4, 2024 9:00 AM EST",
+		"ScheduleID":     "7b0e1c5a-2f4e-4c1a-9d1e-3f6a2b8c9d10",
+	},
 }
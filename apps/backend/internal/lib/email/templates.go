@@ -3,5 +3,6 @@ package email
 type Template string
 
 const (
-	TemplateWelcome Template = "welcome"
+	TemplateWelcome     Template = "welcome"
+	TemplateMissedVisit Template = "missed_visit"
 )
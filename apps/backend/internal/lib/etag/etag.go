@@ -0,0 +1,152 @@
+// Package etag builds entity tags from row versions and evaluates the
+// If-Match and If-None-Match preconditions of a request against them.
+//
+// A row's version is its updated_at, which the database sets on every
+// write. Tags are strong and quoted: "<updated_at in unix microseconds>",
+// optionally followed by a digest of embedded rows, e.g. the tasks returned
+// with a schedule. If-Match only compares the version of the row itself.
+package etag
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+)
+
+const (
+	Header            = "ETag"
+	IfMatchHeader     = "If-Match"
+	IfNoneMatchHeader = "If-None-Match"
+)
+
+// Tagged is implemented by responses that carry an entity tag
+type Tagged interface {
+	ETag() string
+}
+
+// Format returns the entity tag of a row version and the digest of any rows
+// embedded in the representation
+func Format(version time.Time, digest ...string) string {
+	tag := strconv.FormatInt(version.UnixMicro(), 10)
+	for _, d := range digest {
+		tag += "-" + d
+	}
+	return `"` + tag + `"`
+}
+
+// Digest summarizes the versions of embedded rows for Format
+func Digest(values ...string) string {
+	hash := fnv.New64a()
+	for _, value := range values {
+		hash.Write([]byte(value))
+		hash.Write([]byte{0})
+	}
+	return strconv.FormatUint(hash.Sum64(), 36)
+}
+
+// Version returns the row version a tag was built from
+func Version(tag string) (int64, bool) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	tag = strings.Trim(tag, `"`)
+	tag, _, _ = strings.Cut(tag, "-")
+
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
+
+// Matches reports whether an If-None-Match header lists tag. Weak tags
+// compare equal to their strong form and "*" matches any tag.
+func Matches(header, tag string) bool {
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+type ifMatchKey struct{}
+
+// WithIfMatch returns a copy of ctx carrying the If-Match header of a request
+func WithIfMatch(ctx context.Context, header string) context.Context {
+	return context.WithValue(ctx, ifMatchKey{}, header)
+}
+
+// IfMatchFromContext returns the If-Match header carried by ctx, or ""
+func IfMatchFromContext(ctx context.Context) string {
+	header, _ := ctx.Value(ifMatchKey{}).(string)
+	return header
+}
+
+// CheckIfMatch returns a 412 error when ctx carries an If-Match header that
+// does not list the given row version. Requests without one always pass.
+func CheckIfMatch(ctx context.Context, version time.Time) error {
+	header := IfMatchFromContext(ctx)
+	if header == "" {
+		return nil
+	}
+
+	current := version.UnixMicro()
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimSpace(candidate) == "*" {
+			return nil
+		}
+		if v, ok := Version(candidate); ok && v == current {
+			return nil
+		}
+	}
+
+	return PreconditionFailedError()
+}
+
+// Check evaluates the If-Match header carried by ctx against the current
+// representation of a resource. When it holds, the returned context carries
+// the version it was checked against, so the write can be guarded on it.
+func Check(ctx context.Context, current Tagged) (context.Context, error) {
+	tag := current.ETag()
+	version, ok := Version(tag)
+	if !ok {
+		return ctx, fmt.Errorf("invalid entity tag %s", tag)
+	}
+
+	if err := CheckIfMatch(ctx, time.UnixMicro(version)); err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, versionKey{}, version), nil
+}
+
+type versionKey struct{}
+
+// VersionFromContext returns the row version an If-Match header carried by
+// ctx was checked against by Check
+func VersionFromContext(ctx context.Context) (time.Time, bool) {
+	version, ok := ctx.Value(versionKey{}).(int64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.UnixMicro(version), true
+}
+
+// PreconditionFailedError is returned when an If-Match header does not list
+// the version of the resource, whether it is found so before or by the write
+func PreconditionFailedError() error {
+	code := "ETAG_MISMATCH"
+	return errs.NewPreconditionFailedError("Resource was modified since it was read", true, &code)
+}
+
+// NotSupportedError is returned when a request carries an If-Match header
+// for a resource whose version is never checked against it
+func NotSupportedError() error {
+	code := "IF_MATCH_NOT_SUPPORTED"
+	return errs.NewPreconditionFailedError("If-Match is not supported by this endpoint", true, &code)
+}
@@ -0,0 +1,84 @@
+package etag
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	version := time.Date(2024, 3, 4, 15, 0, 0, 123456000, time.UTC)
+	assert.Equal(t, `"1709564400123456"`, Format(version))
+	assert.Equal(t, `"1709564400123456-abc"`, Format(version, "abc"))
+
+	v, ok := Version(Format(version, Digest("task", "1")))
+	require.True(t, ok)
+	assert.Equal(t, version.UnixMicro(), v)
+
+	v, ok = Version(`W/"1709564400123456"`)
+	require.True(t, ok)
+	assert.Equal(t, version.UnixMicro(), v)
+
+	_, ok = Version(`"not-a-version"`)
+	assert.False(t, ok)
+}
+
+func TestDigest(t *testing.T) {
+	assert.Equal(t, Digest("a", "b"), Digest("a", "b"))
+	assert.NotEqual(t, Digest("a", "b"), Digest("ab"))
+	assert.NotEqual(t, Digest("a", "b"), Digest("b", "a"))
+}
+
+func TestMatches(t *testing.T) {
+	tag := `"1709564400123456-abc"`
+	assert.True(t, Matches(tag, tag))
+	assert.True(t, Matches(`"1", W/"1709564400123456-abc"`, tag))
+	assert.True(t, Matches("*", tag))
+	assert.False(t, Matches(`"1709564400123456"`, tag))
+}
+
+func TestCheckIfMatch(t *testing.T) {
+	version := time.Date(2024, 3, 4, 15, 0, 0, 123456000, time.UTC)
+
+	assert.NoError(t, CheckIfMatch(context.Background(), version))
+	assert.NoError(t, CheckIfMatch(WithIfMatch(context.Background(), "*"), version))
+	assert.NoError(t, CheckIfMatch(WithIfMatch(context.Background(), Format(version)), version))
+	// A tag read with the embedded rows still matches the row itself
+	assert.NoError(t, CheckIfMatch(WithIfMatch(context.Background(), `"1", `+Format(version, "abc")), version))
+
+	for _, header := range []string{Format(version.Add(time.Microsecond)), "garbage"} {
+		err := CheckIfMatch(WithIfMatch(context.Background(), header), version)
+		var httpErr *errs.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusPreconditionFailed, httpErr.Status)
+		assert.Equal(t, "ETAG_MISMATCH", httpErr.Code)
+	}
+}
+
+type tagged string
+
+func (t tagged) ETag() string { return string(t) }
+
+func TestCheck(t *testing.T) {
+	version := time.Date(2024, 3, 4, 15, 0, 0, 123456000, time.UTC)
+	current := tagged(Format(version, "abc"))
+
+	ctx, err := Check(WithIfMatch(context.Background(), Format(version)), current)
+	require.NoError(t, err)
+	checked, ok := VersionFromContext(ctx)
+	require.True(t, ok)
+	assert.True(t, checked.Equal(version))
+
+	_, err = Check(WithIfMatch(context.Background(), Format(version.Add(time.Microsecond))), current)
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, "ETAG_MISMATCH", httpErr.Code)
+
+	_, ok = VersionFromContext(context.Background())
+	assert.False(t, ok)
+}
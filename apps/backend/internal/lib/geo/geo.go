@@ -0,0 +1,30 @@
+package geo
+
+import "math"
+
+// EarthRadiusMeters is the mean Earth radius used for great-circle distances
+const EarthRadiusMeters = 6371000.0
+
+// DistanceMeters returns the great-circle (haversine) distance in meters
+// between two latitude/longitude pairs given in degrees
+func DistanceMeters(lat1, long1, lat2, long2 float64) float64 {
+	phi1 := toRadians(lat1)
+	phi2 := toRadians(lat2)
+	deltaPhi := toRadians(lat2 - lat1)
+	deltaLambda := toRadians(long2 - long1)
+
+	a := math.Sin(deltaPhi/2)*math.Sin(deltaPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(deltaLambda/2)*math.Sin(deltaLambda/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return EarthRadiusMeters * c
+}
+
+// WithinRadius reports whether two coordinates are at most radiusMeters apart
+func WithinRadius(lat1, long1, lat2, long2, radiusMeters float64) bool {
+	return DistanceMeters(lat1, long1, lat2, long2) <= radiusMeters
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,23 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceMeters_SamePoint(t *testing.T) {
+	assert.InDelta(t, 0, DistanceMeters(40.7128, -74.0060, 40.7128, -74.0060), 0.001)
+}
+
+func TestDistanceMeters_KnownDistance(t *testing.T) {
+	// New York City to Los Angeles is roughly 3,936 km
+	distance := DistanceMeters(40.7128, -74.0060, 34.0522, -118.2437)
+	assert.InDelta(t, 3936000, distance, 10000)
+}
+
+func TestWithinRadius(t *testing.T) {
+	// Two points about 111 meters apart along a meridian
+	assert.True(t, WithinRadius(40.0000, -74.0, 40.0010, -74.0, 150))
+	assert.False(t, WithinRadius(40.0000, -74.0, 40.0010, -74.0, 100))
+}
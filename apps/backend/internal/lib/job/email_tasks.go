@@ -5,10 +5,12 @@ import (
 	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
 )
 
 const (
-	TaskWelcome = "email:welcome"
+	TaskWelcome          = "email:welcome"
+	TaskMissedVisitAlert = "email:missed_visit"
 )
 
 type WelcomeEmailPayload struct {
@@ -30,3 +32,29 @@ func NewWelcomeEmailTask(to, firstName string) (*asynq.Task, error) {
 		asynq.Queue("default"),
 		asynq.Timeout(30*time.Second)), nil
 }
+
+type MissedVisitEmailPayload struct {
+	To             string `json:"to"`
+	ScheduleID     string `json:"schedule_id"`
+	ClientName     string `json:"client_name"`
+	Location       string `json:"location"`
+	ScheduledStart string `json:"scheduled_start"`
+}
+
+func NewMissedVisitEmailTask(to string, schedule model.Schedule) (*asynq.Task, error) {
+	payload, err := json.Marshal(MissedVisitEmailPayload{
+		To:             to,
+		ScheduleID:     schedule.ID.String(),
+		ClientName:     schedule.ClientName,
+		Location:       schedule.Location,
+		ScheduledStart: schedule.LocalScheduledStart().Format("Mon Jan 2, 2006 3:04 PM MST"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskMissedVisitAlert, payload,
+		asynq.MaxRetry(3),
+		asynq.Queue("critical"),
+		asynq.Timeout(30*time.Second)), nil
+}
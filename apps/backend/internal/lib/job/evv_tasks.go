@@ -0,0 +1,56 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+const (
+	TaskMissedVisitScan   = "evv:missed_visit_scan"
+	TaskMaterializeSeries = "evv:materialize_series"
+	TaskAggregatorExport  = "evv:aggregator_export"
+)
+
+// MissedVisitDetector marks overdue schedules as missed and returns them
+type MissedVisitDetector interface {
+	MarkMissedVisits(ctx context.Context) ([]model.Schedule, error)
+}
+
+// SeriesMaterializer creates the upcoming schedules of recurring series
+type SeriesMaterializer interface {
+	MaterializeScheduleSeries(ctx context.Context) (int, error)
+}
+
+// AggregatorExporter submits the visits that are due to the state EVV aggregator
+type AggregatorExporter interface {
+	ExportVisits(ctx context.Context) ([]model.AggregatorBatch, error)
+}
+
+func NewMissedVisitScanTask(interval time.Duration) *asynq.Task {
+	// Unique keeps several app instances from enqueuing overlapping scans
+	return asynq.NewTask(TaskMissedVisitScan, nil,
+		asynq.MaxRetry(1),
+		asynq.Queue("default"),
+		asynq.Timeout(2*time.Minute),
+		asynq.Unique(interval))
+}
+
+func NewMaterializeSeriesTask(interval time.Duration) *asynq.Task {
+	return asynq.NewTask(TaskMaterializeSeries, nil,
+		asynq.MaxRetry(1),
+		asynq.Queue("low"),
+		asynq.Timeout(5*time.Minute),
+		asynq.Unique(interval))
+}
+
+func NewAggregatorExportTask(interval time.Duration) *asynq.Task {
+	// No retry: visits of a failed batch are picked up again by the next run
+	return asynq.NewTask(TaskAggregatorExport, nil,
+		asynq.MaxRetry(0),
+		asynq.Queue("low"),
+		asynq.Timeout(10*time.Minute),
+		asynq.Unique(interval))
+}
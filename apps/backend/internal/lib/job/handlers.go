@@ -8,7 +8,9 @@ import (
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/audit"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/email"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
 )
 
 var emailClient *email.Client
@@ -47,3 +49,151 @@ func (j *JobService) handleWelcomeEmailTask(ctx context.Context, t *asynq.Task)
 		Msg("Successfully sent welcome email")
 	return nil
 }
+
+func (j *JobService) handleMissedVisitScanTask(ctx context.Context, t *asynq.Task) error {
+	if j.missedVisitDetector == nil {
+		return fmt.Errorf("missed visit detector not registered: %w", asynq.SkipRetry)
+	}
+
+	taskID, _ := asynq.GetTaskID(ctx)
+	ctx = audit.WithSystemActor(ctx, "missed_visit_scan", taskID)
+
+	missed, err := j.missedVisitDetector.MarkMissedVisits(ctx)
+	if err != nil {
+		j.logger.Error().
+			Str("type", "missed_visit_scan").
+			Int("marked", len(missed)).
+			Err(err).
+			Msg("Failed to mark missed visits")
+		return err
+	}
+
+	for _, schedule := range missed {
+		j.logger.Warn().
+			Str("event", "schedule.missed").
+			Str("schedule_id", schedule.ID.String()).
+			Str("client_id", schedule.ClientID.String()).
+			Time("scheduled_start", schedule.ScheduledStart).
+			Msg("Schedule marked as missed")
+
+		if j.evvConfig.CoordinatorEmail == "" {
+			continue
+		}
+
+		task, err := NewMissedVisitEmailTask(j.evvConfig.CoordinatorEmail, schedule)
+		if err != nil {
+			return fmt.Errorf("failed to create missed visit email task: %w", err)
+		}
+
+		if _, err := j.Client.EnqueueContext(ctx, task); err != nil {
+			j.logger.Error().
+				Str("type", "missed_visit").
+				Str("schedule_id", schedule.ID.String()).
+				Err(err).
+				Msg("Failed to enqueue missed visit email")
+		}
+	}
+
+	j.logger.Info().
+		Str("type", "missed_visit_scan").
+		Int("marked", len(missed)).
+		Msg("Completed missed visit scan")
+	return nil
+}
+
+func (j *JobService) handleMissedVisitEmailTask(ctx context.Context, t *asynq.Task) error {
+	var p MissedVisitEmailPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal missed visit email payload: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "missed_visit").
+		Str("to", p.To).
+		Str("schedule_id", p.ScheduleID).
+		Msg("Processing missed visit email task")
+
+	err := emailClient.SendMissedVisitEmail(
+		p.To,
+		p.ClientName,
+		p.Location,
+		p.ScheduledStart,
+		p.ScheduleID,
+	)
+	if err != nil {
+		j.logger.Error().
+			Str("type", "missed_visit").
+			Str("to", p.To).
+			Err(err).
+			Msg("Failed to send missed visit email")
+		return err
+	}
+
+	j.logger.Info().
+		Str("type", "missed_visit").
+		Str("to", p.To).
+		Msg("Successfully sent missed visit email")
+	return nil
+}
+
+func (j *JobService) handleMaterializeSeriesTask(ctx context.Context, t *asynq.Task) error {
+	if j.seriesMaterializer == nil {
+		return fmt.Errorf("series materializer not registered: %w", asynq.SkipRetry)
+	}
+
+	taskID, _ := asynq.GetTaskID(ctx)
+	ctx = audit.WithSystemActor(ctx, "materialize_series", taskID)
+
+	created, err := j.seriesMaterializer.MaterializeScheduleSeries(ctx)
+	if err != nil {
+		j.logger.Error().
+			Str("type", "materialize_series").
+			Int("created", created).
+			Err(err).
+			Msg("Failed to materialize schedule series")
+		return err
+	}
+
+	j.logger.Info().
+		Str("type", "materialize_series").
+		Int("created", created).
+		Msg("Materialized schedule series")
+	return nil
+}
+
+func (j *JobService) handleAggregatorExportTask(ctx context.Context, t *asynq.Task) error {
+	if j.aggregatorExporter == nil {
+		return fmt.Errorf("aggregator exporter not registered: %w", asynq.SkipRetry)
+	}
+
+	batches, err := j.aggregatorExporter.ExportVisits(ctx)
+	if err != nil {
+		j.logger.Error().
+			Str("type", "aggregator_export").
+			Err(err).
+			Msg("Failed to export visits to the aggregator")
+		return err
+	}
+
+	for _, batch := range batches {
+		event := j.logger.Info()
+		if batch.Status != model.AggregatorBatchStatusDelivered {
+			event = j.logger.Error()
+			if batch.Error != nil {
+				event = event.Str("error", *batch.Error)
+			}
+		}
+		event.
+			Str("type", "aggregator_export").
+			Str("batch_id", batch.ID.String()).
+			Str("status", batch.Status).
+			Int("visits", batch.VisitCount).
+			Msg("Exported aggregator batch")
+	}
+
+	j.logger.Info().
+		Str("type", "aggregator_export").
+		Int("batches", len(batches)).
+		Msg("Completed aggregator export")
+	return nil
+}
@@ -1,15 +1,24 @@
 package job
 
 import (
+	"fmt"
+
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
 )
 
 type JobService struct {
-	Client *asynq.Client
-	server *asynq.Server
-	logger *zerolog.Logger
+	Client           *asynq.Client
+	server           *asynq.Server
+	scheduler        *asynq.Scheduler
+	logger           *zerolog.Logger
+	evvConfig        *config.EVVConfig
+	aggregatorConfig *config.AggregatorConfig
+
+	missedVisitDetector MissedVisitDetector
+	seriesMaterializer  SeriesMaterializer
+	aggregatorExporter  AggregatorExporter
 }
 
 func NewJobService(logger *zerolog.Logger, cfg *config.Config) *JobService {
@@ -31,28 +40,79 @@ func NewJobService(logger *zerolog.Logger, cfg *config.Config) *JobService {
 		},
 	)
 
+	scheduler := asynq.NewScheduler(
+		asynq.RedisClientOpt{Addr: redisAddr},
+		nil,
+	)
+
 	return &JobService{
-		Client: client,
-		server: server,
-		logger: logger,
+		Client:           client,
+		server:           server,
+		scheduler:        scheduler,
+		logger:           logger,
+		evvConfig:        cfg.EVV,
+		aggregatorConfig: cfg.Aggregator,
 	}
 }
 
+// SetMissedVisitDetector registers the service the periodic missed visit scan runs against
+func (j *JobService) SetMissedVisitDetector(detector MissedVisitDetector) {
+	j.missedVisitDetector = detector
+}
+
+// SetSeriesMaterializer registers the service the periodic series materialization runs against
+func (j *JobService) SetSeriesMaterializer(materializer SeriesMaterializer) {
+	j.seriesMaterializer = materializer
+}
+
+// SetAggregatorExporter registers the service the periodic aggregator export runs against
+func (j *JobService) SetAggregatorExporter(exporter AggregatorExporter) {
+	j.aggregatorExporter = exporter
+}
+
 func (j *JobService) Start() error {
 	// Register task handlers
 	mux := asynq.NewServeMux()
 	mux.HandleFunc(TaskWelcome, j.handleWelcomeEmailTask)
+	mux.HandleFunc(TaskMissedVisitAlert, j.handleMissedVisitEmailTask)
+	mux.HandleFunc(TaskMissedVisitScan, j.handleMissedVisitScanTask)
+	mux.HandleFunc(TaskMaterializeSeries, j.handleMaterializeSeriesTask)
+	mux.HandleFunc(TaskAggregatorExport, j.handleAggregatorExportTask)
 
 	j.logger.Info().Msg("Starting background job server")
 	if err := j.server.Start(mux); err != nil {
 		return err
 	}
 
+	// Register periodic tasks
+	interval := j.evvConfig.MissedVisitScanInterval
+	if _, err := j.scheduler.Register(fmt.Sprintf("@every %s", interval), NewMissedVisitScanTask(interval)); err != nil {
+		return fmt.Errorf("failed to register missed visit scan: %w", err)
+	}
+
+	interval = j.evvConfig.SeriesMaterializeInterval
+	if _, err := j.scheduler.Register(fmt.Sprintf("@every %s", interval), NewMaterializeSeriesTask(interval)); err != nil {
+		return fmt.Errorf("failed to register series materialization: %w", err)
+	}
+
+	// The aggregator export only runs periodically when an interval is configured
+	if interval = j.aggregatorConfig.ExportInterval; interval > 0 {
+		if _, err := j.scheduler.Register(fmt.Sprintf("@every %s", interval), NewAggregatorExportTask(interval)); err != nil {
+			return fmt.Errorf("failed to register aggregator export: %w", err)
+		}
+	}
+
+	j.logger.Info().Msg("Starting periodic job scheduler")
+	if err := j.scheduler.Start(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (j *JobService) Stop() {
 	j.logger.Info().Msg("Stopping background job server")
+	j.scheduler.Shutdown()
 	j.server.Shutdown()
 	j.Client.Close()
 }
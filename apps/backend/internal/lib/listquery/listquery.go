@@ -0,0 +1,409 @@
+// Package listquery parses the filters and sort of a list endpoint's query
+// string and turns them into parameterized SQL.
+//
+// Each listing declares a Schema of the fields it can be filtered on and
+// sorted by. A filter is written field[operator]=value, e.g.
+//
+//	status[in]=upcoming,in_progress&scheduledStart[gte]=2024-03-01&hasExceptions=true
+//
+// A bare field=value compares for equality, or membership when the value
+// lists several comma-separated values and the field supports "in". The sort
+// is sort=field or sort=field:desc. Column expressions come from the schema
+// only; values are always passed as query arguments.
+package listquery
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+)
+
+// SortParam is the query parameter that selects the sort
+const SortParam = "sort"
+
+// Kind is the type of a field's values
+type Kind int
+
+const (
+	Text Kind = iota
+	UUID
+	Time
+	Bool
+)
+
+// sqlTypes casts a cursor position, which is kept as text, to a column's type
+var sqlTypes = map[Kind]string{
+	Text: "text",
+	UUID: "uuid",
+	Time: "timestamptz",
+	Bool: "boolean",
+}
+
+// Operator compares a field with the value of a filter
+type Operator string
+
+const (
+	Eq  Operator = "eq"
+	Ne  Operator = "ne"
+	In  Operator = "in"
+	Gt  Operator = "gt"
+	Gte Operator = "gte"
+	Lt  Operator = "lt"
+	Lte Operator = "lte"
+)
+
+var sqlOperators = map[Operator]string{
+	Eq:  "=",
+	Ne:  "<>",
+	Gt:  ">",
+	Gte: ">=",
+	Lt:  "<",
+	Lte: "<=",
+}
+
+// Ranges are the operators of fields compared by order, such as timestamps
+var Ranges = []Operator{Eq, Gt, Gte, Lt, Lte}
+
+// Field is a column of a listing that can be filtered on, sorted by, or both
+type Field[T any] struct {
+	// Name is the field as written in the query string
+	Name string
+	// Column is the SQL expression of the field; it is never user input
+	Column    string
+	Kind      Kind
+	Operators []Operator
+	// Values restricts a Text field to an enumeration
+	Values []string
+	// Key returns a row's value of the field. It is set on the fields the
+	// listing can be sorted by, which must be indexed and NOT NULL.
+	Key func(T) any
+}
+
+// Schema declares the fields of a listing of T
+type Schema[T any] struct {
+	Fields []Field[T]
+	// ID returns a row's primary key, which breaks ties in every sort
+	ID func(T) uuid.UUID
+	// DefaultSort is the sort of a query without one, e.g. "createdAt:desc"
+	DefaultSort string
+}
+
+func (s *Schema[T]) field(name string) *Field[T] {
+	for i := range s.Fields {
+		if s.Fields[i].Name == name {
+			return &s.Fields[i]
+		}
+	}
+	return nil
+}
+
+// Condition is a parsed filter
+type Condition struct {
+	Field    string
+	Column   string
+	Operator Operator
+	// Value is a string, uuid.UUID, time.Time or bool, or a slice of one of
+	// them for In
+	Value any
+}
+
+// Sort is the order of a listing; rows with equal values are ordered by ID
+// in the same direction
+type Sort struct {
+	Field  string
+	Column string
+	Kind   Kind
+	Desc   bool
+}
+
+// String returns the sort as written in the query string
+func (s Sort) String() string {
+	if s.Desc {
+		return s.Field + ":desc"
+	}
+	return s.Field + ":asc"
+}
+
+// Query is a parsed filter and sort of a listing of T
+type Query[T any] struct {
+	Conditions []Condition
+	Sort       Sort
+
+	key func(T) any
+	id  func(T) uuid.UUID
+}
+
+// Parse reads the filters and sort of a listing from query parameters.
+// Parameters named in bound are read by the caller and skipped; any other
+// parameter must be a field of the schema. All problems are returned
+// together, one per parameter.
+func Parse[T any](values map[string][]string, schema *Schema[T], bound ...string) (*Query[T], []errs.FieldError) {
+	query := &Query[T]{id: schema.ID}
+	var fieldErrors []errs.FieldError
+
+	params := make([]string, 0, len(values))
+	for param := range values {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	for _, param := range params {
+		if param == SortParam || slices.Contains(bound, param) {
+			continue
+		}
+
+		name, operator, explicit := splitParam(param)
+		field := schema.field(name)
+		if field == nil {
+			fieldErrors = append(fieldErrors, errs.FieldError{Field: name, Error: "is not a field of this listing"})
+			continue
+		}
+
+		raw := make([]string, 0, len(values[param]))
+		for _, value := range values[param] {
+			if value != "" {
+				raw = append(raw, value)
+			}
+		}
+		if len(raw) == 0 {
+			continue
+		}
+
+		if !explicit {
+			operator = Eq
+			if len(raw) > 1 || strings.Contains(raw[0], ",") {
+				operator = In
+			}
+		}
+		if !slices.Contains(field.Operators, operator) {
+			fieldErrors = append(fieldErrors, errs.FieldError{
+				Field: param,
+				Error: fmt.Sprintf("does not support operator %q; use one of: %s", operator, joinOperators(field.Operators)),
+			})
+			continue
+		}
+
+		if operator == In {
+			value, err := field.parseList(strings.Split(strings.Join(raw, ","), ","))
+			if err != nil {
+				fieldErrors = append(fieldErrors, errs.FieldError{Field: param, Error: err.Error()})
+				continue
+			}
+			query.Conditions = append(query.Conditions, Condition{Field: field.Name, Column: field.Column, Operator: In, Value: value})
+			continue
+		}
+
+		for _, r := range raw {
+			value, err := field.parse(r)
+			if err != nil {
+				fieldErrors = append(fieldErrors, errs.FieldError{Field: param, Error: err.Error()})
+				break
+			}
+			query.Conditions = append(query.Conditions, Condition{Field: field.Name, Column: field.Column, Operator: operator, Value: value})
+		}
+	}
+
+	sortValue := schema.DefaultSort
+	if s := strings.TrimSpace(firstValue(values, SortParam)); s != "" {
+		sortValue = s
+	}
+	if err := query.setSort(schema, sortValue); err != nil {
+		fieldErrors = append(fieldErrors, *err)
+	}
+
+	if fieldErrors != nil {
+		return nil, fieldErrors
+	}
+	return query, nil
+}
+
+func (q *Query[T]) setSort(schema *Schema[T], value string) *errs.FieldError {
+	name, direction, _ := strings.Cut(value, ":")
+	field := schema.field(name)
+	if field == nil || field.Key == nil {
+		sortable := make([]string, 0, len(schema.Fields))
+		for _, f := range schema.Fields {
+			if f.Key != nil {
+				sortable = append(sortable, f.Name)
+			}
+		}
+		return &errs.FieldError{Field: SortParam, Error: fmt.Sprintf("must be one of: %s", strings.Join(sortable, " "))}
+	}
+	if direction != "" && direction != "asc" && direction != "desc" {
+		return &errs.FieldError{Field: SortParam, Error: "direction must be asc or desc"}
+	}
+
+	q.Sort = Sort{Field: field.Name, Column: field.Column, Kind: field.Kind, Desc: direction == "desc"}
+	q.key = field.Key
+	return nil
+}
+
+// Where returns the conditions as a SQL boolean expression, with their
+// arguments numbered from n
+func (q *Query[T]) Where(n int) (string, []any) {
+	if len(q.Conditions) == 0 {
+		return "TRUE", nil
+	}
+
+	clauses := make([]string, 0, len(q.Conditions))
+	args := make([]any, 0, len(q.Conditions))
+	for _, c := range q.Conditions {
+		if c.Operator == In {
+			clauses = append(clauses, fmt.Sprintf("(%s) = ANY($%d)", c.Column, n))
+		} else {
+			clauses = append(clauses, fmt.Sprintf("(%s) %s $%d", c.Column, sqlOperators[c.Operator], n))
+		}
+		args = append(args, c.Value)
+		n++
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+// OrderBy returns the ORDER BY clause of the sort, or of its reverse when
+// reading backward from a position
+func (q *Query[T]) OrderBy(backward bool) string {
+	direction := "ASC"
+	if q.Sort.Desc != backward {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("ORDER BY %s %s, id %s", q.Sort.Column, direction, direction)
+}
+
+// After returns the condition that selects the rows after a position in the
+// sort, or before it when backward, with its arguments numbered from n
+func (q *Query[T]) After(value string, id uuid.UUID, backward bool, n int) (string, []any) {
+	operator := ">"
+	if q.Sort.Desc != backward {
+		operator = "<"
+	}
+	condition := fmt.Sprintf("(%s, id) %s ($%d::%s, $%d)", q.Sort.Column, operator, n, sqlTypes[q.Sort.Kind], n+1)
+	return condition, []any{value, id}
+}
+
+// Position returns a row's place in the sort: its value of the sort field
+// as text, and its ID
+func (q *Query[T]) Position(row T) (string, uuid.UUID) {
+	return formatValue(q.key(row)), q.id(row)
+}
+
+// ValidPosition reports whether value can be a position in the sort, e.g.
+// one decoded from a cursor
+func (q *Query[T]) ValidPosition(value string) bool {
+	switch q.Sort.Kind {
+	case UUID:
+		_, err := uuid.Parse(value)
+		return err == nil
+	case Time:
+		_, err := time.Parse(time.RFC3339Nano, value)
+		return err == nil
+	case Bool:
+		_, err := strconv.ParseBool(value)
+		return err == nil
+	}
+	return true
+}
+
+func (f *Field[T]) parse(raw string) (any, error) {
+	raw = strings.TrimSpace(raw)
+	switch f.Kind {
+	case UUID:
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be a valid UUID")
+		}
+		return id, nil
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		if t, err := time.Parse(time.DateOnly, raw); err == nil {
+			return t, nil
+		}
+		return nil, fmt.Errorf("must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	case Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be true or false")
+		}
+		return b, nil
+	}
+
+	if len(f.Values) > 0 && !slices.Contains(f.Values, raw) {
+		return nil, fmt.Errorf("must be one of: %s", strings.Join(f.Values, " "))
+	}
+	return raw, nil
+}
+
+// parseList parses the values of an In filter into a slice of their type,
+// so that it is sent as an array argument
+func (f *Field[T]) parseList(raw []string) (any, error) {
+	parsed := make([]any, 0, len(raw))
+	for _, r := range raw {
+		value, err := f.parse(r)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, value)
+	}
+
+	switch f.Kind {
+	case UUID:
+		return typedList[uuid.UUID](parsed), nil
+	case Time:
+		return typedList[time.Time](parsed), nil
+	case Bool:
+		return typedList[bool](parsed), nil
+	}
+	return typedList[string](parsed), nil
+}
+
+func typedList[V any](values []any) []V {
+	typed := make([]V, len(values))
+	for i, v := range values {
+		typed[i] = v.(V)
+	}
+	return typed
+}
+
+// formatValue writes a sort value as text that the column's type parses back
+// exactly
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case string:
+		return v
+	}
+	return fmt.Sprint(value)
+}
+
+// splitParam splits "field[operator]" into its parts; explicit is false for
+// a bare field name
+func splitParam(param string) (string, Operator, bool) {
+	name, rest, ok := strings.Cut(param, "[")
+	if !ok || !strings.HasSuffix(rest, "]") {
+		return param, "", false
+	}
+	return name, Operator(strings.TrimSuffix(rest, "]")), true
+}
+
+func joinOperators(operators []Operator) string {
+	names := make([]string, len(operators))
+	for i, o := range operators {
+		names[i] = string(o)
+	}
+	return strings.Join(names, " ")
+}
+
+func firstValue(values map[string][]string, key string) string {
+	if v := values[key]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package listquery
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type row struct {
+	ID      uuid.UUID
+	Status  string
+	Created time.Time
+}
+
+var schema = &Schema[row]{
+	Fields: []Field[row]{
+		{Name: "status", Column: "status", Kind: Text, Operators: []Operator{Eq, Ne, In}, Values: []string{"open", "closed"},
+			Key: func(r row) any { return r.Status }},
+		{Name: "ownerId", Column: "owner_id", Kind: UUID, Operators: []Operator{Eq, In}},
+		{Name: "flagged", Column: "EXISTS (SELECT 1 FROM flags f WHERE f.row_id = rows.id)", Kind: Bool, Operators: []Operator{Eq}},
+		{Name: "createdAt", Column: "created_at", Kind: Time, Operators: Ranges,
+			Key: func(r row) any { return r.Created }},
+	},
+	ID:          func(r row) uuid.UUID { return r.ID },
+	DefaultSort: "createdAt:desc",
+}
+
+func parse(t *testing.T, query string, bound ...string) (*Query[row], []errs.FieldError) {
+	values, err := url.ParseQuery(query)
+	require.NoError(t, err)
+	return Parse(values, schema, bound...)
+}
+
+func TestParse_Where(t *testing.T) {
+	owner := uuid.New()
+	query, fieldErrors := parse(t, "status=open,closed&ownerId[eq]="+owner.String()+
+		"&createdAt[gte]=2024-03-01&createdAt[lt]=2024-03-08T00:00:00Z&flagged=true&cursor=abc", "cursor")
+	require.Nil(t, fieldErrors)
+
+	where, args := query.Where(3)
+	assert.Equal(t, "(created_at) >= $3 AND (created_at) < $4 AND (EXISTS (SELECT 1 FROM flags f WHERE f.row_id = rows.id)) = $5 AND (owner_id) = $6 AND (status) = ANY($7)", where)
+	assert.Equal(t, []any{
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC),
+		true,
+		owner,
+		[]string{"open", "closed"},
+	}, args)
+}
+
+func TestParse_NoFilters(t *testing.T) {
+	query, fieldErrors := parse(t, "status=")
+	require.Nil(t, fieldErrors)
+
+	where, args := query.Where(1)
+	assert.Equal(t, "TRUE", where)
+	assert.Empty(t, args)
+	assert.Equal(t, "createdAt:desc", query.Sort.String())
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, fieldErrors := parse(t, "color=red&status[like]=op&status[ne]=archived&ownerId=nope&createdAt[gt]=yesterday&sort=ownerId")
+	assert.Equal(t, []errs.FieldError{
+		{Field: "color", Error: "is not a field of this listing"},
+		{Field: "createdAt[gt]", Error: "must be an RFC 3339 timestamp or a YYYY-MM-DD date"},
+		{Field: "ownerId", Error: "must be a valid UUID"},
+		{Field: "status[like]", Error: `does not support operator "like"; use one of: eq ne in`},
+		{Field: "status[ne]", Error: "must be one of: open closed"},
+		{Field: "sort", Error: "must be one of: status createdAt"},
+	}, fieldErrors)
+
+	_, fieldErrors = parse(t, "flagged=true,false&sort=status:up")
+	assert.Equal(t, []errs.FieldError{
+		{Field: "flagged", Error: `does not support operator "in"; use one of: eq`},
+		{Field: "sort", Error: "direction must be asc or desc"},
+	}, fieldErrors)
+}
+
+func TestQuery_Keyset(t *testing.T) {
+	query, fieldErrors := parse(t, "sort=status")
+	require.Nil(t, fieldErrors)
+	assert.Equal(t, "status:asc", query.Sort.String())
+	assert.Equal(t, "ORDER BY status ASC, id ASC", query.OrderBy(false))
+	assert.Equal(t, "ORDER BY status DESC, id DESC", query.OrderBy(true))
+
+	id := uuid.New()
+	condition, args := query.After("open", id, false, 2)
+	assert.Equal(t, "(status, id) > ($2::text, $3)", condition)
+	assert.Equal(t, []any{"open", id}, args)
+
+	query, _ = parse(t, "")
+	condition, _ = query.After("", id, false, 1)
+	assert.Equal(t, "(created_at, id) < ($1::timestamptz, $2)", condition)
+	condition, _ = query.After("", id, true, 1)
+	assert.Equal(t, "(created_at, id) > ($1::timestamptz, $2)", condition)
+}
+
+func TestQuery_Position(t *testing.T) {
+	query, _ := parse(t, "")
+	r := row{ID: uuid.New(), Created: time.Date(2024, 3, 4, 15, 0, 0, 123456000, time.FixedZone("EST", -5*3600))}
+
+	value, id := query.Position(r)
+	assert.Equal(t, "2024-03-04T20:00:00.123456Z", value)
+	assert.Equal(t, r.ID, id)
+	assert.True(t, query.ValidPosition(value))
+	assert.False(t, query.ValidPosition("open"))
+}
@@ -0,0 +1,49 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+func renderCSV(table *Table) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		header[i] = column.Name
+	}
+	if err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i, cell := range row {
+			record[i] = cell
+			if !table.Columns[i].Numeric {
+				record[i] = escapeFormula(cell)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// escapeFormula keeps spreadsheets from evaluating text that starts like a
+// formula, such as a caregiver's note reading "=HYPERLINK(...)"
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
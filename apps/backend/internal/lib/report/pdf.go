@@ -0,0 +1,185 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Landscape US letter in points, with half-inch margins
+const (
+	pdfPageWidth  = 792.0
+	pdfPageHeight = 612.0
+	pdfMargin     = 36.0
+	pdfFontSize   = 8.0
+	pdfTitleSize  = 12.0
+	pdfLineHeight = 11.0
+	pdfCellPad    = 4.0
+	// Average Helvetica glyph width as a fraction of the font size; columns
+	// are sized from it, so text is measured generously
+	pdfCharWidth = 0.55
+	// Longest cell, in characters, that widens its column
+	pdfMaxColumnChars = 40
+)
+
+func renderPDF(table *Table) []byte {
+	widths := pdfColumnWidths(table)
+
+	// The title and header row are repeated on every page
+	top := pdfPageHeight - pdfMargin
+	rowsPerPage := int((top - pdfTitleSize - 2*pdfLineHeight - pdfMargin) / pdfLineHeight)
+	pageCount := (len(table.Rows) + rowsPerPage - 1) / rowsPerPage
+	if pageCount == 0 {
+		pageCount = 1
+	}
+
+	pages := make([]string, 0, pageCount)
+	for p := 0; p < pageCount; p++ {
+		var content strings.Builder
+		pdfText(&content, "F2", pdfTitleSize, pdfMargin, top-pdfTitleSize, table.Title)
+		footer := fmt.Sprintf("Page %d of %d", p+1, pageCount)
+		pdfText(&content, "F1", pdfFontSize, pdfPageWidth-pdfMargin-pdfTextWidth(footer), pdfMargin/2, footer)
+
+		y := top - pdfTitleSize - 1.5*pdfLineHeight
+		header := make([]string, len(table.Columns))
+		for i, column := range table.Columns {
+			header[i] = column.Name
+		}
+		pdfRow(&content, "F2", table, widths, y, header)
+		fmt.Fprintf(&content, "%.2f %.2f m %.2f %.2f l S\n", pdfMargin, y-3, pdfPageWidth-pdfMargin, y-3)
+
+		end := min((p+1)*rowsPerPage, len(table.Rows))
+		for _, row := range table.Rows[p*rowsPerPage : end] {
+			y -= pdfLineHeight
+			pdfRow(&content, "F1", table, widths, y, row)
+		}
+		pages = append(pages, content.String())
+	}
+
+	return pdfDocument(pages)
+}
+
+// pdfColumnWidths sizes columns to their content, shrunk proportionally to
+// fit the page
+func pdfColumnWidths(table *Table) []float64 {
+	widths := make([]float64, len(table.Columns))
+	total := 0.0
+	for i, column := range table.Columns {
+		chars := len([]rune(column.Name))
+		for _, row := range table.Rows {
+			chars = max(chars, min(len([]rune(row[i])), pdfMaxColumnChars))
+		}
+		widths[i] = float64(chars)*pdfCharWidth*pdfFontSize + 2*pdfCellPad
+		total += widths[i]
+	}
+
+	if available := pdfPageWidth - 2*pdfMargin; total > available {
+		for i := range widths {
+			widths[i] *= available / total
+		}
+	}
+	return widths
+}
+
+// pdfRow writes the cells of a row at baseline y, cut to their column widths
+func pdfRow(b *strings.Builder, font string, table *Table, widths []float64, y float64, cells []string) {
+	x := pdfMargin
+	for i, cell := range cells {
+		room := widths[i] - 2*pdfCellPad
+		text := pdfFit(cell, room)
+		tx := x + pdfCellPad
+		if table.Columns[i].Numeric {
+			tx = x + widths[i] - pdfCellPad - pdfTextWidth(text)
+		}
+		pdfText(b, font, pdfFontSize, tx, y, text)
+		x += widths[i]
+	}
+}
+
+// pdfFit shortens text with an ellipsis until it fits width
+func pdfFit(text string, width float64) string {
+	runes := []rune(text)
+	if pdfTextWidth(text) <= width {
+		return text
+	}
+	for len(runes) > 0 && pdfTextWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	if len(runes) == 0 {
+		return ""
+	}
+	return string(runes) + "..."
+}
+
+func pdfTextWidth(text string) float64 {
+	return float64(len([]rune(text))) * pdfCharWidth * pdfFontSize
+}
+
+func pdfText(b *strings.Builder, font string, size, x, y float64, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(b, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(text))
+}
+
+// pdfEscape encodes text for a literal string in WinAnsiEncoding. Characters
+// outside Latin-1 are replaced with "?".
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r < 0x20 || r > 0xff || (r >= 0x7f && r < 0xa0):
+			b.WriteByte('?')
+		case r >= 0x80:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// pdfDocument assembles the page content streams into a PDF file
+func pdfDocument(pages []string) []byte {
+	// Objects 1 and 2 are the catalog and page tree, 3 and 4 the fonts, then
+	// each page and its content stream
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	kids := make([]string, len(pages))
+	for i, content := range pages {
+		pageID := len(objects) + 1
+		kids[i] = fmt.Sprintf("%d 0 R", pageID)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, pageID+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
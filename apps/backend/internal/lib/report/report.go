@@ -0,0 +1,95 @@
+// Package report renders tabular reports as CSV, XLSX or PDF files.
+//
+// The writers only use the standard library: XLSX is a minimal
+// SpreadsheetML workbook with one sheet, and PDF is a landscape letter
+// document in the standard Helvetica font, which covers Latin-1 text.
+package report
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// Format is the file type of a rendered report
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+	PDF  Format = "pdf"
+)
+
+// Formats are the supported formats; the first is the default
+var Formats = []Format{CSV, XLSX, PDF}
+
+var contentTypes = map[Format]string{
+	CSV:  "text/csv; charset=utf-8",
+	XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	PDF:  "application/pdf",
+}
+
+// ContentType returns the media type of the format
+func (f Format) ContentType() string {
+	return contentTypes[f]
+}
+
+// Negotiate picks the format of a request. An explicit format wins;
+// otherwise the first media type of the Accept header that names a
+// supported format is used, and CSV when none does.
+func Negotiate(format, accept string) (Format, error) {
+	if format != "" {
+		f := Format(strings.ToLower(format))
+		if _, ok := contentTypes[f]; !ok {
+			return "", fmt.Errorf("unsupported report format %q", format)
+		}
+		return f, nil
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		for _, f := range Formats {
+			if t, _, _ := mime.ParseMediaType(f.ContentType()); t == mediaType {
+				return f, nil
+			}
+		}
+	}
+
+	return Formats[0], nil
+}
+
+// Column is a column of a table. Numeric columns are written as numbers
+// where the format has them and right-aligned otherwise.
+type Column struct {
+	Name    string
+	Numeric bool
+}
+
+// Table is the content of a report: a title and rows of formatted cells,
+// one per column
+type Table struct {
+	Title   string
+	Columns []Column
+	Rows    [][]string
+}
+
+// Render writes the table in the given format
+func Render(format Format, table *Table) ([]byte, error) {
+	switch format {
+	case CSV:
+		return renderCSV(table)
+	case XLSX:
+		return renderXLSX(table)
+	case PDF:
+		return renderPDF(table), nil
+	}
+	return nil, fmt.Errorf("unsupported report format %q", format)
+}
+
+// Filename returns the download name of a report, e.g. "visit-log-2024-03-01.csv"
+func Filename(name string, format Format) string {
+	return name + "." + string(format)
+}
@@ -0,0 +1,110 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var table = &Table{
+	Title:   "Visit Log: 2024-03-01 to 2024-03-08",
+	Columns: []Column{{Name: "Client"}, {Name: "Minutes", Numeric: true}},
+	Rows: [][]string{
+		{"Jane Doe", "60"},
+		{"=SUM(A1)", "-15"},
+		{"Zoë (Smith) & Co", ""},
+	},
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		format, accept string
+		want           Format
+	}{
+		{"", "", CSV},
+		{"XLSX", "application/pdf", XLSX},
+		{"", "application/pdf", PDF},
+		{"", "text/html, application/vnd.openxmlformats-officedocument.spreadsheetml.sheet;q=0.9, */*", XLSX},
+		{"", "text/csv; charset=utf-8", CSV},
+		{"", "application/json", CSV},
+	}
+	for _, tt := range tests {
+		got, err := Negotiate(tt.format, tt.accept)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, tt)
+	}
+
+	_, err := Negotiate("docx", "")
+	assert.Error(t, err)
+}
+
+func TestRender_CSV(t *testing.T) {
+	data, err := Render(CSV, table)
+	require.NoError(t, err)
+	assert.Equal(t, "Client,Minutes\nJane Doe,60\n'=SUM(A1),-15\nZoë (Smith) & Co,\n", string(data))
+}
+
+func TestRender_XLSX(t *testing.T) {
+	data, err := Render(XLSX, table)
+	require.NoError(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	parts := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		parts[f.Name] = string(content)
+	}
+
+	assert.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts["xl/workbook.xml"], `name="Visit Log- 2024-03-01 to 2024-0"`)
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="B2"><v>60</v></c>`)
+	assert.Contains(t, sheet, `<c r="B3"><v>-15</v></c>`)
+	assert.Contains(t, sheet, `<t xml:space="preserve">Zoë (Smith) &amp; Co</t>`)
+	assert.NotContains(t, sheet, `r="B4"`)
+}
+
+func TestRender_PDF(t *testing.T) {
+	rows := make([][]string, 120)
+	for i := range rows {
+		rows[i] = []string{"Client " + strconv.Itoa(i), strconv.Itoa(i)}
+	}
+	data, err := Render(PDF, &Table{Title: table.Title, Columns: table.Columns, Rows: append(rows, table.Rows...)})
+	require.NoError(t, err)
+
+	pdf := string(data)
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, "/Count 3")
+	assert.Contains(t, pdf, "(Page 3 of 3)")
+	assert.Contains(t, pdf, `(Zo\353 \(Smith\) & Co)`)
+
+	// Every object is where the cross-reference table says it is
+	xref := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(pdf, -1)
+	require.NotEmpty(t, xref)
+	for i, entry := range xref {
+		offset, err := strconv.Atoi(entry[1])
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(pdf[offset:], strconv.Itoa(i+1)+" 0 obj"), "object %d", i+1)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "BA", columnName(52))
+}
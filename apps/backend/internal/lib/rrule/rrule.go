@@ -0,0 +1,275 @@
+package rrule
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ part of a recurrence rule
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Rule is the subset of an RFC 5545 recurrence rule that schedules need:
+// FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, BYDAY without ordinals,
+// BYMONTHDAY, WKST, COUNT and UNTIL. Rules recur on calendar dates;
+// the time of day is kept by the caller.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	WeekStart  time.Weekday
+	// Count limits the number of occurrences; zero means unlimited
+	Count int
+	// Until is the last date an occurrence may fall on, inclusive
+	Until *time.Time
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE,FR".
+// A leading "RRULE:" is accepted.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("rrule is empty")
+	}
+
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("invalid rrule part %q", part)
+		}
+		key = strings.ToUpper(key)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate rrule part %s", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			freq := Frequency(strings.ToUpper(val))
+			if freq != Daily && freq != Weekly && freq != Monthly {
+				return nil, fmt.Errorf("unsupported FREQ %s (must be DAILY, WEEKLY or MONTHLY)", val)
+			}
+			rule.Freq = freq
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("INTERVAL must be a positive integer")
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("COUNT must be a positive integer")
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported BYDAY value %s", day)
+				}
+				if !slices.Contains(rule.ByDay, weekday) {
+					rule.ByDay = append(rule.ByDay, weekday)
+				}
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY value %s", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, monthDay)
+			}
+		case "WKST":
+			weekday, ok := weekdays[strings.ToUpper(val)]
+			if !ok {
+				return nil, fmt.Errorf("invalid WKST value %s", val)
+			}
+			rule.WeekStart = weekday
+		default:
+			return nil, fmt.Errorf("unsupported rrule part %s", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot both be set")
+	}
+	if rule.Freq == Weekly && len(rule.ByMonthDay) > 0 {
+		return nil, fmt.Errorf("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+
+	return rule, nil
+}
+
+// String formats the rule as an RRULE value
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, weekday := range r.ByDay {
+			days[i] = weekdayCode(weekday)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayCode(r.WeekStart))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between returns the occurrence dates of a series starting on start that
+// fall within [from, to]. COUNT is counted from start, so occurrences before
+// from still use up the count. All dates are returned at midnight UTC.
+func (r *Rule) Between(start, from, to time.Time) []time.Time {
+	start, from, to = Date(start), Date(from), Date(to)
+	if r.Until != nil && r.Until.Before(to) {
+		to = Date(*r.Until)
+	}
+
+	dates := make([]time.Time, 0)
+	count := 0
+	for day := start; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !r.matches(start, day) {
+			continue
+		}
+
+		count++
+		if r.Count > 0 && count > r.Count {
+			break
+		}
+		if !day.Before(from) {
+			dates = append(dates, day)
+		}
+	}
+
+	return dates
+}
+
+// Date truncates t to its calendar date at midnight UTC
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (r *Rule) matches(start, day time.Time) bool {
+	switch r.Freq {
+	case Daily:
+		if daysBetween(start, day)%r.Interval != 0 {
+			return false
+		}
+		return r.matchesByDay(day, true) && r.matchesByMonthDay(day, true)
+	case Weekly:
+		weeks := daysBetween(r.weekStartOf(start), r.weekStartOf(day)) / 7
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == start.Weekday()
+		}
+		return r.matchesByDay(day, false)
+	case Monthly:
+		months := (day.Year()-start.Year())*12 + int(day.Month()) - int(start.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+			return day.Day() == start.Day()
+		}
+		return r.matchesByDay(day, true) && r.matchesByMonthDay(day, true)
+	}
+	return false
+}
+
+func (r *Rule) matchesByDay(day time.Time, emptyMatches bool) bool {
+	if len(r.ByDay) == 0 {
+		return emptyMatches
+	}
+	return slices.Contains(r.ByDay, day.Weekday())
+}
+
+func (r *Rule) matchesByMonthDay(day time.Time, emptyMatches bool) bool {
+	if len(r.ByMonthDay) == 0 {
+		return emptyMatches
+	}
+	// Negative values count back from the end of the month
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, monthDay := range r.ByMonthDay {
+		if monthDay == day.Day() || daysInMonth+monthDay+1 == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) weekStartOf(day time.Time) time.Time {
+	offset := (int(day.Weekday()) - int(r.WeekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func weekdayCode(weekday time.Weekday) string {
+	for code, day := range weekdays {
+		if day == weekday {
+			return code
+		}
+	}
+	return ""
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if until, err := time.Parse(layout, value); err == nil {
+			return Date(until), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL value %s", value)
+}
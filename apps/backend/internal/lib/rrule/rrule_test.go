@@ -0,0 +1,89 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	rule, err := Parse("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=6")
+	require.NoError(t, err)
+	assert.Equal(t, Weekly, rule.Freq)
+	assert.Equal(t, 2, rule.Interval)
+	assert.Equal(t, []time.Weekday{time.Monday, time.Friday}, rule.ByDay)
+	assert.Equal(t, 6, rule.Count)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=6", rule.String())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ=DAILY;FREQ=WEEKLY",
+	} {
+		_, err := Parse(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestBetween_Weekly(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=MO,WE,FR")
+	require.NoError(t, err)
+
+	// 2024-03-04 is a Monday
+	dates := rule.Between(date(2024, 3, 4), date(2024, 3, 6), date(2024, 3, 11))
+	assert.Equal(t, []time.Time{date(2024, 3, 6), date(2024, 3, 8), date(2024, 3, 11)}, dates)
+}
+
+func TestBetween_WeeklyInterval(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;INTERVAL=2")
+	require.NoError(t, err)
+
+	dates := rule.Between(date(2024, 3, 5), date(2024, 3, 1), date(2024, 4, 5))
+	assert.Equal(t, []time.Time{date(2024, 3, 5), date(2024, 3, 19), date(2024, 4, 2)}, dates)
+}
+
+func TestBetween_CountIncludesEarlierOccurrences(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;COUNT=5")
+	require.NoError(t, err)
+
+	dates := rule.Between(date(2024, 3, 1), date(2024, 3, 4), date(2024, 3, 31))
+	assert.Equal(t, []time.Time{date(2024, 3, 4), date(2024, 3, 5)}, dates)
+}
+
+func TestBetween_Until(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;INTERVAL=3;UNTIL=20240307T235959Z")
+	require.NoError(t, err)
+
+	dates := rule.Between(date(2024, 3, 1), date(2024, 3, 1), date(2024, 3, 31))
+	assert.Equal(t, []time.Time{date(2024, 3, 1), date(2024, 3, 4), date(2024, 3, 7)}, dates)
+}
+
+func TestBetween_MonthlySkipsShortMonths(t *testing.T) {
+	rule, err := Parse("FREQ=MONTHLY")
+	require.NoError(t, err)
+
+	dates := rule.Between(date(2024, 1, 31), date(2024, 1, 1), date(2024, 5, 31))
+	assert.Equal(t, []time.Time{date(2024, 1, 31), date(2024, 3, 31), date(2024, 5, 31)}, dates)
+}
+
+func TestBetween_MonthlyLastDay(t *testing.T) {
+	rule, err := Parse("FREQ=MONTHLY;BYMONTHDAY=-1")
+	require.NoError(t, err)
+
+	dates := rule.Between(date(2024, 1, 1), date(2024, 1, 1), date(2024, 3, 31))
+	assert.Equal(t, []time.Time{date(2024, 1, 31), date(2024, 2, 29), date(2024, 3, 31)}, dates)
+}
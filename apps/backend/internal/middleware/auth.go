@@ -3,6 +3,7 @@ package middleware
 import (
 	"encoding/json"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/clerk/clerk-sdk-go/v2"
@@ -12,6 +13,12 @@ import (
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
 )
 
+// Clerk organization roles
+const (
+	RoleAdmin       = "org:admin"
+	RoleCoordinator = "org:coordinator"
+)
+
 type AuthMiddleware struct {
 	server *server.Server
 }
@@ -72,3 +79,24 @@ func (auth *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc
 		return next(c)
 	})
 }
+
+// RequireRole allows the request through only when the authenticated user's
+// active organization role is one of roles. It must run after RequireAuth.
+func (auth *AuthMiddleware) RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role := GetUserRole(c)
+			if slices.Contains(roles, role) {
+				return next(c)
+			}
+
+			auth.server.Logger.Warn().
+				Str("function", "RequireRole").
+				Str("user_id", GetUserID(c)).
+				Str("user_role", role).
+				Str("request_id", GetRequestID(c)).
+				Msg("user does not have a required role")
+			return errs.NewForbiddenError("You do not have permission to perform this action", false)
+		}
+	}
+}
@@ -87,6 +87,13 @@ func GetUserID(c echo.Context) string {
 	return ""
 }
 
+func GetUserRole(c echo.Context) string {
+	if role, ok := c.Get(UserRoleKey).(string); ok {
+		return role
+	}
+	return ""
+}
+
 func GetLogger(c echo.Context) *zerolog.Logger {
 	if logger, ok := c.Get(LoggerKey).(*zerolog.Logger); ok {
 		return logger
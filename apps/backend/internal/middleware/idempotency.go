@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from a stored request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored along with the body
+var replayedHeaders = []string{echo.HeaderContentType, echo.HeaderContentDisposition, echo.HeaderLocation}
+
+type IdempotencyMiddleware struct {
+	server *server.Server
+}
+
+func NewIdempotencyMiddleware(s *server.Server) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		server: s,
+	}
+}
+
+// idempotencyRecord is kept in Redis for a key. A record without a status
+// belongs to a request that is still running.
+type idempotencyRecord struct {
+	Fingerprint string            `json:"fingerprint"`
+	Status      int               `json:"status,omitempty"`
+	Header      map[string]string `json:"header,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// bodyRecorder copies the response body as it is written
+type bodyRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *bodyRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Idempotent replays the stored response to a POST or PATCH retried with the
+// same Idempotency-Key. Keys are scoped to the authenticated user, so it must
+// run after RequireAuth; a key sent without a user is rejected rather than
+// ignored. Responses below 500 are stored; a server error releases the key so
+// that the request can be retried.
+func (m *IdempotencyMiddleware) Idempotent(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		key := req.Header.Get(IdempotencyKeyHeader)
+		if key == "" || req.Method != http.MethodPost && req.Method != http.MethodPatch {
+			return next(c)
+		}
+
+		// Keys of unauthenticated callers would share one key space
+		userID := GetUserID(c)
+		if userID == "" {
+			return errs.NewUnauthorizedError("Idempotency-Key requires an authenticated request", true)
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			return errs.NewBadRequestError("Idempotency-Key must be at most 255 characters", true, nil, nil, nil)
+		}
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return errs.NewBadRequestError("Failed to read request body", false, nil, nil, nil)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := req.Context()
+		redisKey := idempotencyRedisKey(userID, key)
+		fingerprint := requestFingerprint(req.Method, req.URL.Path, body)
+		cfg := m.server.Config.Idempotency
+
+		claim, err := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+		if err != nil {
+			return err
+		}
+
+		claimed, err := m.server.Redis.SetNX(ctx, redisKey, claim, cfg.LockTimeout).Result()
+		if err != nil {
+			// Without Redis the request runs as if it carried no key
+			GetLogger(c).Warn().Err(err).Msg("idempotency store unavailable")
+			return next(c)
+		}
+		if !claimed {
+			return m.replay(c, redisKey, fingerprint)
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+		if err := next(c); err != nil {
+			// Write the error response here so that it is recorded
+			c.Error(err)
+		}
+		c.Response().Writer = recorder.ResponseWriter
+
+		// The response has been sent; storing it must not depend on the client staying
+		ctx = context.WithoutCancel(ctx)
+
+		status := c.Response().Status
+		if status >= http.StatusInternalServerError {
+			if err := m.server.Redis.Del(ctx, redisKey).Err(); err != nil {
+				GetLogger(c).Error().Err(err).Msg("failed to release idempotency key")
+			}
+			return nil
+		}
+
+		record := idempotencyRecord{
+			Fingerprint: fingerprint,
+			Status:      status,
+			Header:      make(map[string]string),
+			Body:        recorder.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if value := c.Response().Header().Get(name); value != "" {
+				record.Header[name] = value
+			}
+		}
+
+		data, err := json.Marshal(record)
+		if err == nil {
+			err = m.server.Redis.Set(ctx, redisKey, data, cfg.TTL).Err()
+		}
+		if err != nil {
+			GetLogger(c).Error().Err(err).Msg("failed to store idempotent response")
+		}
+
+		return nil
+	}
+}
+
+// replay answers a request whose key is already taken
+func (m *IdempotencyMiddleware) replay(c echo.Context, redisKey, fingerprint string) error {
+	inProgressCode := "IDEMPOTENCY_KEY_IN_PROGRESS"
+	inProgress := errs.NewConflictError("A request with this Idempotency-Key is still being processed", true, &inProgressCode)
+
+	data, err := m.server.Redis.Get(c.Request().Context(), redisKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// The key was released by a request that failed; a retry claims it again
+			return inProgress
+		}
+		return err
+	}
+
+	var record idempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return err
+	}
+
+	if record.Fingerprint != fingerprint {
+		code := "IDEMPOTENCY_KEY_REUSED"
+		return errs.NewUnprocessableEntityError("Idempotency-Key was already used for a different request", true, &code)
+	}
+
+	if record.Status == 0 {
+		return inProgress
+	}
+
+	for name, value := range record.Header {
+		c.Response().Header().Set(name, value)
+	}
+	c.Response().Header().Set(IdempotentReplayedHeader, "true")
+	c.Response().WriteHeader(record.Status)
+	_, err = c.Response().Write(record.Body)
+	return err
+}
+
+func idempotencyRedisKey(userID, key string) string {
+	return "idempotency:" + userID + ":" + key
+}
+
+// requestFingerprint identifies a request by its method, path and body
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AggregatorBatchStatusPending   = "pending"
+	AggregatorBatchStatusDelivered = "delivered"
+	AggregatorBatchStatusFailed    = "failed"
+)
+
+const (
+	VisitSubmissionStatusPending   = "pending"
+	VisitSubmissionStatusSubmitted = "submitted"
+	VisitSubmissionStatusAccepted  = "accepted"
+	VisitSubmissionStatusRejected  = "rejected"
+	VisitSubmissionStatusFailed    = "failed"
+)
+
+// AggregatorBatch is a set of verified visits exported to the state EVV aggregator
+type AggregatorBatch struct {
+	Base
+	Format     string `json:"format" db:"format"`
+	Transport  string `json:"transport" db:"transport"`
+	Status     string `json:"status" db:"status"`
+	FileName   string `json:"fileName" db:"file_name"`
+	VisitCount int    `json:"visitCount" db:"visit_count"`
+	// Reference is the aggregator's reference for the batch, or the file path for a directory
+	Reference   *string    `json:"reference" db:"reference"`
+	Error       *string    `json:"error" db:"error"`
+	DeliveredAt *time.Time `json:"deliveredAt" db:"delivered_at"`
+}
+
+// VisitSubmission is a visit's submission in a batch. Submitted visits stay
+// submitted until the aggregator reports accepting or rejecting them.
+type VisitSubmission struct {
+	BatchID  uuid.UUID `json:"batchId" db:"batch_id"`
+	VisitID  uuid.UUID `json:"visitId" db:"visit_id"`
+	Status   string    `json:"status" db:"status"`
+	Messages []string  `json:"messages" db:"messages"`
+	BaseWithCreatedAt
+	BaseWithUpdatedAt
+}
+
+type AggregatorBatchWithSubmissions struct {
+	AggregatorBatch
+	Submissions []VisitSubmission `json:"submissions"`
+}
+
+func (b *AggregatorBatch) TableName() string {
+	return "aggregator_batches"
+}
+
+func (s *VisitSubmission) TableName() string {
+	return "visit_submissions"
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditEntitySchedule = "schedule"
+	AuditEntityVisit    = "visit"
+	AuditEntityTask     = "task"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditEvent is one write to an audited entity. Before and After hold the
+// changed columns only: a create has no Before and a delete has no After.
+type AuditEvent struct {
+	BaseWithId
+	Entity    string          `json:"entity" db:"entity"`
+	EntityID  uuid.UUID       `json:"entityId" db:"entity_id"`
+	Action    string          `json:"action" db:"action"`
+	Actor     *string         `json:"actor" db:"actor"`
+	RequestID *string         `json:"requestId" db:"request_id"`
+	Before    json.RawMessage `json:"before" db:"before"`
+	After     json.RawMessage `json:"after" db:"after"`
+	BaseWithCreatedAt
+}
+
+// AuditEventFilter narrows an audit trail query; zero fields are not filtered on
+type AuditEventFilter struct {
+	Entity   string
+	EntityID *uuid.UUID
+	Actor    string
+	From     *time.Time
+	To       *time.Time
+}
+
+func (e *AuditEvent) TableName() string {
+	return "audit_events"
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rules for the partial unit left after a visit's whole billable units
+const (
+	// RoundingEightMinute bills the partial unit when it is at least half a
+	// unit, as in the CMS 8-minute rule for 15-minute units
+	RoundingEightMinute = "eight_minute"
+	// RoundingDown never bills the partial unit
+	RoundingDown = "round_down"
+	// RoundingUp bills any partial unit
+	RoundingUp = "round_up"
+)
+
+var BillingRoundings = []string{RoundingEightMinute, RoundingDown, RoundingUp}
+
+// DefaultBillingUnitMinutes is the unit Medicaid bills most services in
+const DefaultBillingUnitMinutes = 15
+
+// Reasons a completed visit could not be billed
+const (
+	BillingIssueMissingPayer       = "missing_payer"
+	BillingIssueMissingServiceCode = "missing_service_code"
+	BillingIssueMissingRate        = "missing_rate"
+)
+
+// BillingRate is what a payer pays per unit of a service code. A rate with
+// a caregiver overrides the payer's general rate for that caregiver's visits.
+type BillingRate struct {
+	Base
+	Payer         string     `json:"payer" db:"payer"`
+	ServiceCode   string     `json:"serviceCode" db:"service_code"`
+	CaregiverID   *uuid.UUID `json:"caregiverId" db:"caregiver_id"`
+	UnitMinutes   int        `json:"unitMinutes" db:"unit_minutes"`
+	Rounding      string     `json:"rounding" db:"rounding"`
+	UnitRateCents int64      `json:"unitRateCents" db:"unit_rate_cents"`
+	// Service dates the rate applies to; both inclusive, open-ended without an end
+	EffectiveFrom time.Time  `json:"effectiveFrom" db:"effective_from"`
+	EffectiveTo   *time.Time `json:"effectiveTo" db:"effective_to"`
+}
+
+type BillingRateCreate struct {
+	Payer         string     `json:"payer" db:"payer"`
+	ServiceCode   string     `json:"serviceCode" db:"service_code"`
+	CaregiverID   *uuid.UUID `json:"caregiverId" db:"caregiver_id"`
+	UnitMinutes   int        `json:"unitMinutes" db:"unit_minutes"`
+	Rounding      string     `json:"rounding" db:"rounding"`
+	UnitRateCents int64      `json:"unitRateCents" db:"unit_rate_cents"`
+	EffectiveFrom time.Time  `json:"effectiveFrom" db:"effective_from"`
+	EffectiveTo   *time.Time `json:"effectiveTo" db:"effective_to"`
+}
+
+func (r *BillingRate) TableName() string {
+	return "billing_rates"
+}
+
+// BillableUnits turns whole minutes of service into units of the rate
+func (r *BillingRate) BillableUnits(minutes int) int {
+	if minutes <= 0 || r.UnitMinutes <= 0 {
+		return 0
+	}
+
+	units, remainder := minutes/r.UnitMinutes, minutes%r.UnitMinutes
+	switch r.Rounding {
+	case RoundingEightMinute:
+		if 2*remainder >= r.UnitMinutes {
+			units++
+		}
+	case RoundingUp:
+		if remainder > 0 {
+			units++
+		}
+	}
+	return units
+}
+
+// VisitBilling is the latest billing calculation of a completed visit. When
+// the visit cannot be billed, Issue says why and the units and amount are empty.
+type VisitBilling struct {
+	VisitID      uuid.UUID  `json:"visitId" db:"visit_id"`
+	RateID       *uuid.UUID `json:"rateId" db:"billing_rate_id"`
+	Units        *int       `json:"units" db:"billable_units"`
+	AmountCents  *int64     `json:"amountCents" db:"billed_amount_cents"`
+	Issue        *string    `json:"issue" db:"billing_issue"`
+	CalculatedAt time.Time  `json:"calculatedAt" db:"billed_at"`
+}
+
+func (b *VisitBilling) TableName() string {
+	return "visit_billing"
+}
+
+// CalculateVisitBilling bills a completed visit's clock times at a rate. The
+// payer comes from the visit's client and the service code from its schedule;
+// rate is the one that applies to them, the caregiver and the service date,
+// or nil when there is none.
+func CalculateVisitBilling(visit *Visit, schedule *Schedule, payer *string, rate *BillingRate, now time.Time) VisitBilling {
+	billing := VisitBilling{VisitID: visit.ID, CalculatedAt: now}
+
+	var issue string
+	switch {
+	case payer == nil || *payer == "":
+		issue = BillingIssueMissingPayer
+	case schedule.ServiceCode == nil || *schedule.ServiceCode == "":
+		issue = BillingIssueMissingServiceCode
+	case rate == nil:
+		issue = BillingIssueMissingRate
+	}
+	if issue != "" {
+		billing.Issue = &issue
+		return billing
+	}
+
+	// Payers bill whole minutes; seconds never count towards a unit
+	minutes := 0
+	if visit.EndTime != nil {
+		minutes = int(visit.EndTime.Sub(visit.StartTime) / time.Minute)
+	}
+	units := rate.BillableUnits(minutes)
+	amount := int64(units) * rate.UnitRateCents
+
+	billing.RateID = &rate.ID
+	billing.Units = &units
+	billing.AmountCents = &amount
+	return billing
+}
+
+// VisitBillingRun summarizes a recalculation of the completed visits in a date range
+type VisitBillingRun struct {
+	From        time.Time         `json:"from"`
+	To          time.Time         `json:"to"`
+	Calculated  int               `json:"calculated"`
+	Unbilled    int               `json:"unbilled"`
+	AmountCents int64             `json:"amountCents"`
+	Failures    []VisitRunFailure `json:"failures"`
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBillingRate_BillableUnits(t *testing.T) {
+	tests := []struct {
+		rounding string
+		minutes  int
+		want     int
+	}{
+		{RoundingEightMinute, 0, 0},
+		{RoundingEightMinute, 7, 0},
+		{RoundingEightMinute, 8, 1},
+		{RoundingEightMinute, 22, 1},
+		{RoundingEightMinute, 23, 2},
+		{RoundingEightMinute, 60, 4},
+		{RoundingDown, 14, 0},
+		{RoundingDown, 29, 1},
+		{RoundingDown, 30, 2},
+		{RoundingUp, 1, 1},
+		{RoundingUp, 15, 1},
+		{RoundingUp, 16, 2},
+		{RoundingUp, -5, 0},
+	}
+	for _, tt := range tests {
+		rate := BillingRate{UnitMinutes: 15, Rounding: tt.rounding}
+		assert.Equal(t, tt.want, rate.BillableUnits(tt.minutes), "%s %d minutes", tt.rounding, tt.minutes)
+	}
+
+	hourly := BillingRate{UnitMinutes: 60, Rounding: RoundingEightMinute}
+	assert.Equal(t, 1, hourly.BillableUnits(89))
+	assert.Equal(t, 2, hourly.BillableUnits(90))
+}
+
+func TestCalculateVisitBilling(t *testing.T) {
+	visit, schedule := newCompliantVisit()
+	// 1 hour 52 minutes 50 seconds bills as 112 minutes
+	end := visit.StartTime.Add(112*time.Minute + 50*time.Second)
+	visit.EndTime = &end
+	payer := "TX-MEDICAID"
+	rate := &BillingRate{
+		Base:          Base{BaseWithId: BaseWithId{ID: uuid.New()}},
+		UnitMinutes:   15,
+		Rounding:      RoundingEightMinute,
+		UnitRateCents: 525,
+	}
+	now := time.Now()
+
+	billing := CalculateVisitBilling(visit, schedule, &payer, rate, now)
+	require.NotNil(t, billing.Units)
+	require.NotNil(t, billing.AmountCents)
+	assert.Equal(t, 7, *billing.Units)
+	assert.Equal(t, int64(3675), *billing.AmountCents)
+	assert.Equal(t, &rate.ID, billing.RateID)
+	assert.Nil(t, billing.Issue)
+	assert.Equal(t, visit.ID, billing.VisitID)
+	assert.Equal(t, now, billing.CalculatedAt)
+
+	rate.Rounding = RoundingDown
+	billing = CalculateVisitBilling(visit, schedule, &payer, rate, now)
+	assert.Equal(t, 7, *billing.Units)
+	rate.Rounding = RoundingUp
+	billing = CalculateVisitBilling(visit, schedule, &payer, rate, now)
+	assert.Equal(t, 8, *billing.Units)
+}
+
+func TestCalculateVisitBilling_Issues(t *testing.T) {
+	visit, schedule := newCompliantVisit()
+	payer := "TX-MEDICAID"
+	rate := &BillingRate{UnitMinutes: 15, Rounding: RoundingEightMinute, UnitRateCents: 525}
+
+	billing := CalculateVisitBilling(visit, schedule, nil, rate, time.Now())
+	require.NotNil(t, billing.Issue)
+	assert.Equal(t, BillingIssueMissingPayer, *billing.Issue)
+	assert.Nil(t, billing.Units)
+	assert.Nil(t, billing.AmountCents)
+	assert.Nil(t, billing.RateID)
+	assert.False(t, billing.CalculatedAt.IsZero())
+
+	billing = CalculateVisitBilling(visit, schedule, &payer, nil, time.Now())
+	assert.Equal(t, BillingIssueMissingRate, *billing.Issue)
+
+	schedule.ServiceCode = nil
+	billing = CalculateVisitBilling(visit, schedule, &payer, rate, time.Now())
+	assert.Equal(t, BillingIssueMissingServiceCode, *billing.Issue)
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CarePlan is a client's ordered list of task templates; the active plan
+// seeds the tasks of every new schedule for that client
+type CarePlan struct {
+	Base
+	ClientID uuid.UUID      `json:"clientId" db:"client_id"`
+	Name     string         `json:"name" db:"name"`
+	IsActive bool           `json:"isActive" db:"is_active"`
+	Tasks    []CarePlanTask `json:"tasks" db:"tasks"`
+}
+
+type CarePlanTask struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	CarePlanID  uuid.UUID `json:"carePlanId" db:"care_plan_id"`
+	Position    int       `json:"position" db:"position"`
+	Name        string    `json:"name" db:"name"`
+	Description *string   `json:"description" db:"description"`
+	IsRequired  bool      `json:"isRequired" db:"is_required"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+}
+
+type CarePlanCreate struct {
+	ClientID uuid.UUID            `json:"clientId" db:"client_id"`
+	Name     string               `json:"name" db:"name"`
+	Tasks    []CarePlanTaskCreate `json:"tasks" db:"tasks"`
+}
+
+type CarePlanTaskCreate struct {
+	Name        string  `json:"name" db:"name"`
+	Description *string `json:"description" db:"description"`
+	IsRequired  bool    `json:"isRequired" db:"is_required"`
+}
+
+func (p *CarePlan) TableName() string {
+	return "care_plans"
+}
+
+// NewTasks builds the pending tasks a schedule starts with, in plan order
+func (p *CarePlan) NewTasks(scheduleID uuid.UUID, now time.Time) []Task {
+	tasks := make([]Task, 0, len(p.Tasks))
+	for _, template := range p.Tasks {
+		templateID := template.ID
+		tasks = append(tasks, Task{
+			Base: Base{
+				BaseWithId:        BaseWithId{ID: uuid.New()},
+				BaseWithCreatedAt: BaseWithCreatedAt{CreatedAt: now},
+				BaseWithUpdatedAt: BaseWithUpdatedAt{UpdatedAt: now},
+			},
+			ScheduleID:     scheduleID,
+			Name:           template.Name,
+			Description:    template.Description,
+			Status:         TaskStatusPending,
+			IsRequired:     template.IsRequired,
+			SortOrder:      template.Position,
+			CarePlanTaskID: &templateID,
+		})
+	}
+	return tasks
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCarePlanNewTasks(t *testing.T) {
+	description := "Give prescribed morning medication"
+	plan := CarePlan{
+		Tasks: []CarePlanTask{
+			{ID: uuid.New(), Position: 1, Name: "Morning Medication", Description: &description, IsRequired: true},
+			{ID: uuid.New(), Position: 2, Name: "Light Housekeeping", IsRequired: false},
+		},
+	}
+	scheduleID := uuid.New()
+
+	tasks := plan.NewTasks(scheduleID, time.Now())
+
+	assert.Len(t, tasks, 2)
+	for i, task := range tasks {
+		assert.Equal(t, scheduleID, task.ScheduleID)
+		assert.Equal(t, TaskStatusPending, task.Status)
+		assert.Equal(t, plan.Tasks[i].Name, task.Name)
+		assert.Equal(t, plan.Tasks[i].Position, task.SortOrder)
+		assert.Equal(t, plan.Tasks[i].IsRequired, task.IsRequired)
+		assert.Equal(t, plan.Tasks[i].ID, *task.CarePlanTaskID)
+		assert.NotEqual(t, uuid.Nil, task.ID)
+	}
+	assert.NotEqual(t, tasks[0].ID, tasks[1].ID)
+}
+
+func TestCarePlanNewTasksWithoutTemplates(t *testing.T) {
+	plan := CarePlan{}
+	assert.Empty(t, plan.NewTasks(uuid.New(), time.Now()))
+}
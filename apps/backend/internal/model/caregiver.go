@@ -0,0 +1,28 @@
+package model
+
+type Caregiver struct {
+	Base
+	// UserID is the Clerk user ID the caregiver signs in with
+	UserID    string  `json:"userId" db:"user_id"`
+	FirstName string  `json:"firstName" db:"first_name"`
+	LastName  string  `json:"lastName" db:"last_name"`
+	Email     *string `json:"email" db:"email"`
+	Phone     *string `json:"phone" db:"phone"`
+	IsActive  bool    `json:"isActive" db:"is_active"`
+}
+
+type CaregiverCreate struct {
+	UserID    string  `json:"userId" db:"user_id"`
+	FirstName string  `json:"firstName" db:"first_name"`
+	LastName  string  `json:"lastName" db:"last_name"`
+	Email     *string `json:"email" db:"email"`
+	Phone     *string `json:"phone" db:"phone"`
+}
+
+func (c *Caregiver) TableName() string {
+	return "caregivers"
+}
+
+func (c *Caregiver) FullName() string {
+	return c.FirstName + " " + c.LastName
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/etag"
+)
+
+const (
+	ClientStatusActive   = "active"
+	ClientStatusInactive = "inactive"
+)
+
+// Client is a care recipient that schedules are delivered to
+type Client struct {
+	Base
+	FirstName   string     `json:"firstName" db:"first_name"`
+	LastName    string     `json:"lastName" db:"last_name"`
+	DateOfBirth *time.Time `json:"dateOfBirth" db:"date_of_birth"`
+	Gender      *string    `json:"gender" db:"gender"`
+	MedicaidID  *string    `json:"medicaidId" db:"medicaid_id"`
+	// Payer billed for the client's visits; see BillingRate
+	Payer *string `json:"payer" db:"payer"`
+	// Primary address; schedules default their service location to it
+	AddressLine1      string             `json:"addressLine1" db:"address_line1"`
+	AddressLine2      *string            `json:"addressLine2" db:"address_line2"`
+	City              string             `json:"city" db:"city"`
+	State             string             `json:"state" db:"state"`
+	PostalCode        string             `json:"postalCode" db:"postal_code"`
+	Latitude          *float64           `json:"latitude" db:"latitude"`
+	Longitude         *float64           `json:"longitude" db:"longitude"`
+	Phone             *string            `json:"phone" db:"phone"`
+	EmergencyContacts []EmergencyContact `json:"emergencyContacts" db:"emergency_contacts"`
+	Status            string             `json:"status" db:"status"`
+}
+
+type EmergencyContact struct {
+	Name         string `json:"name"`
+	Relationship string `json:"relationship,omitempty"`
+	Phone        string `json:"phone"`
+}
+
+type ClientCreate struct {
+	FirstName         string             `json:"firstName" db:"first_name"`
+	LastName          string             `json:"lastName" db:"last_name"`
+	DateOfBirth       *time.Time         `json:"dateOfBirth" db:"date_of_birth"`
+	Gender            *string            `json:"gender" db:"gender"`
+	MedicaidID        *string            `json:"medicaidId" db:"medicaid_id"`
+	Payer             *string            `json:"payer" db:"payer"`
+	AddressLine1      string             `json:"addressLine1" db:"address_line1"`
+	AddressLine2      *string            `json:"addressLine2" db:"address_line2"`
+	City              string             `json:"city" db:"city"`
+	State             string             `json:"state" db:"state"`
+	PostalCode        string             `json:"postalCode" db:"postal_code"`
+	Latitude          *float64           `json:"latitude" db:"latitude"`
+	Longitude         *float64           `json:"longitude" db:"longitude"`
+	Phone             *string            `json:"phone" db:"phone"`
+	EmergencyContacts []EmergencyContact `json:"emergencyContacts" db:"emergency_contacts"`
+}
+
+func (c *Client) TableName() string {
+	return "clients"
+}
+
+// ETag identifies this version of the client
+func (c *Client) ETag() string {
+	return etag.Format(c.UpdatedAt)
+}
+
+func (c *Client) FullName() string {
+	return strings.TrimSpace(c.FirstName + " " + c.LastName)
+}
+
+func (c *Client) IsActive() bool {
+	return c.Status == ClientStatusActive
+}
+
+// FormattedAddress joins the non-empty parts of the primary address on one line
+func (c *Client) FormattedAddress() string {
+	parts := []string{c.AddressLine1}
+	if c.AddressLine2 != nil {
+		parts = append(parts, *c.AddressLine2)
+	}
+	parts = append(parts, c.City, strings.TrimSpace(c.State+" "+c.PostalCode))
+
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
+
+type ClientUpdate struct {
+	ClientCreate
+	Status string `json:"status" db:"status"`
+}
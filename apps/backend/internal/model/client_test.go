@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientFormattedAddress(t *testing.T) {
+	unit := "Apt 4"
+
+	t.Run("full address", func(t *testing.T) {
+		client := Client{AddressLine1: "123 Main St", AddressLine2: &unit, City: "Springfield", State: "IL", PostalCode: "62701"}
+		assert.Equal(t, "123 Main St, Apt 4, Springfield, IL 62701", client.FormattedAddress())
+	})
+
+	t.Run("migrated client with only a street line", func(t *testing.T) {
+		client := Client{AddressLine1: "123 Main St"}
+		assert.Equal(t, "123 Main St", client.FormattedAddress())
+	})
+}
+
+func TestClientFullName(t *testing.T) {
+	assert.Equal(t, "John Doe", (&Client{FirstName: "John", LastName: "Doe"}).FullName())
+	assert.Equal(t, "Cher", (&Client{FirstName: "Cher"}).FullName())
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Cursor marks a row of a sorted listing. Value is the row's value of the
+// field named by Sort, written as text, and ID breaks ties. A forward cursor
+// continues after the row; a backward cursor returns to the rows before it.
+type Cursor struct {
+	Sort     string
+	Value    string
+	ID       uuid.UUID
+	Backward bool
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Encode returns the opaque form of the cursor handed to clients
+func (c Cursor) Encode() string {
+	direction := "n"
+	if c.Backward {
+		direction = "p"
+	}
+	// The value goes last as it may contain the separator
+	raw := strings.Join([]string{direction, c.Sort, c.ID.String(), c.Value}, "|")
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor returned by Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 4)
+	if len(parts) != 4 || parts[0] != "n" && parts[0] != "p" || parts[1] == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{
+		Sort:     parts[1],
+		Value:    parts[3],
+		ID:       id,
+		Backward: parts[0] == "p",
+	}, nil
+}
+
+// CursorPage selects a page of a cursor-paginated listing. Without a cursor
+// it is the first page.
+type CursorPage struct {
+	Cursor       *Cursor
+	Limit        int
+	IncludeTotal bool
+}
+
+// CursorPaginatedResponse is a page of a listing paginated by cursor. Total
+// is only counted when the page asks for it.
+type CursorPaginatedResponse[T interface{}] struct {
+	Data       []T     `json:"data"`
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"nextCursor"`
+	PrevCursor *string `json:"prevCursor"`
+	Total      *int    `json:"total,omitempty"`
+}
+
+// NewCursorPaginatedResponse builds the response for rows fetched for page in
+// the order of page's cursor, with one row beyond the limit if there are
+// more. key returns the position of a row in the listing.
+func NewCursorPaginatedResponse[T interface{}](rows []T, page CursorPage, key func(T) Cursor) *CursorPaginatedResponse[T] {
+	more := len(rows) > page.Limit
+	if more {
+		rows = rows[:page.Limit]
+	}
+
+	backward := page.Cursor != nil && page.Cursor.Backward
+	if backward {
+		// Rows before the cursor were fetched in reverse order
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	response := &CursorPaginatedResponse[T]{
+		Data:  rows,
+		Limit: page.Limit,
+	}
+	if len(rows) == 0 {
+		return response
+	}
+
+	// A page reached through a cursor has rows on the side it came from
+	if more || backward {
+		next := key(rows[len(rows)-1])
+		encoded := next.Encode()
+		response.NextCursor = &encoded
+	}
+	if more && backward || page.Cursor != nil && !backward {
+		prev := key(rows[0])
+		prev.Backward = true
+		encoded := prev.Encode()
+		response.PrevCursor = &encoded
+	}
+
+	return response
+}
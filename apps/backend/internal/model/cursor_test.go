@@ -0,0 +1,99 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_Encode(t *testing.T) {
+	cursor := Cursor{
+		Sort:     "location:asc",
+		Value:    "12 Main St | Apt 3",
+		ID:       uuid.New(),
+		Backward: true,
+	}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, *decoded)
+
+	for _, s := range []string{"", "not base64!", "eDox", "bnx8MXwy", cursor.Encode()[1:]} {
+		_, err := DecodeCursor(s)
+		assert.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+}
+
+func TestNewCursorPaginatedResponse(t *testing.T) {
+	start := time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)
+	// Five rows, newest first
+	rows := make([]Base, 5)
+	for i := range rows {
+		rows[i] = Base{
+			BaseWithId:        BaseWithId{ID: uuid.New()},
+			BaseWithCreatedAt: BaseWithCreatedAt{CreatedAt: start.Add(-time.Duration(i) * time.Minute)},
+		}
+	}
+	key := func(b Base) Cursor {
+		return Cursor{Sort: "createdAt:desc", Value: b.CreatedAt.Format(time.RFC3339Nano), ID: b.ID}
+	}
+	fetch := func(rows ...Base) []Base {
+		return append([]Base(nil), rows...)
+	}
+	decode := func(s *string) *Cursor {
+		require.NotNil(t, s)
+		cursor, err := DecodeCursor(*s)
+		require.NoError(t, err)
+		return cursor
+	}
+
+	t.Run("first page", func(t *testing.T) {
+		page := CursorPage{Limit: 2}
+		response := NewCursorPaginatedResponse(fetch(rows[0], rows[1], rows[2]), page, key)
+		assert.Equal(t, rows[:2], response.Data)
+		assert.Nil(t, response.PrevCursor)
+		assert.Equal(t, key(rows[1]), *decode(response.NextCursor))
+		assert.Nil(t, response.Total)
+	})
+
+	t.Run("last page after a cursor", func(t *testing.T) {
+		page := CursorPage{Cursor: &Cursor{Sort: "createdAt:desc", Value: key(rows[2]).Value, ID: rows[2].ID}, Limit: 2}
+		response := NewCursorPaginatedResponse(fetch(rows[3], rows[4]), page, key)
+		assert.Equal(t, rows[3:], response.Data)
+		assert.Nil(t, response.NextCursor)
+
+		prev := decode(response.PrevCursor)
+		assert.True(t, prev.Backward)
+		assert.Equal(t, rows[3].ID, prev.ID)
+	})
+
+	t.Run("backward page is returned newest first", func(t *testing.T) {
+		page := CursorPage{Cursor: &Cursor{Sort: "createdAt:desc", Value: key(rows[3]).Value, ID: rows[3].ID, Backward: true}, Limit: 2}
+		// Rows before the cursor are fetched oldest first
+		response := NewCursorPaginatedResponse(fetch(rows[2], rows[1], rows[0]), page, key)
+		assert.Equal(t, []Base{rows[1], rows[2]}, response.Data)
+		assert.Equal(t, key(rows[2]), *decode(response.NextCursor))
+
+		prev := decode(response.PrevCursor)
+		assert.True(t, prev.Backward)
+		assert.Equal(t, rows[1].ID, prev.ID)
+	})
+
+	t.Run("backward to the first page", func(t *testing.T) {
+		page := CursorPage{Cursor: &Cursor{Sort: "createdAt:desc", Value: key(rows[2]).Value, ID: rows[2].ID, Backward: true}, Limit: 2}
+		response := NewCursorPaginatedResponse(fetch(rows[1], rows[0]), page, key)
+		assert.Equal(t, rows[:2], response.Data)
+		assert.Nil(t, response.PrevCursor)
+		assert.Equal(t, key(rows[1]), *decode(response.NextCursor))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		response := NewCursorPaginatedResponse([]Base{}, CursorPage{Limit: 2}, key)
+		assert.Empty(t, response.Data)
+		assert.Nil(t, response.NextCursor)
+		assert.Nil(t, response.PrevCursor)
+	})
+}
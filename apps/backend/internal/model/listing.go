@@ -0,0 +1,87 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/listquery"
+)
+
+// Filters and sorts of the schedule, visit and task listings. Visits and
+// tasks are filtered through the schedule they belong to, so the three
+// listings share the schedule fields. Only indexed columns are sortable.
+
+var (
+	ScheduleStatuses = []string{"missed", "upcoming", "in_progress", "completed", "cancelled"}
+	VisitStatuses    = []string{"in_progress", "completed"}
+	TaskStatuses     = []string{"pending", "completed", "not_completed"}
+)
+
+var ScheduleListing = &listquery.Schema[Schedule]{
+	Fields: []listquery.Field[Schedule]{
+		{Name: "id", Column: "id", Kind: listquery.UUID, Operators: []listquery.Operator{listquery.Eq, listquery.In}},
+		{Name: "status", Column: "status", Kind: listquery.Text, Operators: []listquery.Operator{listquery.Eq, listquery.Ne, listquery.In}, Values: ScheduleStatuses,
+			Key: func(s Schedule) any { return s.Status }},
+		{Name: "scheduledStart", Column: "scheduled_start", Kind: listquery.Time, Operators: listquery.Ranges,
+			Key: func(s Schedule) any { return s.ScheduledStart }},
+		{Name: "scheduledEnd", Column: "scheduled_end", Kind: listquery.Time, Operators: listquery.Ranges},
+		{Name: "caregiverId", Column: "caregiver_id", Kind: listquery.UUID, Operators: []listquery.Operator{listquery.Eq, listquery.Ne, listquery.In}},
+		{Name: "clientId", Column: "client_id", Kind: listquery.UUID, Operators: []listquery.Operator{listquery.Eq, listquery.Ne, listquery.In}},
+		{Name: "hasExceptions", Column: hasExceptions("schedules.id"), Kind: listquery.Bool, Operators: []listquery.Operator{listquery.Eq}},
+		createdAtField(func(s Schedule) Base { return s.Base }),
+	},
+	ID:          func(s Schedule) uuid.UUID { return s.ID },
+	DefaultSort: "createdAt:desc",
+}
+
+var VisitListing = &listquery.Schema[Visit]{
+	Fields: append([]listquery.Field[Visit]{
+		{Name: "id", Column: "id", Kind: listquery.UUID, Operators: []listquery.Operator{listquery.Eq, listquery.In}},
+		{Name: "status", Column: "status", Kind: listquery.Text, Operators: []listquery.Operator{listquery.Eq, listquery.Ne, listquery.In}, Values: VisitStatuses,
+			Key: func(v Visit) any { return v.Status }},
+		{Name: "startTime", Column: "start_time", Kind: listquery.Time, Operators: listquery.Ranges,
+			Key: func(v Visit) any { return v.StartTime }},
+		{Name: "hasExceptions", Column: "EXISTS (SELECT 1 FROM visit_exceptions e WHERE e.visit_id = visits.id)", Kind: listquery.Bool, Operators: []listquery.Operator{listquery.Eq}},
+		createdAtField(func(v Visit) Base { return v.Base }),
+	}, scheduleFields[Visit]("visits.schedule_id")...),
+	ID:          func(v Visit) uuid.UUID { return v.ID },
+	DefaultSort: "createdAt:desc",
+}
+
+var TaskListing = &listquery.Schema[Task]{
+	Fields: append([]listquery.Field[Task]{
+		{Name: "id", Column: "id", Kind: listquery.UUID, Operators: []listquery.Operator{listquery.Eq, listquery.In}},
+		{Name: "status", Column: "status", Kind: listquery.Text, Operators: []listquery.Operator{listquery.Eq, listquery.Ne, listquery.In}, Values: TaskStatuses,
+			Key: func(t Task) any { return t.Status }},
+		{Name: "hasExceptions", Column: hasExceptions("tasks.schedule_id"), Kind: listquery.Bool, Operators: []listquery.Operator{listquery.Eq}},
+		createdAtField(func(t Task) Base { return t.Base }),
+	}, scheduleFields[Task]("tasks.schedule_id")...),
+	ID:          func(t Task) uuid.UUID { return t.ID },
+	DefaultSort: "createdAt:desc",
+}
+
+func createdAtField[T any](base func(T) Base) listquery.Field[T] {
+	return listquery.Field[T]{
+		Name: "createdAt", Column: "created_at", Kind: listquery.Time, Operators: listquery.Ranges,
+		Key: func(row T) any { return base(row).CreatedAt },
+	}
+}
+
+// scheduleFields filter rows by the schedule that scheduleID, a SQL
+// expression, refers to
+func scheduleFields[T any](scheduleID string) []listquery.Field[T] {
+	column := func(name string) string {
+		return "SELECT s." + name + " FROM schedules s WHERE s.id = " + scheduleID
+	}
+
+	return []listquery.Field[T]{
+		{Name: "scheduleId", Column: scheduleID, Kind: listquery.UUID, Operators: []listquery.Operator{listquery.Eq, listquery.In}},
+		{Name: "scheduledStart", Column: column("scheduled_start"), Kind: listquery.Time, Operators: listquery.Ranges},
+		{Name: "caregiverId", Column: column("caregiver_id"), Kind: listquery.UUID, Operators: []listquery.Operator{listquery.Eq, listquery.Ne, listquery.In}},
+		{Name: "clientId", Column: column("client_id"), Kind: listquery.UUID, Operators: []listquery.Operator{listquery.Eq, listquery.Ne, listquery.In}},
+	}
+}
+
+// hasExceptions tells whether the visit of the schedule that scheduleID, a
+// SQL expression, refers to has any exception
+func hasExceptions(scheduleID string) string {
+	return "EXISTS (SELECT 1 FROM visit_exceptions e JOIN visits v ON v.id = e.visit_id WHERE v.schedule_id = " + scheduleID + ")"
+}
@@ -1,16 +1,75 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/etag"
 )
 
 type Schedule struct {
 	Base
+	ClientID   uuid.UUID `json:"clientId" db:"client_id"`
+	// ClientName is read from the client record for display
 	ClientName string    `json:"clientName" db:"client_name"`
-	ShiftTime  string    `json:"shiftTime" db:"shift_time"`
+	// Shift window; TimeZone is the IANA zone the shift is worked in
+	ScheduledStart time.Time `json:"scheduledStart" db:"scheduled_start"`
+	ScheduledEnd   time.Time `json:"scheduledEnd" db:"scheduled_end"`
+	TimeZone       string    `json:"timeZone" db:"time_zone"`
 	Location   string    `json:"location" db:"location"`
+	// ServiceCode is the payer's code for the type of service delivered, e.g. T1019
+	ServiceCode *string `json:"serviceCode" db:"service_code"`
 	Status     string    `json:"status" db:"status"`
+	CaregiverID *uuid.UUID `json:"caregiverId" db:"caregiver_id"`
 	VisitID    *uuid.UUID `json:"visitId" db:"visit_id"`
+	// Service location coordinates used for geofence verification
+	Latitude             *float64 `json:"latitude" db:"latitude"`
+	Longitude            *float64 `json:"longitude" db:"longitude"`
+	GeofenceRadiusMeters *int     `json:"geofenceRadiusMeters" db:"geofence_radius_meters"`
+	// Recurring series the schedule was generated from, and the occurrence date it stands for
+	SeriesID          *uuid.UUID `json:"seriesId" db:"series_id"`
+	SeriesDate        *time.Time `json:"seriesDate" db:"series_date"`
+	IsSeriesException bool       `json:"isSeriesException" db:"is_series_exception"`
+}
+
+type ScheduleCreate struct {
+	ClientID             uuid.UUID `json:"clientId" db:"client_id"`
+	ScheduledStart       time.Time `json:"scheduledStart" db:"scheduled_start"`
+	ScheduledEnd         time.Time `json:"scheduledEnd" db:"scheduled_end"`
+	TimeZone             string    `json:"timeZone" db:"time_zone"`
+	Location             string   `json:"location" db:"location"`
+	ServiceCode          *string  `json:"serviceCode" db:"service_code"`
+	Latitude             *float64 `json:"latitude" db:"latitude"`
+	Longitude            *float64 `json:"longitude" db:"longitude"`
+	GeofenceRadiusMeters *int     `json:"geofenceRadiusMeters" db:"geofence_radius_meters"`
+}
+
+// ScheduleSearchFilter narrows a schedule search by status and by a range of
+// scheduled start times; From is inclusive and To exclusive
+type ScheduleSearchFilter struct {
+	Query  string
+	Status string
+	From   *time.Time
+	To     *time.Time
+}
+
+// ScheduleSearchResult is a schedule matching a search. Snippet is the
+// schedule's client name, location and task names with the matching words
+// wrapped in <mark> tags.
+type ScheduleSearchResult struct {
+	Schedule
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// ScheduleUpdate holds the schedule fields to change; nil fields are left as they are
+type ScheduleUpdate struct {
+	ClientID       *uuid.UUID `json:"clientId"`
+	Location       *string    `json:"location"`
+	ServiceCode    *string    `json:"serviceCode"`
+	ScheduledStart *time.Time `json:"scheduledStart"`
+	ScheduledEnd   *time.Time `json:"scheduledEnd"`
+	TimeZone       *string    `json:"timeZone"`
 }
 
 type ScheduleWithVisit struct {
@@ -24,6 +83,53 @@ type ScheduleWithTasks struct {
 	Tasks  []Task  `json:"tasks" db:"tasks"`
 }
 
+// ScheduleStats counts schedules by lifecycle status
+type ScheduleStats struct {
+	Total      int `json:"total"`
+	Upcoming   int `json:"upcoming"`
+	InProgress int `json:"inProgress"`
+	Completed  int `json:"completed"`
+	Missed     int `json:"missed"`
+	Cancelled  int `json:"cancelled"`
+}
+
 func (s *Schedule) TableName() string {
 	return "schedules"
-}
\ No newline at end of file
+}
+
+// ETag identifies this version of the schedule
+func (s *Schedule) ETag() string {
+	return etag.Format(s.UpdatedAt)
+}
+
+// ETag identifies this version of the schedule together with its visit and
+// tasks, so that a change to any of them invalidates a cached copy
+func (s *ScheduleWithTasks) ETag() string {
+	values := make([]string, 0, 2*len(s.Tasks)+2)
+	if s.Visit != nil {
+		values = append(values, s.Visit.ID.String(), s.Visit.ETag())
+	}
+	for _, task := range s.Tasks {
+		values = append(values, task.ID.String(), task.ETag())
+	}
+	return etag.Format(s.UpdatedAt, etag.Digest(values...))
+}
+
+// ShiftDuration returns the length of the scheduled shift
+func (s *Schedule) ShiftDuration() time.Duration {
+	return s.ScheduledEnd.Sub(s.ScheduledStart)
+}
+
+// HasServiceLocation reports whether the schedule carries coordinates to verify clock events against
+func (s *Schedule) HasServiceLocation() bool {
+	return s.Latitude != nil && s.Longitude != nil
+}
+
+// LocalScheduledStart returns the scheduled start in the schedule's time zone, falling back to UTC
+func (s *Schedule) LocalScheduledStart() time.Time {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return s.ScheduledStart.In(loc)
+}
@@ -0,0 +1,191 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/rrule"
+)
+
+// SeriesTimeLayout is the format of a series' local start time
+const SeriesTimeLayout = "15:04"
+
+// ScheduleSeries is a recurring shift; its occurrences are materialized
+// ahead of time as schedules linked back to the series
+type ScheduleSeries struct {
+	Base
+	ClientID    uuid.UUID  `json:"clientId" db:"client_id"`
+	CaregiverID *uuid.UUID `json:"caregiverId" db:"caregiver_id"`
+	// RRule is the RFC 5545 recurrence pattern; EndDate and Count bound it
+	RRule     string    `json:"rrule" db:"rrule"`
+	StartDate time.Time `json:"startDate" db:"start_date"`
+	// StartTime is the local wall-clock start of every occurrence in TimeZone
+	StartTime       string      `json:"startTime" db:"start_time"`
+	DurationMinutes int         `json:"durationMinutes" db:"duration_minutes"`
+	TimeZone        string      `json:"timeZone" db:"time_zone"`
+	EndDate         *time.Time  `json:"endDate" db:"end_date"`
+	Count           *int        `json:"count" db:"occurrence_count"`
+	ExceptionDates  []time.Time `json:"exceptionDates" db:"exception_dates"`
+	// Service location copied onto every occurrence
+	Location             string   `json:"location" db:"location"`
+	ServiceCode          *string  `json:"serviceCode" db:"service_code"`
+	Latitude             *float64 `json:"latitude" db:"latitude"`
+	Longitude            *float64 `json:"longitude" db:"longitude"`
+	GeofenceRadiusMeters *int     `json:"geofenceRadiusMeters" db:"geofence_radius_meters"`
+	// MaterializedThrough is the last date occurrences have been created for
+	MaterializedThrough *time.Time `json:"materializedThrough" db:"materialized_through"`
+}
+
+type ScheduleSeriesCreate struct {
+	ClientID             uuid.UUID   `json:"clientId" db:"client_id"`
+	CaregiverID          *uuid.UUID  `json:"caregiverId" db:"caregiver_id"`
+	RRule                string      `json:"rrule" db:"rrule"`
+	StartDate            time.Time   `json:"startDate" db:"start_date"`
+	StartTime            string      `json:"startTime" db:"start_time"`
+	DurationMinutes      int         `json:"durationMinutes" db:"duration_minutes"`
+	TimeZone             string      `json:"timeZone" db:"time_zone"`
+	EndDate              *time.Time  `json:"endDate" db:"end_date"`
+	Count                *int        `json:"count" db:"occurrence_count"`
+	ExceptionDates       []time.Time `json:"exceptionDates" db:"exception_dates"`
+	Location             string      `json:"location" db:"location"`
+	ServiceCode          *string     `json:"serviceCode" db:"service_code"`
+	Latitude             *float64    `json:"latitude" db:"latitude"`
+	Longitude            *float64    `json:"longitude" db:"longitude"`
+	GeofenceRadiusMeters *int        `json:"geofenceRadiusMeters" db:"geofence_radius_meters"`
+}
+
+func (s *ScheduleSeries) TableName() string {
+	return "schedule_series"
+}
+
+// Rule returns the recurrence rule bounded by the series end date or count
+func (s *ScheduleSeries) Rule() (*rrule.Rule, error) {
+	rule, err := rrule.Parse(s.RRule)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.EndDate != nil {
+		until := rrule.Date(*s.EndDate)
+		rule.Until = &until
+	}
+	if s.Count != nil {
+		rule.Count = *s.Count
+	}
+
+	return rule, nil
+}
+
+// OccurrenceDates returns the series dates within [from, to], leaving out exception dates
+func (s *ScheduleSeries) OccurrenceDates(from, to time.Time) ([]time.Time, error) {
+	rule, err := s.Rule()
+	if err != nil {
+		return nil, err
+	}
+
+	dates := rule.Between(s.StartDate, from, to)
+	return slices.DeleteFunc(dates, s.isException), nil
+}
+
+// OccurrenceWindow returns the scheduled start and end of the occurrence on date
+func (s *ScheduleSeries) OccurrenceWindow(date time.Time) (time.Time, time.Time, error) {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid series time zone: %w", err)
+	}
+
+	clock, err := time.Parse(SeriesTimeLayout, s.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid series start time: %w", err)
+	}
+
+	start := time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	return start, start.Add(time.Duration(s.DurationMinutes) * time.Minute), nil
+}
+
+// NewSchedule builds the upcoming schedule for the occurrence on date
+func (s *ScheduleSeries) NewSchedule(date, now time.Time) (Schedule, error) {
+	start, end, err := s.OccurrenceWindow(date)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	seriesID := s.ID
+	seriesDate := rrule.Date(date)
+	return Schedule{
+		Base: Base{
+			BaseWithId:        BaseWithId{ID: uuid.New()},
+			BaseWithCreatedAt: BaseWithCreatedAt{CreatedAt: now},
+			BaseWithUpdatedAt: BaseWithUpdatedAt{UpdatedAt: now},
+		},
+		ClientID:             s.ClientID,
+		ScheduledStart:       start,
+		ScheduledEnd:         end,
+		TimeZone:             s.TimeZone,
+		Location:             s.Location,
+		ServiceCode:          s.ServiceCode,
+		Status:               ScheduleStatusUpcoming,
+		CaregiverID:          s.CaregiverID,
+		Latitude:             s.Latitude,
+		Longitude:            s.Longitude,
+		GeofenceRadiusMeters: s.GeofenceRadiusMeters,
+		SeriesID:             &seriesID,
+		SeriesDate:           &seriesDate,
+	}, nil
+}
+
+// Split ends the series on the day before date and returns a new series that
+// continues the same pattern from date on. A count is shared between the two.
+func (s *ScheduleSeries) Split(date, now time.Time) (*ScheduleSeries, error) {
+	date = rrule.Date(date)
+
+	next := *s
+	next.Base = Base{
+		BaseWithId:        BaseWithId{ID: uuid.New()},
+		BaseWithCreatedAt: BaseWithCreatedAt{CreatedAt: now},
+		BaseWithUpdatedAt: BaseWithUpdatedAt{UpdatedAt: now},
+	}
+	next.StartDate = date
+	next.ExceptionDates = make([]time.Time, 0)
+
+	if s.Count != nil {
+		rule, err := s.Rule()
+		if err != nil {
+			return nil, err
+		}
+		used := len(rule.Between(s.StartDate, s.StartDate, date.AddDate(0, 0, -1)))
+		if used >= *s.Count {
+			return nil, fmt.Errorf("series has no occurrences on or after %s", date.Format("2006-01-02"))
+		}
+		remaining := *s.Count - used
+		next.Count = &remaining
+	}
+
+	kept := make([]time.Time, 0)
+	for _, exception := range s.ExceptionDates {
+		if exception.Before(date) {
+			kept = append(kept, exception)
+		} else {
+			next.ExceptionDates = append(next.ExceptionDates, exception)
+		}
+	}
+
+	end := date.AddDate(0, 0, -1)
+	s.EndDate = &end
+	s.Count = nil
+	s.ExceptionDates = kept
+	s.UpdatedAt = now
+
+	return &next, nil
+}
+
+func (s *ScheduleSeries) isException(date time.Time) bool {
+	for _, exception := range s.ExceptionDates {
+		if rrule.Date(exception).Equal(date) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seriesDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func newTestSeries() ScheduleSeries {
+	count := 6
+	return ScheduleSeries{
+		Base:            Base{BaseWithId: BaseWithId{ID: uuid.New()}},
+		ClientID:        uuid.New(),
+		RRule:           "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		StartDate:       seriesDate(2024, 3, 4),
+		StartTime:       "09:30",
+		DurationMinutes: 240,
+		TimeZone:        "America/New_York",
+		Count:           &count,
+		ExceptionDates:  []time.Time{seriesDate(2024, 3, 6)},
+		Location:        "123 Main St",
+	}
+}
+
+func TestScheduleSeries_OccurrenceDates(t *testing.T) {
+	series := newTestSeries()
+
+	dates, err := series.OccurrenceDates(seriesDate(2024, 3, 1), seriesDate(2024, 3, 31))
+	require.NoError(t, err)
+
+	// The exception still uses up one of the six occurrences
+	assert.Equal(t, []time.Time{
+		seriesDate(2024, 3, 4), seriesDate(2024, 3, 8),
+		seriesDate(2024, 3, 11), seriesDate(2024, 3, 13), seriesDate(2024, 3, 15),
+	}, dates)
+}
+
+func TestScheduleSeries_NewScheduleKeepsLocalTimeAcrossDST(t *testing.T) {
+	series := newTestSeries()
+	now := time.Now()
+
+	// US daylight saving time starts on 2024-03-10
+	before, err := series.NewSchedule(seriesDate(2024, 3, 8), now)
+	require.NoError(t, err)
+	after, err := series.NewSchedule(seriesDate(2024, 3, 11), now)
+	require.NoError(t, err)
+
+	assert.Equal(t, 14, before.ScheduledStart.UTC().Hour())
+	assert.Equal(t, 13, after.ScheduledStart.UTC().Hour())
+	assert.Equal(t, 4*time.Hour, after.ShiftDuration())
+	assert.Equal(t, ScheduleStatusUpcoming, after.Status)
+	assert.Equal(t, series.ID, *after.SeriesID)
+	assert.Equal(t, seriesDate(2024, 3, 11), *after.SeriesDate)
+}
+
+func TestScheduleSeries_Split(t *testing.T) {
+	series := newTestSeries()
+	series.ExceptionDates = []time.Time{seriesDate(2024, 3, 6), seriesDate(2024, 3, 13)}
+
+	next, err := series.Split(seriesDate(2024, 3, 11), time.Now())
+	require.NoError(t, err)
+
+	assert.NotEqual(t, series.ID, next.ID)
+	assert.Equal(t, seriesDate(2024, 3, 10), *series.EndDate)
+	assert.Nil(t, series.Count)
+	assert.Equal(t, []time.Time{seriesDate(2024, 3, 6)}, series.ExceptionDates)
+
+	assert.Equal(t, seriesDate(2024, 3, 11), next.StartDate)
+	require.NotNil(t, next.Count)
+	assert.Equal(t, 3, *next.Count)
+	assert.Equal(t, []time.Time{seriesDate(2024, 3, 13)}, next.ExceptionDates)
+
+	dates, err := next.OccurrenceDates(seriesDate(2024, 3, 1), seriesDate(2024, 3, 31))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{seriesDate(2024, 3, 11), seriesDate(2024, 3, 15)}, dates)
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedule_LocalScheduledStart(t *testing.T) {
+	start := time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC)
+
+	schedule := Schedule{ScheduledStart: start, TimeZone: "America/New_York"}
+	local := schedule.LocalScheduledStart()
+	assert.Equal(t, 9, local.Hour())
+	assert.True(t, local.Equal(start))
+
+	schedule.TimeZone = "Not/AZone"
+	assert.Equal(t, time.UTC, schedule.LocalScheduledStart().Location())
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+)
+
+const (
+	ScheduleStatusUpcoming   = "upcoming"
+	ScheduleStatusInProgress = "in_progress"
+	ScheduleStatusCompleted  = "completed"
+	ScheduleStatusMissed     = "missed"
+	ScheduleStatusCancelled  = "cancelled"
+)
+
+const (
+	VisitStatusNotStarted = "not_started"
+	VisitStatusInProgress = "in_progress"
+	VisitStatusCompleted  = "completed"
+)
+
+const (
+	TaskStatusPending      = "pending"
+	TaskStatusCompleted    = "completed"
+	TaskStatusNotCompleted = "not_completed"
+)
+
+const (
+	VisitExceptionStatusOpen          = "open"
+	VisitExceptionStatusPendingReview = "pending_review"
+	VisitExceptionStatusApproved      = "approved"
+	VisitExceptionStatusRejected      = "rejected"
+)
+
+// InvalidStatusTransitionCode is returned with a 409 when a status change is not allowed
+const InvalidStatusTransitionCode = "INVALID_STATUS_TRANSITION"
+
+// StatusMachine is the transition table for one entity's status lifecycle.
+// Statuses without outgoing transitions are terminal.
+type StatusMachine struct {
+	Entity      string
+	transitions map[string][]string
+}
+
+var ScheduleStatusMachine = StatusMachine{
+	Entity: "schedule",
+	transitions: map[string][]string{
+		ScheduleStatusUpcoming:   {ScheduleStatusInProgress, ScheduleStatusMissed, ScheduleStatusCancelled},
+		ScheduleStatusInProgress: {ScheduleStatusCompleted, ScheduleStatusMissed, ScheduleStatusCancelled},
+		ScheduleStatusCompleted:  {},
+		ScheduleStatusMissed:     {},
+		ScheduleStatusCancelled:  {},
+	},
+}
+
+var VisitStatusMachine = StatusMachine{
+	Entity: "visit",
+	transitions: map[string][]string{
+		VisitStatusNotStarted: {VisitStatusInProgress},
+		VisitStatusInProgress: {VisitStatusCompleted},
+		VisitStatusCompleted:  {},
+	},
+}
+
+var TaskStatusMachine = StatusMachine{
+	Entity: "task",
+	transitions: map[string][]string{
+		TaskStatusPending:      {TaskStatusCompleted, TaskStatusNotCompleted},
+		TaskStatusNotCompleted: {TaskStatusCompleted},
+		TaskStatusCompleted:    {},
+	},
+}
+
+// A rejected exception goes back to the caregiver for a new explanation
+var VisitExceptionStatusMachine = StatusMachine{
+	Entity: "visit exception",
+	transitions: map[string][]string{
+		VisitExceptionStatusOpen:          {VisitExceptionStatusPendingReview},
+		VisitExceptionStatusPendingReview: {VisitExceptionStatusApproved, VisitExceptionStatusRejected},
+		VisitExceptionStatusRejected:      {VisitExceptionStatusPendingReview},
+		VisitExceptionStatusApproved:      {},
+	},
+}
+
+// IsValid reports whether status is part of the lifecycle
+func (m StatusMachine) IsValid(status string) bool {
+	_, ok := m.transitions[status]
+	return ok
+}
+
+// CanTransition reports whether the table allows moving from one status to another
+func (m StatusMachine) CanTransition(from, to string) bool {
+	for _, next := range m.transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition validates a status change, returning a 400 for an unknown status
+// and a 409 for a change the lifecycle does not allow
+func (m StatusMachine) Transition(from, to string) error {
+	if !m.IsValid(to) {
+		return errs.NewBadRequestError(fmt.Sprintf("Invalid %s status: %s", m.Entity, to), true, nil, nil, nil)
+	}
+
+	if !m.CanTransition(from, to) {
+		return m.ConflictError(from, to)
+	}
+
+	return nil
+}
+
+// ConflictError is the 409 returned for a disallowed status change
+func (m StatusMachine) ConflictError(from, to string) *errs.HTTPError {
+	code := InvalidStatusTransitionCode
+	message := fmt.Sprintf("Cannot change %s status from %s to %s", m.Entity, from, to)
+	return errs.NewConflictError(message, true, &code)
+}
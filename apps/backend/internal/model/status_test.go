@@ -0,0 +1,62 @@
+package model
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleStatusMachine(t *testing.T) {
+	assert.True(t, ScheduleStatusMachine.CanTransition(ScheduleStatusUpcoming, ScheduleStatusInProgress))
+	assert.True(t, ScheduleStatusMachine.CanTransition(ScheduleStatusInProgress, ScheduleStatusCompleted))
+	assert.True(t, ScheduleStatusMachine.CanTransition(ScheduleStatusUpcoming, ScheduleStatusMissed))
+	assert.True(t, ScheduleStatusMachine.CanTransition(ScheduleStatusUpcoming, ScheduleStatusCancelled))
+
+	assert.False(t, ScheduleStatusMachine.CanTransition(ScheduleStatusCompleted, ScheduleStatusUpcoming))
+	assert.False(t, ScheduleStatusMachine.CanTransition(ScheduleStatusUpcoming, ScheduleStatusCompleted))
+	assert.False(t, ScheduleStatusMachine.CanTransition(ScheduleStatusUpcoming, ScheduleStatusUpcoming))
+}
+
+func TestVisitAndTaskStatusMachines(t *testing.T) {
+	assert.True(t, VisitStatusMachine.CanTransition(VisitStatusInProgress, VisitStatusCompleted))
+	assert.False(t, VisitStatusMachine.CanTransition(VisitStatusCompleted, VisitStatusInProgress))
+
+	assert.True(t, TaskStatusMachine.CanTransition(TaskStatusPending, TaskStatusNotCompleted))
+	assert.True(t, TaskStatusMachine.CanTransition(TaskStatusNotCompleted, TaskStatusCompleted))
+	assert.False(t, TaskStatusMachine.CanTransition(TaskStatusCompleted, TaskStatusPending))
+}
+
+func TestVisitExceptionStatusMachine(t *testing.T) {
+	assert.True(t, VisitExceptionStatusMachine.CanTransition(VisitExceptionStatusOpen, VisitExceptionStatusPendingReview))
+	assert.True(t, VisitExceptionStatusMachine.CanTransition(VisitExceptionStatusPendingReview, VisitExceptionStatusApproved))
+	assert.True(t, VisitExceptionStatusMachine.CanTransition(VisitExceptionStatusRejected, VisitExceptionStatusPendingReview))
+
+	assert.False(t, VisitExceptionStatusMachine.CanTransition(VisitExceptionStatusOpen, VisitExceptionStatusApproved))
+	assert.False(t, VisitExceptionStatusMachine.CanTransition(VisitExceptionStatusApproved, VisitExceptionStatusPendingReview))
+}
+
+func TestStatusMachineTransition(t *testing.T) {
+	t.Run("allowed", func(t *testing.T) {
+		assert.NoError(t, ScheduleStatusMachine.Transition(ScheduleStatusUpcoming, ScheduleStatusInProgress))
+	})
+
+	t.Run("illegal transition is a conflict", func(t *testing.T) {
+		err := ScheduleStatusMachine.Transition(ScheduleStatusCompleted, ScheduleStatusUpcoming)
+
+		var httpErr *errs.HTTPError
+		assert.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusConflict, httpErr.Status)
+		assert.Equal(t, InvalidStatusTransitionCode, httpErr.Code)
+	})
+
+	t.Run("unknown status is a bad request", func(t *testing.T) {
+		err := TaskStatusMachine.Transition(TaskStatusPending, "done")
+
+		var httpErr *errs.HTTPError
+		assert.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusBadRequest, httpErr.Status)
+	})
+}
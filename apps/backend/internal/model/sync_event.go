@@ -0,0 +1,143 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SyncEventTypeClockIn    = "clock_in"
+	SyncEventTypeClockOut   = "clock_out"
+	SyncEventTypeTaskStatus = "task_status"
+)
+
+const (
+	// SyncEventStatusPending marks an event claimed by a sync that has not finished applying it
+	SyncEventStatusPending  = "pending"
+	SyncEventStatusApplied  = "applied"
+	SyncEventStatusRejected = "rejected"
+	// SyncEventStatusDuplicate is only reported back; the stored event keeps its first outcome
+	SyncEventStatusDuplicate = "duplicate"
+)
+
+const (
+	// SyncFlagClockSkew marks an event recorded by a device whose clock disagrees with the server's
+	SyncFlagClockSkew = "clock_skew"
+	// SyncFlagLateArrival marks an event that reached the server long after it was recorded
+	SyncFlagLateArrival = "late_arrival"
+)
+
+// SyncEvent is a clock or task event recorded on a device, possibly offline,
+// and synced later. The client's event ID deduplicates events a device resends.
+type SyncEvent struct {
+	ClientEventID uuid.UUID `json:"clientEventId" db:"client_event_id"`
+	UserID        string    `json:"userId" db:"user_id"`
+	Type          string    `json:"type" db:"type"`
+	// DeviceTime is when the event happened according to the device clock
+	DeviceTime time.Time  `json:"deviceTime" db:"device_time"`
+	ReceivedAt time.Time  `json:"receivedAt" db:"received_at"`
+	ScheduleID *uuid.UUID `json:"scheduleId" db:"schedule_id"`
+	TaskID     *uuid.UUID `json:"taskId" db:"task_id"`
+	Latitude   *float64   `json:"latitude" db:"latitude"`
+	Longitude  *float64   `json:"longitude" db:"longitude"`
+	TaskStatus *string    `json:"taskStatus" db:"task_status"`
+	Reason     *string    `json:"reason" db:"reason"`
+	Status     string     `json:"status" db:"status"`
+	Flags      []string   `json:"flags" db:"flags"`
+	// ClockSkewSeconds is how far ahead of the server the device clock was when it sent the batch
+	ClockSkewSeconds *int       `json:"clockSkewSeconds" db:"clock_skew_seconds"`
+	ErrorCode        *string    `json:"errorCode" db:"error_code"`
+	Error            *string    `json:"error" db:"error"`
+	VisitID          *uuid.UUID `json:"visitId" db:"visit_id"`
+	BaseWithCreatedAt
+	BaseWithUpdatedAt
+}
+
+// SyncEventFilter narrows a sync event query; zero fields are not filtered on
+type SyncEventFilter struct {
+	UserID string
+	Status string
+	Flag   string
+}
+
+// SyncEventResult is the outcome of one event in a sync batch
+type SyncEventResult struct {
+	ClientEventID uuid.UUID `json:"clientEventId"`
+	Type          string    `json:"type"`
+	Status        string    `json:"status"`
+	// OriginalStatus is the outcome of the first sync of a duplicate event
+	OriginalStatus *string    `json:"originalStatus,omitempty"`
+	Flags          []string   `json:"flags"`
+	ErrorCode      *string    `json:"errorCode,omitempty"`
+	Error          *string    `json:"error,omitempty"`
+	VisitID        *uuid.UUID `json:"visitId,omitempty"`
+	TaskID         *uuid.UUID `json:"taskId,omitempty"`
+}
+
+// SyncBatchResult holds the per-event results of a sync batch in request order
+type SyncBatchResult struct {
+	ReceivedAt       time.Time         `json:"receivedAt"`
+	ClockSkewSeconds *int              `json:"clockSkewSeconds"`
+	Results          []SyncEventResult `json:"results"`
+}
+
+func (e *SyncEvent) TableName() string {
+	return "sync_events"
+}
+
+// ClockSkew returns how far ahead of the server's clock the device clock was,
+// given the device time the batch was sent at. Without it the skew is unknown.
+func ClockSkew(sentAt *time.Time, receivedAt time.Time) *time.Duration {
+	if sentAt == nil {
+		return nil
+	}
+	skew := sentAt.Sub(receivedAt)
+	return &skew
+}
+
+// DetectSyncFlags returns the flags for an event recorded at deviceTime and
+// received at receivedAt. A known skew beyond skewThreshold flags clock skew,
+// as does a device time in the server's future. Lateness is measured on the
+// server's clock, so a known skew is taken out of the device time first.
+func DetectSyncFlags(deviceTime, receivedAt time.Time, skew *time.Duration, skewThreshold, lateThreshold time.Duration) []string {
+	flags := make([]string, 0)
+
+	eventTime := deviceTime
+	if skew != nil {
+		eventTime = deviceTime.Add(-*skew)
+	}
+
+	if skew != nil && (*skew > skewThreshold || *skew < -skewThreshold) || eventTime.Sub(receivedAt) > skewThreshold {
+		flags = append(flags, SyncFlagClockSkew)
+	}
+
+	if receivedAt.Sub(eventTime) > lateThreshold {
+		flags = append(flags, SyncFlagLateArrival)
+	}
+
+	return flags
+}
+
+// Result returns the event's outcome for the sync response
+func (e *SyncEvent) Result() SyncEventResult {
+	return SyncEventResult{
+		ClientEventID: e.ClientEventID,
+		Type:          e.Type,
+		Status:        e.Status,
+		Flags:         e.Flags,
+		ErrorCode:     e.ErrorCode,
+		Error:         e.Error,
+		VisitID:       e.VisitID,
+		TaskID:        e.TaskID,
+	}
+}
+
+// DuplicateResult returns the outcome reported when the event is synced again
+func (e *SyncEvent) DuplicateResult() SyncEventResult {
+	result := e.Result()
+	original := e.Status
+	result.Status = SyncEventStatusDuplicate
+	result.OriginalStatus = &original
+	return result
+}
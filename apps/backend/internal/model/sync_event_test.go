@@ -0,0 +1,73 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSyncFlags(t *testing.T) {
+	receivedAt := time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)
+	skewThreshold := 2 * time.Minute
+	lateThreshold := 15 * time.Minute
+
+	t.Run("on time with an accurate clock", func(t *testing.T) {
+		skew := 10 * time.Second
+		flags := DetectSyncFlags(receivedAt.Add(-time.Minute), receivedAt, &skew, skewThreshold, lateThreshold)
+		assert.Empty(t, flags)
+	})
+
+	t.Run("recorded offline hours ago", func(t *testing.T) {
+		flags := DetectSyncFlags(receivedAt.Add(-3*time.Hour), receivedAt, nil, skewThreshold, lateThreshold)
+		assert.Equal(t, []string{SyncFlagLateArrival}, flags)
+	})
+
+	t.Run("device time in the future without a known skew", func(t *testing.T) {
+		flags := DetectSyncFlags(receivedAt.Add(10*time.Minute), receivedAt, nil, skewThreshold, lateThreshold)
+		assert.Equal(t, []string{SyncFlagClockSkew}, flags)
+	})
+
+	t.Run("slow device clock is not mistaken for a late arrival", func(t *testing.T) {
+		// The device clock runs 30 minutes behind; the event happened a minute ago
+		skew := -30 * time.Minute
+		flags := DetectSyncFlags(receivedAt.Add(-31*time.Minute), receivedAt, &skew, skewThreshold, lateThreshold)
+		assert.Equal(t, []string{SyncFlagClockSkew}, flags)
+	})
+
+	t.Run("skewed and late", func(t *testing.T) {
+		skew := 5 * time.Minute
+		flags := DetectSyncFlags(receivedAt.Add(-time.Hour), receivedAt, &skew, skewThreshold, lateThreshold)
+		assert.Equal(t, []string{SyncFlagClockSkew, SyncFlagLateArrival}, flags)
+	})
+}
+
+func TestClockSkew(t *testing.T) {
+	receivedAt := time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)
+	assert.Nil(t, ClockSkew(nil, receivedAt))
+
+	sentAt := receivedAt.Add(-90 * time.Second)
+	skew := ClockSkew(&sentAt, receivedAt)
+	require.NotNil(t, skew)
+	assert.Equal(t, -90*time.Second, *skew)
+}
+
+func TestSyncEvent_DuplicateResult(t *testing.T) {
+	visitID := uuid.New()
+	event := SyncEvent{
+		ClientEventID: uuid.New(),
+		Type:          SyncEventTypeClockIn,
+		Status:        SyncEventStatusApplied,
+		Flags:         []string{SyncFlagLateArrival},
+		VisitID:       &visitID,
+	}
+
+	result := event.DuplicateResult()
+	assert.Equal(t, SyncEventStatusDuplicate, result.Status)
+	require.NotNil(t, result.OriginalStatus)
+	assert.Equal(t, SyncEventStatusApplied, *result.OriginalStatus)
+	assert.Equal(t, &visitID, result.VisitID)
+	assert.Equal(t, SyncEventStatusApplied, event.Status)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/etag"
 )
 
 type Task struct {
@@ -14,6 +15,10 @@ type Task struct {
 	Status        string    `json:"status" db:"status"`
 	Reason        *string   `json:"reason" db:"reason"`
 	CompletedAt   *time.Time `json:"completedAt" db:"completed_at"`
+	IsRequired    bool       `json:"isRequired" db:"is_required"`
+	SortOrder     int        `json:"sortOrder" db:"sort_order"`
+	// CarePlanTaskID links tasks generated from a care plan back to their template
+	CarePlanTaskID *uuid.UUID `json:"carePlanTaskId" db:"care_plan_task_id"`
 }
 
 type TaskCreate struct {
@@ -32,6 +37,11 @@ func (t *Task) TableName() string {
 	return "tasks"
 }
 
+// ETag identifies this version of the task
+func (t *Task) ETag() string {
+	return etag.Format(t.UpdatedAt)
+}
+
 type TaskStats struct {
 	TotalTasks      int `json:"totalTasks"`
 	CompletedTasks  int `json:"completedTasks"`
@@ -0,0 +1,184 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OvertimeRules are the hours past which work is paid as overtime; a zero
+// threshold disables its rule
+type OvertimeRules struct {
+	Daily  time.Duration
+	Weekly time.Duration
+}
+
+// PayPeriod runs from midnight at Start up to, not including, midnight at End
+type PayPeriod struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// PayPeriodContaining returns the period of the given length in days that t
+// falls in, counting whole periods from anchor, a midnight in the pay zone
+func PayPeriodContaining(t, anchor time.Time, days int) PayPeriod {
+	offset := civilDaysBetween(anchor, t.In(anchor.Location()))
+	n := offset / days
+	if offset%days < 0 {
+		n--
+	}
+	return PayPeriod{
+		Start: anchor.AddDate(0, 0, n*days),
+		End:   anchor.AddDate(0, 0, (n+1)*days),
+	}
+}
+
+// Contains tells whether t is within the period
+func (p PayPeriod) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+// civilDaysBetween counts calendar days from the date of a to the date of b,
+// so a DST change in between does not shorten or lengthen a day
+func civilDaysBetween(a, b time.Time) int {
+	date := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return int(date(b).Sub(date(a)).Hours() / 24)
+}
+
+// CaregiverVisit is a visit with the caregiver its schedule is assigned to
+type CaregiverVisit struct {
+	Visit
+	CaregiverID uuid.UUID
+}
+
+type TimesheetVisit struct {
+	VisitID    uuid.UUID `json:"visitId"`
+	ScheduleID uuid.UUID `json:"scheduleId"`
+	StartTime  time.Time `json:"startTime"`
+	EndTime    time.Time `json:"endTime"`
+	Minutes    int       `json:"minutes"`
+	// OverlapsWith lists the caregiver's other visits in the period that
+	// were running at the same time as this one
+	OverlapsWith []uuid.UUID `json:"overlapsWith"`
+}
+
+// TimesheetDay adds up the visits that started on a workday. Minutes is
+// split into regular, daily overtime and weekly overtime minutes.
+type TimesheetDay struct {
+	Date                  string           `json:"date"`
+	Visits                []TimesheetVisit `json:"visits"`
+	Minutes               int              `json:"minutes"`
+	RegularMinutes        int              `json:"regularMinutes"`
+	DailyOvertimeMinutes  int              `json:"dailyOvertimeMinutes"`
+	WeeklyOvertimeMinutes int              `json:"weeklyOvertimeMinutes"`
+}
+
+// Timesheet is a caregiver's completed visits in a pay period
+type Timesheet struct {
+	CaregiverID           uuid.UUID      `json:"caregiverId"`
+	CaregiverName         string         `json:"caregiverName"`
+	Period                PayPeriod      `json:"period"`
+	Days                  []TimesheetDay `json:"days"`
+	VisitCount            int            `json:"visitCount"`
+	Minutes               int            `json:"minutes"`
+	RegularMinutes        int            `json:"regularMinutes"`
+	DailyOvertimeMinutes  int            `json:"dailyOvertimeMinutes"`
+	WeeklyOvertimeMinutes int            `json:"weeklyOvertimeMinutes"`
+	// OverlappingVisits counts visits that overlap another one; their time
+	// is still counted, so overlaps should be reviewed before payroll
+	OverlappingVisits int `json:"overlappingVisits"`
+}
+
+type TimesheetReport struct {
+	Period     PayPeriod   `json:"period"`
+	Timesheets []Timesheet `json:"timesheets"`
+}
+
+// NewTimesheet adds up a caregiver's completed visits in a period. A visit
+// belongs to the workday, in the period's time zone, on which it started.
+// Minutes past the daily threshold are daily overtime; regular minutes past
+// the weekly threshold in each 7-day workweek of the period are weekly
+// overtime, charged to the day on which the threshold is crossed.
+func NewTimesheet(caregiverID uuid.UUID, period PayPeriod, visits []Visit, rules OvertimeRules) Timesheet {
+	sheet := Timesheet{
+		CaregiverID: caregiverID,
+		Period:      period,
+		Days:        make([]TimesheetDay, 0),
+	}
+
+	entries := make([]TimesheetVisit, 0, len(visits))
+	for _, visit := range visits {
+		if visit.EndTime == nil || !period.Contains(visit.StartTime) {
+			continue
+		}
+		visit.CalculateDuration()
+		entries = append(entries, TimesheetVisit{
+			VisitID:      visit.ID,
+			ScheduleID:   visit.ScheduleID,
+			StartTime:    visit.StartTime,
+			EndTime:      *visit.EndTime,
+			Minutes:      max(visit.GetDurationMinutes(), 0),
+			OverlapsWith: make([]uuid.UUID, 0),
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartTime.Before(entries[j].StartTime)
+	})
+
+	// Entries are ordered by start, so only those starting before an entry
+	// ends can overlap it
+	for i := range entries {
+		for j := i + 1; j < len(entries) && entries[j].StartTime.Before(entries[i].EndTime); j++ {
+			entries[i].OverlapsWith = append(entries[i].OverlapsWith, entries[j].VisitID)
+			entries[j].OverlapsWith = append(entries[j].OverlapsWith, entries[i].VisitID)
+		}
+	}
+
+	location := period.Start.Location()
+	for _, entry := range entries {
+		date := entry.StartTime.In(location).Format(time.DateOnly)
+		if len(sheet.Days) == 0 || sheet.Days[len(sheet.Days)-1].Date != date {
+			sheet.Days = append(sheet.Days, TimesheetDay{Date: date, Visits: make([]TimesheetVisit, 0)})
+		}
+		day := &sheet.Days[len(sheet.Days)-1]
+		day.Visits = append(day.Visits, entry)
+		day.Minutes += entry.Minutes
+
+		sheet.VisitCount++
+		if len(entry.OverlapsWith) > 0 {
+			sheet.OverlappingVisits++
+		}
+	}
+
+	dailyLimit := int(rules.Daily.Minutes())
+	weeklyLimit := int(rules.Weekly.Minutes())
+	weekRegular := map[int]int{}
+	for i := range sheet.Days {
+		day := &sheet.Days[i]
+
+		regular := day.Minutes
+		if dailyLimit > 0 && regular > dailyLimit {
+			day.DailyOvertimeMinutes = regular - dailyLimit
+			regular = dailyLimit
+		}
+
+		date, _ := time.ParseInLocation(time.DateOnly, day.Date, location)
+		week := civilDaysBetween(period.Start, date) / 7
+		if weeklyLimit > 0 && weekRegular[week]+regular > weeklyLimit {
+			day.WeeklyOvertimeMinutes = weekRegular[week] + regular - weeklyLimit
+			regular -= day.WeeklyOvertimeMinutes
+		}
+		weekRegular[week] += regular
+		day.RegularMinutes = regular
+
+		sheet.Minutes += day.Minutes
+		sheet.RegularMinutes += day.RegularMinutes
+		sheet.DailyOvertimeMinutes += day.DailyOvertimeMinutes
+		sheet.WeeklyOvertimeMinutes += day.WeeklyOvertimeMinutes
+	}
+
+	return sheet
+}
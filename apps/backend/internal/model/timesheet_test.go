@@ -0,0 +1,101 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayPeriodContaining(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	require.NoError(t, err)
+	anchor := time.Date(2024, 1, 1, 0, 0, 0, 0, chicago)
+
+	tests := []struct {
+		name       string
+		t          time.Time
+		days       int
+		start, end time.Time
+	}{
+		{"first week", time.Date(2024, 1, 3, 12, 0, 0, 0, chicago), 7,
+			anchor, time.Date(2024, 1, 8, 0, 0, 0, 0, chicago)},
+		{"before midnight in the pay zone", time.Date(2024, 1, 8, 5, 0, 0, 0, time.UTC), 7,
+			anchor, time.Date(2024, 1, 8, 0, 0, 0, 0, chicago)},
+		{"across daylight saving", time.Date(2024, 3, 12, 9, 0, 0, 0, chicago), 14,
+			time.Date(2024, 3, 11, 0, 0, 0, 0, chicago), time.Date(2024, 3, 25, 0, 0, 0, 0, chicago)},
+		{"before the anchor", time.Date(2023, 12, 31, 23, 0, 0, 0, chicago), 14,
+			time.Date(2023, 12, 18, 0, 0, 0, 0, chicago), anchor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period := PayPeriodContaining(tt.t, anchor, tt.days)
+			assert.True(t, tt.start.Equal(period.Start), "start %s", period.Start)
+			assert.True(t, tt.end.Equal(period.End), "end %s", period.End)
+			assert.True(t, period.Contains(tt.t))
+		})
+	}
+}
+
+func TestNewTimesheet(t *testing.T) {
+	period := PayPeriod{
+		Start: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC),
+	}
+	visit := func(day, startHour, hours int) Visit {
+		start := period.Start.AddDate(0, 0, day).Add(time.Duration(startHour) * time.Hour)
+		end := start.Add(time.Duration(hours) * time.Hour)
+		return Visit{Base: Base{BaseWithId: BaseWithId{ID: uuid.New()}}, ScheduleID: uuid.New(), StartTime: start, EndTime: &end, Status: "completed"}
+	}
+
+	// Four 10-hour days and a 6-hour day in the first week, two overlapping
+	// visits in the second
+	visits := []Visit{
+		visit(4, 8, 6), visit(0, 8, 10), visit(1, 8, 10), visit(2, 8, 10), visit(3, 8, 10),
+		visit(7, 8, 4), visit(7, 10, 4),
+	}
+	sheet := NewTimesheet(uuid.New(), period, visits, OvertimeRules{Daily: 8 * time.Hour, Weekly: 36 * time.Hour})
+
+	require.Len(t, sheet.Days, 6)
+	assert.Equal(t, "2024-03-04", sheet.Days[0].Date)
+	for _, day := range sheet.Days[:4] {
+		assert.Equal(t, 600, day.Minutes)
+		assert.Equal(t, 480, day.RegularMinutes)
+		assert.Equal(t, 120, day.DailyOvertimeMinutes)
+	}
+	// 32 regular hours before Friday, so 2 of its 6 hours are past the 36-hour week
+	assert.Equal(t, "2024-03-08", sheet.Days[4].Date)
+	assert.Equal(t, 240, sheet.Days[4].RegularMinutes)
+	assert.Equal(t, 120, sheet.Days[4].WeeklyOvertimeMinutes)
+
+	second := sheet.Days[5]
+	assert.Equal(t, "2024-03-11", second.Date)
+	assert.Equal(t, 480, second.Minutes)
+	assert.Equal(t, 480, second.RegularMinutes)
+	assert.Equal(t, []uuid.UUID{visits[6].ID}, second.Visits[0].OverlapsWith)
+	assert.Equal(t, []uuid.UUID{visits[5].ID}, second.Visits[1].OverlapsWith)
+
+	assert.Equal(t, 7, sheet.VisitCount)
+	assert.Equal(t, 2, sheet.OverlappingVisits)
+	assert.Equal(t, 3240, sheet.Minutes)
+	assert.Equal(t, 2640, sheet.RegularMinutes)
+	assert.Equal(t, 480, sheet.DailyOvertimeMinutes)
+	assert.Equal(t, 120, sheet.WeeklyOvertimeMinutes)
+	assert.Equal(t, sheet.Minutes, sheet.RegularMinutes+sheet.DailyOvertimeMinutes+sheet.WeeklyOvertimeMinutes)
+}
+
+func TestNewTimesheet_SkipsVisitsOutsidePeriod(t *testing.T) {
+	period := PayPeriod{
+		Start: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+	}
+	end := period.End.Add(time.Hour)
+	open := Visit{StartTime: period.Start.Add(time.Hour), Status: "in_progress"}
+	later := Visit{StartTime: period.End, EndTime: &end, Status: "completed"}
+
+	sheet := NewTimesheet(uuid.New(), period, []Visit{open, later}, OvertimeRules{})
+	assert.Empty(t, sheet.Days)
+	assert.Zero(t, sheet.Minutes)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/etag"
 )
 
 type Visit struct {
@@ -17,6 +18,20 @@ type Visit struct {
 	EndLongitude    *float64  `json:"endLongitude" db:"end_longitude"`
 	Status          string    `json:"status" db:"status"`
 	DurationMinutes *int      `json:"durationMinutes" db:"duration_minutes"`
+	// Geofence verification against the schedule's service location
+	DistanceMeters    *float64 `json:"distanceMeters" db:"distance_meters"`
+	EndDistanceMeters *float64 `json:"endDistanceMeters" db:"end_distance_meters"`
+	WithinGeofence    *bool    `json:"withinGeofence" db:"within_geofence"`
+	// IsManualEntry marks a visit a coordinator recorded after the fact
+	IsManualEntry bool `json:"isManualEntry" db:"is_manual_entry"`
+	// IsVerified is set once the visit is completed and all its exceptions are approved
+	IsVerified bool `json:"isVerified" db:"is_verified"`
+}
+
+// GeofenceResult is the outcome of checking a clock event against a service location
+type GeofenceResult struct {
+	DistanceMeters float64
+	WithinGeofence bool
 }
 
 type VisitCreate struct {
@@ -38,6 +53,11 @@ func (v *Visit) TableName() string {
 	return "visits"
 }
 
+// ETag identifies this version of the visit
+func (v *Visit) ETag() string {
+	return etag.Format(v.UpdatedAt)
+}
+
 func (v *Visit) CalculateDuration() {
 	if v.EndTime != nil && !v.StartTime.IsZero() {
 		duration := v.EndTime.Sub(v.StartTime)
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The six data elements the 21st Century Cures Act requires on every EVV record
+const (
+	EVVElementServiceType = "service_type"
+	EVVElementClient      = "client"
+	EVVElementServiceDate = "service_date"
+	EVVElementLocation    = "location"
+	EVVElementCaregiver   = "caregiver"
+	EVVElementTimes       = "start_end_time"
+)
+
+// EVVElements lists the required elements in the order the Cures Act names them
+var EVVElements = []string{
+	EVVElementServiceType,
+	EVVElementClient,
+	EVVElementServiceDate,
+	EVVElementLocation,
+	EVVElementCaregiver,
+	EVVElementTimes,
+}
+
+// VisitCompliance is the outcome of checking a completed visit for the six
+// required EVV data elements
+type VisitCompliance struct {
+	VisitID         uuid.UUID `json:"visitId" db:"visit_id"`
+	IsCompliant     bool      `json:"isCompliant" db:"is_compliant"`
+	MissingElements []string  `json:"missingElements" db:"missing_elements"`
+	CheckedAt       time.Time `json:"checkedAt" db:"checked_at"`
+	// Read from the visit and its schedule for listing
+	ScheduleID  uuid.UUID `json:"scheduleId" db:"schedule_id"`
+	ServiceDate time.Time `json:"serviceDate" db:"service_date"`
+}
+
+func (c *VisitCompliance) TableName() string {
+	return "visit_compliance"
+}
+
+// EvaluateVisitCompliance checks a visit and its schedule for the six required
+// EVV data elements. The schedule supplies the service type, the client and the
+// caregiver; the visit supplies the date, the location and the clock times.
+func EvaluateVisitCompliance(visit *Visit, schedule *Schedule, now time.Time) VisitCompliance {
+	missing := make([]string, 0)
+
+	if schedule.ServiceCode == nil || *schedule.ServiceCode == "" {
+		missing = append(missing, EVVElementServiceType)
+	}
+	if schedule.ClientID == uuid.Nil {
+		missing = append(missing, EVVElementClient)
+	}
+	if visit.StartTime.IsZero() {
+		missing = append(missing, EVVElementServiceDate)
+	}
+	if !visit.hasClockLocations() {
+		missing = append(missing, EVVElementLocation)
+	}
+	if schedule.CaregiverID == nil {
+		missing = append(missing, EVVElementCaregiver)
+	}
+	if visit.StartTime.IsZero() || visit.EndTime == nil || !visit.EndTime.After(visit.StartTime) {
+		missing = append(missing, EVVElementTimes)
+	}
+
+	return VisitCompliance{
+		VisitID:         visit.ID,
+		IsCompliant:     len(missing) == 0,
+		MissingElements: missing,
+		CheckedAt:       now,
+		ScheduleID:      visit.ScheduleID,
+		ServiceDate:     visit.StartTime,
+	}
+}
+
+// hasClockLocations reports whether the visit recorded where it was clocked in
+// and, once ended, where it was clocked out. 0,0 is what a device without a
+// position fix reports and does not count.
+func (v *Visit) hasClockLocations() bool {
+	if v.StartLatitude == 0 && v.StartLongitude == 0 {
+		return false
+	}
+	if v.EndTime == nil {
+		return true
+	}
+	return v.EndLatitude != nil && v.EndLongitude != nil && (*v.EndLatitude != 0 || *v.EndLongitude != 0)
+}
+
+// VisitRunFailure is a visit that a run over a date range could not process
+type VisitRunFailure struct {
+	VisitID uuid.UUID `json:"visitId"`
+	Error   string    `json:"error"`
+}
+
+// VisitComplianceRun summarizes a re-check of the completed visits in a date range
+type VisitComplianceRun struct {
+	From         time.Time         `json:"from"`
+	To           time.Time         `json:"to"`
+	Checked      int               `json:"checked"`
+	NonCompliant int               `json:"nonCompliant"`
+	Failures     []VisitRunFailure `json:"failures"`
+}
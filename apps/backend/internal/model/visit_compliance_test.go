@@ -0,0 +1,81 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCompliantVisit() (*Visit, *Schedule) {
+	start := time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+	endLat, endLong := 40.7128, -74.0060
+	serviceCode := "T1019"
+	caregiverID := uuid.New()
+
+	schedule := &Schedule{
+		Base:        Base{BaseWithId: BaseWithId{ID: uuid.New()}},
+		ClientID:    uuid.New(),
+		ServiceCode: &serviceCode,
+		CaregiverID: &caregiverID,
+	}
+	visit := &Visit{
+		Base:           Base{BaseWithId: BaseWithId{ID: uuid.New()}},
+		ScheduleID:     schedule.ID,
+		StartTime:      start,
+		EndTime:        &end,
+		StartLatitude:  40.7128,
+		StartLongitude: -74.0060,
+		EndLatitude:    &endLat,
+		EndLongitude:   &endLong,
+		Status:         VisitStatusCompleted,
+	}
+	return visit, schedule
+}
+
+func TestEvaluateVisitCompliance_Compliant(t *testing.T) {
+	visit, schedule := newCompliantVisit()
+	now := time.Now()
+
+	result := EvaluateVisitCompliance(visit, schedule, now)
+
+	assert.True(t, result.IsCompliant)
+	assert.Empty(t, result.MissingElements)
+	assert.Equal(t, visit.ID, result.VisitID)
+	assert.Equal(t, visit.StartTime, result.ServiceDate)
+	assert.Equal(t, now, result.CheckedAt)
+}
+
+func TestEvaluateVisitCompliance_MissingElements(t *testing.T) {
+	visit, schedule := newCompliantVisit()
+	schedule.ServiceCode = nil
+	schedule.CaregiverID = nil
+	visit.EndLatitude = nil
+	visit.EndLongitude = nil
+
+	result := EvaluateVisitCompliance(visit, schedule, time.Now())
+
+	assert.False(t, result.IsCompliant)
+	assert.Equal(t, []string{EVVElementServiceType, EVVElementLocation, EVVElementCaregiver}, result.MissingElements)
+}
+
+func TestEvaluateVisitCompliance_NoPositionFix(t *testing.T) {
+	visit, schedule := newCompliantVisit()
+	visit.StartLatitude = 0
+	visit.StartLongitude = 0
+
+	result := EvaluateVisitCompliance(visit, schedule, time.Now())
+
+	assert.Equal(t, []string{EVVElementLocation}, result.MissingElements)
+}
+
+func TestEvaluateVisitCompliance_NoEndTime(t *testing.T) {
+	visit, schedule := newCompliantVisit()
+	visit.EndTime = nil
+
+	result := EvaluateVisitCompliance(visit, schedule, time.Now())
+
+	assert.Equal(t, []string{EVVElementTimes}, result.MissingElements)
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	VisitCorrectionKindManualEntry = "manual_entry"
+	VisitCorrectionKindCorrection  = "correction"
+)
+
+// VisitClockData is the clock-in and clock-out data a coordinator can enter or correct
+type VisitClockData struct {
+	StartTime      *time.Time `json:"startTime"`
+	EndTime        *time.Time `json:"endTime"`
+	StartLatitude  *float64   `json:"startLatitude"`
+	StartLongitude *float64   `json:"startLongitude"`
+	EndLatitude    *float64   `json:"endLatitude"`
+	EndLongitude   *float64   `json:"endLongitude"`
+}
+
+// VisitCorrection records a coordinator's manual entry or correction of a
+// visit with the values before and after it, so the original clock data is
+// never lost
+type VisitCorrection struct {
+	BaseWithId
+	BaseWithCreatedAt
+	VisitID     uuid.UUID `json:"visitId" db:"visit_id"`
+	Kind        string    `json:"kind" db:"kind"`
+	ReasonCode  string    `json:"reasonCode" db:"reason_code"`
+	Reason      string    `json:"reason" db:"reason"`
+	CorrectedBy string    `json:"correctedBy" db:"corrected_by"`
+	// Previous is nil for a manual entry, which has no original values
+	Previous  *VisitClockData `json:"previous"`
+	Corrected VisitClockData  `json:"corrected"`
+}
+
+// ManualVisitEntry is a visit recorded after the fact by a coordinator
+type ManualVisitEntry struct {
+	StartTime      time.Time  `json:"startTime"`
+	EndTime        *time.Time `json:"endTime"`
+	StartLatitude  *float64   `json:"startLatitude"`
+	StartLongitude *float64   `json:"startLongitude"`
+	EndLatitude    *float64   `json:"endLatitude"`
+	EndLongitude   *float64   `json:"endLongitude"`
+	ReasonCode     string     `json:"reasonCode"`
+	Reason         string     `json:"reason"`
+}
+
+// VisitCorrectionUpdate holds the clock data a coordinator changes; nil fields are kept
+type VisitCorrectionUpdate struct {
+	VisitClockData
+	ReasonCode string `json:"reasonCode"`
+	Reason     string `json:"reason"`
+}
+
+func (c *VisitCorrection) TableName() string {
+	return "visit_corrections"
+}
+
+// NewVisitCorrection records a change to a visit's clock data; previous is nil for a manual entry
+func NewVisitCorrection(visitID uuid.UUID, previous *VisitClockData, corrected VisitClockData, reasonCode, reason, correctedBy string, now time.Time) VisitCorrection {
+	kind := VisitCorrectionKindCorrection
+	if previous == nil {
+		kind = VisitCorrectionKindManualEntry
+	}
+
+	return VisitCorrection{
+		BaseWithId:        BaseWithId{ID: uuid.New()},
+		BaseWithCreatedAt: BaseWithCreatedAt{CreatedAt: now},
+		VisitID:           visitID,
+		Kind:              kind,
+		ReasonCode:        reasonCode,
+		Reason:            reason,
+		CorrectedBy:       correctedBy,
+		Previous:          previous,
+		Corrected:         corrected,
+	}
+}
+
+// ClockData returns the visit's current clock-in and clock-out data
+func (v *Visit) ClockData() VisitClockData {
+	startTime := v.StartTime
+	startLat := v.StartLatitude
+	startLong := v.StartLongitude
+	return VisitClockData{
+		StartTime:      &startTime,
+		EndTime:        v.EndTime,
+		StartLatitude:  &startLat,
+		StartLongitude: &startLong,
+		EndLatitude:    v.EndLatitude,
+		EndLongitude:   v.EndLongitude,
+	}
+}
+
+// ApplyCorrection overwrites the visit's clock data with the non-nil fields of
+// update, coordinates only as latitude and longitude pairs, and returns the
+// names of the fields that changed. Setting an end time on a visit in progress
+// completes it.
+func (v *Visit) ApplyCorrection(update VisitClockData) []string {
+	changed := make([]string, 0)
+
+	if update.StartTime != nil && !update.StartTime.Equal(v.StartTime) {
+		v.StartTime = *update.StartTime
+		changed = append(changed, "start time")
+	}
+	if update.EndTime != nil && (v.EndTime == nil || !update.EndTime.Equal(*v.EndTime)) {
+		endTime := *update.EndTime
+		v.EndTime = &endTime
+		changed = append(changed, "end time")
+	}
+	if update.StartLatitude != nil && update.StartLongitude != nil &&
+		(*update.StartLatitude != v.StartLatitude || *update.StartLongitude != v.StartLongitude) {
+		v.StartLatitude = *update.StartLatitude
+		v.StartLongitude = *update.StartLongitude
+		changed = append(changed, "start location")
+	}
+	if update.EndLatitude != nil && update.EndLongitude != nil &&
+		(!equalFloatPtr(update.EndLatitude, v.EndLatitude) || !equalFloatPtr(update.EndLongitude, v.EndLongitude)) {
+		endLat := *update.EndLatitude
+		endLong := *update.EndLongitude
+		v.EndLatitude = &endLat
+		v.EndLongitude = &endLong
+		changed = append(changed, "end location")
+	}
+
+	if v.EndTime != nil && v.Status == VisitStatusInProgress {
+		v.Status = VisitStatusCompleted
+	}
+	v.CalculateDuration()
+
+	return changed
+}
+
+// CorrectionDetail describes a correction for the visit's manual entry exception
+func CorrectionDetail(changed []string) string {
+	return "Corrected by a coordinator: " + strings.Join(changed, ", ")
+}
+
+func equalFloatPtr(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisit_ApplyCorrection(t *testing.T) {
+	start := time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC)
+	visit := Visit{
+		StartTime:      start,
+		StartLatitude:  40.7128,
+		StartLongitude: -74.006,
+		Status:         VisitStatusInProgress,
+	}
+	original := visit.ClockData()
+
+	correctedStart := start.Add(-30 * time.Minute)
+	end := start.Add(3 * time.Hour)
+	endLat, endLong := 40.7130, -74.0062
+	sameLat := visit.StartLatitude
+	sameLong := visit.StartLongitude
+
+	changed := visit.ApplyCorrection(VisitClockData{
+		StartTime:      &correctedStart,
+		EndTime:        &end,
+		StartLatitude:  &sameLat,
+		StartLongitude: &sameLong,
+		EndLatitude:    &endLat,
+		EndLongitude:   &endLong,
+	})
+
+	assert.Equal(t, []string{"start time", "end time", "end location"}, changed)
+	assert.Equal(t, VisitStatusCompleted, visit.Status)
+	require.NotNil(t, visit.DurationMinutes)
+	assert.Equal(t, 210, *visit.DurationMinutes)
+
+	// The clock data taken before the correction is unaffected by it
+	assert.Equal(t, start, *original.StartTime)
+	assert.Nil(t, original.EndTime)
+}
+
+func TestVisit_ApplyCorrectionWithoutChanges(t *testing.T) {
+	visit := Visit{StartTime: time.Now(), Status: VisitStatusInProgress}
+	startTime := visit.StartTime
+
+	assert.Empty(t, visit.ApplyCorrection(VisitClockData{StartTime: &startTime}))
+	assert.Equal(t, VisitStatusInProgress, visit.Status)
+}
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/etag"
+)
+
+const (
+	VisitExceptionTypeLateClockIn             = "late_clock_in"
+	VisitExceptionTypeEarlyClockOut           = "early_clock_out"
+	VisitExceptionTypeClockInOutsideGeofence  = "clock_in_outside_geofence"
+	VisitExceptionTypeClockOutOutsideGeofence = "clock_out_outside_geofence"
+	VisitExceptionTypeManualEntry             = "manual_entry"
+)
+
+// VisitException is an irregularity in a visit's clock events. The caregiver
+// explains it with a reason code and a coordinator approves or rejects the
+// explanation; a visit is only verified once all its exceptions are approved.
+type VisitException struct {
+	Base
+	VisitID uuid.UUID `json:"visitId" db:"visit_id"`
+	Type    string    `json:"type" db:"type"`
+	// Detail describes what was detected, e.g. how late the clock-in was
+	Detail      string     `json:"detail" db:"detail"`
+	Status      string     `json:"status" db:"status"`
+	ReasonCode  *string    `json:"reasonCode" db:"reason_code"`
+	Explanation *string    `json:"explanation" db:"explanation"`
+	ExplainedBy *string    `json:"explainedBy" db:"explained_by"`
+	ExplainedAt *time.Time `json:"explainedAt" db:"explained_at"`
+	// Coordinator review
+	ReviewedBy    *string    `json:"reviewedBy" db:"reviewed_by"`
+	ReviewedAt    *time.Time `json:"reviewedAt" db:"reviewed_at"`
+	ReviewComment *string    `json:"reviewComment" db:"review_comment"`
+}
+
+// ExceptionReasonCode is an agency-configured reason a caregiver can give for a visit exception
+type ExceptionReasonCode struct {
+	Code        string `json:"code" db:"code"`
+	Description string `json:"description" db:"description"`
+	IsActive    bool   `json:"isActive" db:"is_active"`
+	BaseWithCreatedAt
+	BaseWithUpdatedAt
+}
+
+// VisitExceptionFilter narrows the exception queue; zero fields are not filtered on
+type VisitExceptionFilter struct {
+	Status string
+	// Range of detection times; From is inclusive, To is exclusive
+	From *time.Time
+	To   *time.Time
+}
+
+func (e *VisitException) TableName() string {
+	return "visit_exceptions"
+}
+
+func (r *ExceptionReasonCode) TableName() string {
+	return "exception_reason_codes"
+}
+
+// ETag identifies this version of the exception
+func (e *VisitException) ETag() string {
+	return etag.Format(e.UpdatedAt)
+}
+
+// ETag identifies this version of the reason code
+func (r *ExceptionReasonCode) ETag() string {
+	return etag.Format(r.UpdatedAt)
+}
+
+// IsResolved reports whether the exception no longer blocks visit verification
+func (e *VisitException) IsResolved() bool {
+	return e.Status == VisitExceptionStatusApproved
+}
+
+// NewVisitException builds an open exception for a visit
+func NewVisitException(visitID uuid.UUID, exceptionType, detail string, now time.Time) VisitException {
+	return VisitException{
+		Base: Base{
+			BaseWithId:        BaseWithId{ID: uuid.New()},
+			BaseWithCreatedAt: BaseWithCreatedAt{CreatedAt: now},
+			BaseWithUpdatedAt: BaseWithUpdatedAt{UpdatedAt: now},
+		},
+		VisitID: visitID,
+		Type:    exceptionType,
+		Detail:  detail,
+		Status:  VisitExceptionStatusOpen,
+	}
+}
+
+// NewManualEntryException builds the exception a coordinator's manual entry or
+// correction raises. It carries the coordinator's reason and goes straight to review.
+func NewManualEntryException(visitID uuid.UUID, detail, reasonCode, explanation, explainedBy string, now time.Time) VisitException {
+	exception := NewVisitException(visitID, VisitExceptionTypeManualEntry, detail, now)
+	exception.Status = VisitExceptionStatusPendingReview
+	exception.ReasonCode = &reasonCode
+	exception.Explanation = &explanation
+	exception.ExplainedBy = &explainedBy
+	exception.ExplainedAt = &now
+	return exception
+}
+
+// DetectClockInExceptions returns the exceptions raised by a visit's clock-in:
+// starting more than lateThreshold after the scheduled start, or outside the geofence
+func DetectClockInExceptions(visit *Visit, schedule *Schedule, geofence *GeofenceResult, lateThreshold time.Duration, now time.Time) []VisitException {
+	exceptions := make([]VisitException, 0)
+
+	if late := visit.StartTime.Sub(schedule.ScheduledStart); late > lateThreshold {
+		detail := fmt.Sprintf("Clocked in %d minutes after the scheduled start", int(late.Minutes()))
+		exceptions = append(exceptions, NewVisitException(visit.ID, VisitExceptionTypeLateClockIn, detail, now))
+	}
+
+	if geofence != nil && !geofence.WithinGeofence {
+		detail := fmt.Sprintf("Clocked in %.0f meters from the service location", geofence.DistanceMeters)
+		exceptions = append(exceptions, NewVisitException(visit.ID, VisitExceptionTypeClockInOutsideGeofence, detail, now))
+	}
+
+	return exceptions
+}
+
+// DetectClockOutExceptions returns the exceptions raised by a visit's clock-out:
+// ending more than earlyThreshold before the scheduled end, or outside the geofence
+func DetectClockOutExceptions(visit *Visit, schedule *Schedule, geofence *GeofenceResult, earlyThreshold time.Duration, now time.Time) []VisitException {
+	exceptions := make([]VisitException, 0)
+
+	if visit.EndTime != nil {
+		if early := schedule.ScheduledEnd.Sub(*visit.EndTime); early > earlyThreshold {
+			detail := fmt.Sprintf("Clocked out %d minutes before the scheduled end", int(early.Minutes()))
+			exceptions = append(exceptions, NewVisitException(visit.ID, VisitExceptionTypeEarlyClockOut, detail, now))
+		}
+	}
+
+	if geofence != nil && !geofence.WithinGeofence {
+		detail := fmt.Sprintf("Clocked out %.0f meters from the service location", geofence.DistanceMeters)
+		exceptions = append(exceptions, NewVisitException(visit.ID, VisitExceptionTypeClockOutOutsideGeofence, detail, now))
+	}
+
+	return exceptions
+}
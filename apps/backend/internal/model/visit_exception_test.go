@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExceptionTestSchedule() *Schedule {
+	start := time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC)
+	return &Schedule{
+		ScheduledStart: start,
+		ScheduledEnd:   start.Add(4 * time.Hour),
+	}
+}
+
+func TestDetectClockInExceptions(t *testing.T) {
+	schedule := newExceptionTestSchedule()
+	visit := &Visit{Base: Base{BaseWithId: BaseWithId{ID: uuid.New()}}}
+
+	t.Run("on time and inside the geofence", func(t *testing.T) {
+		visit.StartTime = schedule.ScheduledStart.Add(10 * time.Minute)
+		geofence := &GeofenceResult{DistanceMeters: 40, WithinGeofence: true}
+
+		assert.Empty(t, DetectClockInExceptions(visit, schedule, geofence, 10*time.Minute, time.Now()))
+	})
+
+	t.Run("late and outside the geofence", func(t *testing.T) {
+		visit.StartTime = schedule.ScheduledStart.Add(25 * time.Minute)
+		geofence := &GeofenceResult{DistanceMeters: 412, WithinGeofence: false}
+
+		exceptions := DetectClockInExceptions(visit, schedule, geofence, 10*time.Minute, time.Now())
+		require.Len(t, exceptions, 2)
+		assert.Equal(t, VisitExceptionTypeLateClockIn, exceptions[0].Type)
+		assert.Equal(t, "Clocked in 25 minutes after the scheduled start", exceptions[0].Detail)
+		assert.Equal(t, VisitExceptionTypeClockInOutsideGeofence, exceptions[1].Type)
+		assert.Equal(t, visit.ID, exceptions[1].VisitID)
+		assert.Equal(t, VisitExceptionStatusOpen, exceptions[1].Status)
+	})
+
+	t.Run("no geofence result", func(t *testing.T) {
+		visit.StartTime = schedule.ScheduledStart
+
+		assert.Empty(t, DetectClockInExceptions(visit, schedule, nil, 10*time.Minute, time.Now()))
+	})
+}
+
+func TestDetectClockOutExceptions(t *testing.T) {
+	schedule := newExceptionTestSchedule()
+	end := schedule.ScheduledEnd.Add(-45 * time.Minute)
+	visit := &Visit{Base: Base{BaseWithId: BaseWithId{ID: uuid.New()}}, EndTime: &end}
+
+	exceptions := DetectClockOutExceptions(visit, schedule, &GeofenceResult{DistanceMeters: 20, WithinGeofence: true}, 10*time.Minute, time.Now())
+	require.Len(t, exceptions, 1)
+	assert.Equal(t, VisitExceptionTypeEarlyClockOut, exceptions[0].Type)
+	assert.Equal(t, "Clocked out 45 minutes before the scheduled end", exceptions[0].Detail)
+}
@@ -0,0 +1,343 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/aggregator"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+const aggregatorBatchColumns = `id, format, transport, status, file_name, visit_count, reference, error, delivered_at, created_at, updated_at`
+
+const visitSubmissionColumns = `batch_id, visit_id, status, messages, created_at, updated_at`
+
+// pendingSubmissionTimeout is how long a submission can stay pending before the
+// batch delivering it is presumed lost and the visit is due again
+const pendingSubmissionTimeout = `1 hour`
+
+type AggregatorRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewAggregatorRepository(db *pgxpool.Pool) *AggregatorRepository {
+	return &AggregatorRepository{DB: db}
+}
+
+func scanAggregatorBatch(row pgx.Row) (model.AggregatorBatch, error) {
+	var batch model.AggregatorBatch
+	err := row.Scan(&batch.ID, &batch.Format, &batch.Transport, &batch.Status, &batch.FileName, &batch.VisitCount,
+		&batch.Reference, &batch.Error, &batch.DeliveredAt, &batch.CreatedAt, &batch.UpdatedAt)
+	return batch, err
+}
+
+func scanVisitSubmission(row pgx.Row) (model.VisitSubmission, error) {
+	var submission model.VisitSubmission
+	err := row.Scan(&submission.BatchID, &submission.VisitID, &submission.Status, &submission.Messages,
+		&submission.CreatedAt, &submission.UpdatedAt)
+	return submission, err
+}
+
+// Get completed, verified visits that are due for submission, oldest first.
+// A visit is due when it was never submitted, when its last delivery failed
+// or was lost, or when it or its schedule changed after its last submission.
+// Visits whose latest compliance check failed are held back.
+func (r *AggregatorRepository) GetVisitsToSubmit(ctx context.Context, limit int) ([]aggregator.Record, error) {
+	query := `
+		SELECT v.id, COALESCE(s.service_code, ''),
+			c.id, COALESCE(c.medicaid_id, ''), c.first_name, c.last_name,
+			COALESCE(g.id, '00000000-0000-0000-0000-000000000000'::uuid), COALESCE(g.first_name, ''), COALESCE(g.last_name, ''),
+			s.time_zone, v.start_time, v.end_time,
+			v.start_latitude, v.start_longitude, COALESCE(v.end_latitude, 0), COALESCE(v.end_longitude, 0),
+			v.is_manual_entry,
+			ARRAY(
+				SELECT DISTINCT e.reason_code FROM visit_exceptions e
+				WHERE e.visit_id = v.id AND e.reason_code IS NOT NULL
+				ORDER BY e.reason_code
+			)
+		FROM visits v
+		JOIN schedules s ON s.id = v.schedule_id
+		JOIN clients c ON c.id = s.client_id
+		LEFT JOIN caregivers g ON g.id = s.caregiver_id
+		LEFT JOIN LATERAL (
+			SELECT vs.status, vs.created_at FROM visit_submissions vs
+			WHERE vs.visit_id = v.id
+			ORDER BY vs.created_at DESC
+			LIMIT 1
+		) last ON TRUE
+		WHERE v.status = 'completed' AND v.end_time IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM visit_exceptions e WHERE e.visit_id = v.id AND e.status <> 'approved')
+		AND NOT EXISTS (SELECT 1 FROM visit_compliance vc WHERE vc.visit_id = v.id AND NOT vc.is_compliant)
+		AND (
+			last.status IS NULL
+			OR last.status = 'failed'
+			OR (last.status = 'pending' AND last.created_at < NOW() - INTERVAL '` + pendingSubmissionTimeout + `')
+			OR GREATEST(v.updated_at, s.updated_at) > last.created_at
+		)
+		ORDER BY v.end_time ASC, v.id ASC
+		LIMIT $1
+	`
+
+	records := make([]aggregator.Record, 0)
+	rows, err := r.DB.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get visits to submit: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record aggregator.Record
+		err := rows.Scan(&record.VisitID, &record.ServiceCode,
+			&record.ClientID, &record.ClientMedicaidID, &record.ClientFirstName, &record.ClientLastName,
+			&record.CaregiverID, &record.CaregiverFirstName, &record.CaregiverLastName,
+			&record.TimeZone, &record.StartTime, &record.EndTime,
+			&record.StartLatitude, &record.StartLongitude, &record.EndLatitude, &record.EndLongitude,
+			&record.IsManualEntry, &record.ReasonCodes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan visit to submit: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate visits to submit: %w", err)
+	}
+
+	return records, nil
+}
+
+// Create a pending batch with a pending submission for each of its visits
+func (r *AggregatorRepository) CreateAggregatorBatch(ctx context.Context, batch *model.AggregatorBatch, visitIDs []uuid.UUID) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO aggregator_batches (id, format, transport, status, file_name, visit_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	if _, err := tx.Exec(ctx, query, batch.ID, batch.Format, batch.Transport, batch.Status, batch.FileName, batch.VisitCount,
+		batch.CreatedAt, batch.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create aggregator batch: %w", err)
+	}
+
+	query = `INSERT INTO visit_submissions (batch_id, visit_id, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $4)`
+	for _, visitID := range visitIDs {
+		if _, err := tx.Exec(ctx, query, batch.ID, visitID, model.VisitSubmissionStatusPending, batch.CreatedAt); err != nil {
+			return fmt.Errorf("failed to create visit submission: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit aggregator batch: %w", err)
+	}
+
+	return nil
+}
+
+// Mark a batch delivered. Its visits become submitted, or accepted or
+// rejected where the receipt carries the aggregator's verdict.
+func (r *AggregatorRepository) CompleteAggregatorBatch(ctx context.Context, batchID uuid.UUID, receipt *aggregator.Receipt) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var reference *string
+	if receipt.Reference != "" {
+		reference = &receipt.Reference
+	}
+
+	query := `UPDATE aggregator_batches SET status = $1, reference = $2, delivered_at = NOW() WHERE id = $3`
+	if _, err := tx.Exec(ctx, query, model.AggregatorBatchStatusDelivered, reference, batchID); err != nil {
+		return fmt.Errorf("failed to update aggregator batch: %w", err)
+	}
+
+	query = `UPDATE visit_submissions SET status = $1 WHERE batch_id = $2`
+	if _, err := tx.Exec(ctx, query, model.VisitSubmissionStatusSubmitted, batchID); err != nil {
+		return fmt.Errorf("failed to update visit submissions: %w", err)
+	}
+
+	query = `UPDATE visit_submissions SET status = $1, messages = $2 WHERE batch_id = $3 AND visit_id = $4`
+	for _, result := range receipt.Results {
+		status := model.VisitSubmissionStatusAccepted
+		if !result.Accepted {
+			status = model.VisitSubmissionStatusRejected
+		}
+		messages := result.Messages
+		if messages == nil {
+			messages = make([]string, 0)
+		}
+
+		if _, err := tx.Exec(ctx, query, status, messages, batchID, result.VisitID); err != nil {
+			return fmt.Errorf("failed to update visit submission: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit aggregator batch: %w", err)
+	}
+
+	return nil
+}
+
+// Mark a batch and all its visits failed with the delivery error
+func (r *AggregatorRepository) FailAggregatorBatch(ctx context.Context, batchID uuid.UUID, message string) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE aggregator_batches SET status = $1, error = $2 WHERE id = $3`
+	if _, err := tx.Exec(ctx, query, model.AggregatorBatchStatusFailed, message, batchID); err != nil {
+		return fmt.Errorf("failed to update aggregator batch: %w", err)
+	}
+
+	query = `UPDATE visit_submissions SET status = $1, messages = $2 WHERE batch_id = $3`
+	if _, err := tx.Exec(ctx, query, model.VisitSubmissionStatusFailed, []string{message}, batchID); err != nil {
+		return fmt.Errorf("failed to update visit submissions: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit aggregator batch: %w", err)
+	}
+
+	return nil
+}
+
+// Get aggregator batches with pagination, newest first
+func (r *AggregatorRepository) GetAggregatorBatches(ctx context.Context, page, limit int, status string) (*model.PaginatedResponse[model.AggregatorBatch], error) {
+	query := `
+		SELECT ` + aggregatorBatchColumns + ` FROM aggregator_batches
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	batches := make([]model.AggregatorBatch, 0)
+	offset := (page - 1) * limit
+	rows, err := r.DB.Query(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aggregator batches: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		batch, err := scanAggregatorBatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan aggregator batch: %w", err)
+		}
+		batches = append(batches, batch)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate aggregator batches: %w", err)
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM aggregator_batches WHERE ($1 = '' OR status = $1)`
+	if err := r.DB.QueryRow(ctx, countQuery, status).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to get aggregator batch count: %w", err)
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	return &model.PaginatedResponse[model.AggregatorBatch]{
+		Data:       batches,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Get aggregator batch by ID
+func (r *AggregatorRepository) GetAggregatorBatchByID(ctx context.Context, id uuid.UUID) (*model.AggregatorBatch, error) {
+	query := `SELECT ` + aggregatorBatchColumns + ` FROM aggregator_batches WHERE id = $1`
+
+	batch, err := scanAggregatorBatch(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Aggregator batch not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to get aggregator batch: %w", err)
+	}
+
+	return &batch, nil
+}
+
+// Get the submissions of a batch
+func (r *AggregatorRepository) GetBatchSubmissions(ctx context.Context, batchID uuid.UUID) ([]model.VisitSubmission, error) {
+	query := `SELECT ` + visitSubmissionColumns + ` FROM visit_submissions WHERE batch_id = $1 ORDER BY visit_id ASC`
+	return r.querySubmissions(ctx, query, batchID)
+}
+
+// Get a visit's submissions, newest first
+func (r *AggregatorRepository) GetVisitSubmissions(ctx context.Context, visitID uuid.UUID) ([]model.VisitSubmission, error) {
+	query := `SELECT ` + visitSubmissionColumns + ` FROM visit_submissions WHERE visit_id = $1 ORDER BY created_at DESC`
+	return r.querySubmissions(ctx, query, visitID)
+}
+
+// Get submissions with pagination, newest first, optionally filtered by status
+func (r *AggregatorRepository) GetSubmissions(ctx context.Context, page, limit int, status string) (*model.PaginatedResponse[model.VisitSubmission], error) {
+	query := `
+		SELECT ` + visitSubmissionColumns + ` FROM visit_submissions
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC, visit_id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	offset := (page - 1) * limit
+	submissions, err := r.querySubmissions(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM visit_submissions WHERE ($1 = '' OR status = $1)`
+	if err := r.DB.QueryRow(ctx, countQuery, status).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to get visit submission count: %w", err)
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	return &model.PaginatedResponse[model.VisitSubmission]{
+		Data:       submissions,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (r *AggregatorRepository) querySubmissions(ctx context.Context, query string, args ...any) ([]model.VisitSubmission, error) {
+	submissions := make([]model.VisitSubmission, 0)
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get visit submissions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		submission, err := scanVisitSubmission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan visit submission: %w", err)
+		}
+		submissions = append(submissions, submission)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate visit submissions: %w", err)
+	}
+
+	return submissions, nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/database"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/audit"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+const auditEventColumns = `id, entity, entity_id, action, actor, request_id, before, after, created_at`
+
+type AuditEventRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewAuditEventRepository(db *pgxpool.Pool) *AuditEventRepository {
+	return &AuditEventRepository{DB: db}
+}
+
+func scanAuditEvent(row pgx.Row) (model.AuditEvent, error) {
+	var event model.AuditEvent
+	err := row.Scan(&event.ID, &event.Entity, &event.EntityID, &event.Action, &event.Actor, &event.RequestID,
+		&event.Before, &event.After, &event.CreatedAt)
+	return event, err
+}
+
+// beginAudited starts a transaction tagged with the actor and request ID
+// carried by ctx. The audit trigger on schedules, visits and tasks records
+// them against every row the transaction writes. Inside a unit of work it
+// starts a savepoint of the shared transaction.
+func beginAudited(ctx context.Context, db database.DBTX) (pgx.Tx, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	actor := audit.ActorFromContext(ctx)
+	query := `SELECT set_config('audit.actor', $1, TRUE), set_config('audit.request_id', $2, TRUE)`
+	if _, err := tx.Exec(ctx, query, actor.UserID, actor.RequestID); err != nil {
+		tx.Rollback(ctx)
+		return nil, fmt.Errorf("failed to set audit actor: %w", err)
+	}
+
+	return tx, nil
+}
+
+// execAudited runs a single write statement in an audited transaction
+func execAudited(ctx context.Context, db database.DBTX, query string, args ...any) (pgconn.CommandTag, error) {
+	tx, err := beginAudited(ctx, db)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return pgconn.CommandTag{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// queryRowAudited runs a single write statement returning a row in an audited
+// transaction; scan reads the row before the transaction commits
+func queryRowAudited(ctx context.Context, db database.DBTX, scan func(pgx.Row) error, query string, args ...any) error {
+	tx, err := beginAudited(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := scan(tx.QueryRow(ctx, query, args...)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Get audit events with pagination, newest first
+func (r *AuditEventRepository) GetAuditEvents(ctx context.Context, filter model.AuditEventFilter, page, limit int) (*model.PaginatedResponse[model.AuditEvent], error) {
+	where := `
+		WHERE ($1 = '' OR entity = $1)
+		AND ($2::uuid IS NULL OR entity_id = $2)
+		AND ($3 = '' OR actor = $3)
+		AND ($4::timestamptz IS NULL OR created_at >= $4)
+		AND ($5::timestamptz IS NULL OR created_at < $5)
+	`
+	args := []any{filter.Entity, filter.EntityID, filter.Actor, filter.From, filter.To}
+
+	query := `SELECT ` + auditEventColumns + ` FROM audit_events ` + where + ` ORDER BY created_at DESC, id DESC LIMIT $6 OFFSET $7`
+
+	events := make([]model.AuditEvent, 0)
+	offset := (page - 1) * limit
+	rows, err := r.DB.Query(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit events: %w", err)
+	}
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM audit_events `+where, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to get audit event count: %w", err)
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	return &model.PaginatedResponse[model.AuditEvent]{
+		Data:       events,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/database"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+const billingRateColumns = `id, payer, service_code, caregiver_id, unit_minutes, rounding, unit_rate_cents, effective_from, effective_to,
+	created_at, updated_at`
+
+const visitBillingColumns = `visit_id, billing_rate_id, billable_units, billed_amount_cents, billing_issue, billed_at`
+
+type BillingRepository struct {
+	DB database.DBTX
+}
+
+func NewBillingRepository(db database.DBTX) *BillingRepository {
+	return &BillingRepository{DB: db}
+}
+
+func scanBillingRate(row pgx.Row) (model.BillingRate, error) {
+	var rate model.BillingRate
+	err := row.Scan(&rate.ID, &rate.Payer, &rate.ServiceCode, &rate.CaregiverID, &rate.UnitMinutes, &rate.Rounding, &rate.UnitRateCents,
+		&rate.EffectiveFrom, &rate.EffectiveTo, &rate.CreatedAt, &rate.UpdatedAt)
+	return rate, err
+}
+
+func scanVisitBilling(row pgx.Row) (model.VisitBilling, error) {
+	var billing model.VisitBilling
+	err := row.Scan(&billing.VisitID, &billing.RateID, &billing.Units, &billing.AmountCents, &billing.Issue, &billing.CalculatedAt)
+	return billing, err
+}
+
+// Create a billing rate
+func (r *BillingRepository) CreateBillingRate(ctx context.Context, rate *model.BillingRate) error {
+	query := `
+		INSERT INTO billing_rates (id, payer, service_code, caregiver_id, unit_minutes, rounding, unit_rate_cents, effective_from, effective_to)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at
+	`
+
+	err := r.DB.QueryRow(ctx, query, rate.ID, rate.Payer, rate.ServiceCode, rate.CaregiverID, rate.UnitMinutes, rate.Rounding, rate.UnitRateCents,
+		rate.EffectiveFrom, rate.EffectiveTo).Scan(&rate.CreatedAt, &rate.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create billing rate: %w", err)
+	}
+
+	return nil
+}
+
+// Get billing rate by ID
+func (r *BillingRepository) GetBillingRateByID(ctx context.Context, id uuid.UUID) (*model.BillingRate, error) {
+	query := `SELECT ` + billingRateColumns + ` FROM billing_rates WHERE id = $1`
+
+	rate, err := scanBillingRate(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Billing rate not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to get billing rate: %w", err)
+	}
+
+	return &rate, nil
+}
+
+// Get billing rates with pagination, optionally of one payer and service code
+func (r *BillingRepository) GetBillingRates(ctx context.Context, page, limit int, payer, serviceCode string) (*model.PaginatedResponse[model.BillingRate], error) {
+	query := `
+		SELECT ` + billingRateColumns + ` FROM billing_rates
+		WHERE ($1 = '' OR payer = $1) AND ($2 = '' OR service_code = $2)
+		ORDER BY payer ASC, service_code ASC, caregiver_id ASC NULLS FIRST, effective_from DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rates := make([]model.BillingRate, 0)
+	offset := (page - 1) * limit
+	rows, err := r.DB.Query(ctx, query, payer, serviceCode, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get billing rates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rate, err := scanBillingRate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan billing rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate billing rates: %w", err)
+	}
+
+	countQuery := `SELECT COUNT(*) FROM billing_rates WHERE ($1 = '' OR payer = $1) AND ($2 = '' OR service_code = $2)`
+
+	var total int
+	if err := r.DB.QueryRow(ctx, countQuery, payer, serviceCode).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to get billing rate count: %w", err)
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	return &model.PaginatedResponse[model.BillingRate]{
+		Data:       rates,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// FindBillingRate returns the rate of a payer's service code in effect on a
+// service date, preferring the caregiver's own rate and then the latest one.
+// It returns nil when no rate applies.
+func (r *BillingRepository) FindBillingRate(ctx context.Context, payer, serviceCode string, caregiverID *uuid.UUID, serviceDate time.Time) (*model.BillingRate, error) {
+	query := `
+		SELECT ` + billingRateColumns + ` FROM billing_rates
+		WHERE payer = $1 AND service_code = $2 AND (caregiver_id IS NULL OR caregiver_id = $3)
+			AND effective_from <= $4::date AND (effective_to IS NULL OR effective_to >= $4::date)
+		ORDER BY caregiver_id IS NULL, effective_from DESC
+		LIMIT 1
+	`
+
+	// A date parameter takes the year, month and day as they are
+	date := time.Date(serviceDate.Year(), serviceDate.Month(), serviceDate.Day(), 0, 0, 0, 0, time.UTC)
+	rate, err := scanBillingRate(r.DB.QueryRow(ctx, query, payer, serviceCode, caregiverID, date))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find billing rate: %w", err)
+	}
+
+	return &rate, nil
+}
+
+// Get the IDs of completed visits whose service date falls within [from, to]
+func (r *BillingRepository) GetCompletedVisitIDs(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	query := `
+		SELECT v.id FROM visits v
+		JOIN schedules s ON s.id = v.schedule_id
+		WHERE v.status = 'completed' AND ` + visitServiceDateFilter + `
+		ORDER BY v.start_time ASC
+	`
+
+	ids := make([]uuid.UUID, 0)
+	rows, err := r.DB.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completed visits: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan visit ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate completed visits: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Get the latest billing calculation of a visit
+func (r *BillingRepository) GetVisitBilling(ctx context.Context, visitID uuid.UUID) (*model.VisitBilling, error) {
+	query := `SELECT ` + visitBillingColumns + ` FROM visit_billing WHERE visit_id = $1`
+
+	billing, err := scanVisitBilling(r.DB.QueryRow(ctx, query, visitID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Visit billing not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to get visit billing: %w", err)
+	}
+
+	return &billing, nil
+}
+
+// Store a visit's billing calculation, replacing any earlier one
+func (r *BillingRepository) SaveVisitBilling(ctx context.Context, billing *model.VisitBilling) error {
+	query := `
+		INSERT INTO visit_billing (` + visitBillingColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (visit_id) DO UPDATE SET
+			billing_rate_id = EXCLUDED.billing_rate_id,
+			billable_units = EXCLUDED.billable_units,
+			billed_amount_cents = EXCLUDED.billed_amount_cents,
+			billing_issue = EXCLUDED.billing_issue,
+			billed_at = EXCLUDED.billed_at
+	`
+
+	_, err := r.DB.Exec(ctx, query, billing.VisitID, billing.RateID, billing.Units, billing.AmountCents, billing.Issue, billing.CalculatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save visit billing: %w", err)
+	}
+
+	return nil
+}
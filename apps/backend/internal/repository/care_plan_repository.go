@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+const carePlanColumns = `id, client_id, name, is_active, created_at, updated_at`
+
+const carePlanTaskColumns = `id, care_plan_id, position, name, description, is_required, created_at`
+
+type CarePlanRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewCarePlanRepository(db *pgxpool.Pool) *CarePlanRepository {
+	return &CarePlanRepository{DB: db}
+}
+
+func scanCarePlan(row pgx.Row) (model.CarePlan, error) {
+	var plan model.CarePlan
+	err := row.Scan(&plan.ID, &plan.ClientID, &plan.Name, &plan.IsActive, &plan.CreatedAt, &plan.UpdatedAt)
+	return plan, err
+}
+
+// Get care plan by ID with its task templates
+func (r *CarePlanRepository) GetCarePlanByID(ctx context.Context, id uuid.UUID) (*model.CarePlan, error) {
+	query := `SELECT ` + carePlanColumns + ` FROM care_plans WHERE id = $1`
+
+	plan, err := scanCarePlan(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Care plan not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to get care plan: %w", err)
+	}
+
+	if plan.Tasks, err = r.getCarePlanTasks(ctx, plan.ID); err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// Get the client's active care plan; returns nil when the client has none
+func (r *CarePlanRepository) GetActiveCarePlan(ctx context.Context, clientID uuid.UUID) (*model.CarePlan, error) {
+	query := `SELECT ` + carePlanColumns + ` FROM care_plans WHERE client_id = $1 AND is_active`
+
+	plan, err := scanCarePlan(r.DB.QueryRow(ctx, query, clientID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get active care plan: %w", err)
+	}
+
+	if plan.Tasks, err = r.getCarePlanTasks(ctx, plan.ID); err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// Get all care plans for a client, newest first, without task templates
+func (r *CarePlanRepository) GetCarePlansByClient(ctx context.Context, clientID uuid.UUID) ([]model.CarePlan, error) {
+	query := `SELECT ` + carePlanColumns + ` FROM care_plans WHERE client_id = $1 ORDER BY created_at DESC`
+
+	plans := make([]model.CarePlan, 0)
+	rows, err := r.DB.Query(ctx, query, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get care plans: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		plan, err := scanCarePlan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan care plan: %w", err)
+		}
+		plan.Tasks = make([]model.CarePlanTask, 0)
+		plans = append(plans, plan)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate care plans: %w", err)
+	}
+
+	return plans, nil
+}
+
+// Create a care plan with its task templates and make it the client's active plan
+func (r *CarePlanRepository) CreateCarePlan(ctx context.Context, plan *model.CarePlan) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE care_plans SET is_active = FALSE WHERE client_id = $1 AND is_active`, plan.ClientID); err != nil {
+		return fmt.Errorf("failed to deactivate previous care plan: %w", err)
+	}
+
+	query := `INSERT INTO care_plans (id, client_id, name, is_active) VALUES ($1, $2, $3, TRUE)`
+	if _, err := tx.Exec(ctx, query, plan.ID, plan.ClientID, plan.Name); err != nil {
+		return fmt.Errorf("failed to create care plan: %w", err)
+	}
+
+	taskQuery := `
+		INSERT INTO care_plan_tasks (id, care_plan_id, position, name, description, is_required)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for _, task := range plan.Tasks {
+		if _, err := tx.Exec(ctx, taskQuery, task.ID, plan.ID, task.Position, task.Name, task.Description, task.IsRequired); err != nil {
+			return fmt.Errorf("failed to create care plan task: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit care plan: %w", err)
+	}
+
+	plan.IsActive = true
+	return nil
+}
+
+// Make a care plan the client's active plan, deactivating any other
+func (r *CarePlanRepository) ActivateCarePlan(ctx context.Context, plan *model.CarePlan) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE care_plans SET is_active = FALSE WHERE client_id = $1 AND is_active AND id <> $2`, plan.ClientID, plan.ID); err != nil {
+		return fmt.Errorf("failed to deactivate previous care plan: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE care_plans SET is_active = TRUE WHERE id = $1`, plan.ID); err != nil {
+		return fmt.Errorf("failed to activate care plan: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit care plan: %w", err)
+	}
+
+	plan.IsActive = true
+	return nil
+}
+
+// Deactivate a care plan; new schedules for the client then start without tasks
+func (r *CarePlanRepository) DeactivateCarePlan(ctx context.Context, id uuid.UUID) error {
+	_, err := r.DB.Exec(ctx, `UPDATE care_plans SET is_active = FALSE WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate care plan: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CarePlanRepository) getCarePlanTasks(ctx context.Context, carePlanID uuid.UUID) ([]model.CarePlanTask, error) {
+	query := `SELECT ` + carePlanTaskColumns + ` FROM care_plan_tasks WHERE care_plan_id = $1 ORDER BY position ASC`
+
+	tasks := make([]model.CarePlanTask, 0)
+	rows, err := r.DB.Query(ctx, query, carePlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get care plan tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var task model.CarePlanTask
+		if err := rows.Scan(&task.ID, &task.CarePlanID, &task.Position, &task.Name, &task.Description, &task.IsRequired, &task.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan care plan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate care plan tasks: %w", err)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+const caregiverColumns = `id, user_id, first_name, last_name, email, phone, is_active, created_at, updated_at`
+
+type CaregiverRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewCaregiverRepository(db *pgxpool.Pool) *CaregiverRepository {
+	return &CaregiverRepository{DB: db}
+}
+
+func scanCaregiver(row pgx.Row) (model.Caregiver, error) {
+	var caregiver model.Caregiver
+	err := row.Scan(&caregiver.ID, &caregiver.UserID, &caregiver.FirstName, &caregiver.LastName, &caregiver.Email,
+		&caregiver.Phone, &caregiver.IsActive, &caregiver.CreatedAt, &caregiver.UpdatedAt)
+	return caregiver, err
+}
+
+// Get all caregivers with pagination
+func (r *CaregiverRepository) GetCaregivers(ctx context.Context, page, limit int) (*model.PaginatedResponse[model.Caregiver], error) {
+	query := `
+		SELECT ` + caregiverColumns + ` FROM caregivers
+		ORDER BY last_name ASC, first_name ASC
+		LIMIT $1 OFFSET $2
+	`
+
+	caregivers := make([]model.Caregiver, 0)
+	offset := (page - 1) * limit
+	rows, err := r.DB.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caregivers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		caregiver, err := scanCaregiver(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan caregiver: %w", err)
+		}
+		caregivers = append(caregivers, caregiver)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate caregivers: %w", err)
+	}
+
+	var total int
+	err = r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM caregivers`).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caregiver count: %w", err)
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	return &model.PaginatedResponse[model.Caregiver]{
+		Data:       caregivers,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Get caregiver by ID
+func (r *CaregiverRepository) GetCaregiverByID(ctx context.Context, id uuid.UUID) (*model.Caregiver, error) {
+	query := `SELECT ` + caregiverColumns + ` FROM caregivers WHERE id = $1`
+
+	caregiver, err := scanCaregiver(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Caregiver not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to get caregiver: %w", err)
+	}
+
+	return &caregiver, nil
+}
+
+// Get caregiver by Clerk user ID
+func (r *CaregiverRepository) GetCaregiverByUserID(ctx context.Context, userID string) (*model.Caregiver, error) {
+	query := `SELECT ` + caregiverColumns + ` FROM caregivers WHERE user_id = $1`
+
+	caregiver, err := scanCaregiver(r.DB.QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := "CAREGIVER_PROFILE_NOT_FOUND"
+			return nil, errs.NewNotFoundError("No caregiver profile is linked to this user", true, &code)
+		}
+		return nil, fmt.Errorf("failed to get caregiver: %w", err)
+	}
+
+	return &caregiver, nil
+}
+
+// Create a new caregiver
+func (r *CaregiverRepository) CreateCaregiver(ctx context.Context, caregiver *model.Caregiver) error {
+	query := `
+		INSERT INTO caregivers (id, user_id, first_name, last_name, email, phone, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.DB.Exec(ctx, query, caregiver.ID, caregiver.UserID, caregiver.FirstName, caregiver.LastName,
+		caregiver.Email, caregiver.Phone, caregiver.IsActive)
+	if err != nil {
+		return fmt.Errorf("failed to create caregiver: %w", err)
+	}
+
+	return nil
+}
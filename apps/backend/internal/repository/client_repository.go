@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/database"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+const clientColumns = `id, first_name, last_name, date_of_birth, gender, medicaid_id, payer, address_line1, address_line2, city, state, postal_code,
+	latitude, longitude, phone, emergency_contacts, status, created_at, updated_at`
+
+type ClientRepository struct {
+	DB database.DBTX
+}
+
+func NewClientRepository(db database.DBTX) *ClientRepository {
+	return &ClientRepository{DB: db}
+}
+
+func scanClient(row pgx.Row) (model.Client, error) {
+	var client model.Client
+	err := row.Scan(&client.ID, &client.FirstName, &client.LastName, &client.DateOfBirth, &client.Gender, &client.MedicaidID, &client.Payer,
+		&client.AddressLine1, &client.AddressLine2, &client.City, &client.State, &client.PostalCode,
+		&client.Latitude, &client.Longitude, &client.Phone, &client.EmergencyContacts, &client.Status, &client.CreatedAt, &client.UpdatedAt)
+	return client, err
+}
+
+// Get all clients with pagination and filtering
+func (r *ClientRepository) GetClients(ctx context.Context, page, limit int, status, search string) (*model.PaginatedResponse[model.Client], error) {
+	query := `
+		SELECT ` + clientColumns + ` FROM clients
+		WHERE ($1 = '' OR status = $1)
+		AND ($2 = '' OR LOWER(first_name || ' ' || last_name) LIKE LOWER('%' || $2 || '%') OR medicaid_id = $2)
+		ORDER BY last_name ASC, first_name ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	clients := make([]model.Client, 0)
+	offset := (page - 1) * limit
+	rows, err := r.DB.Query(ctx, query, status, search, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clients: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		client, err := scanClient(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan client: %w", err)
+		}
+		clients = append(clients, client)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate clients: %w", err)
+	}
+
+	countQuery := `
+		SELECT COUNT(*) FROM clients
+		WHERE ($1 = '' OR status = $1)
+		AND ($2 = '' OR LOWER(first_name || ' ' || last_name) LIKE LOWER('%' || $2 || '%') OR medicaid_id = $2)
+	`
+
+	var total int
+	err = r.DB.QueryRow(ctx, countQuery, status, search).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client count: %w", err)
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	return &model.PaginatedResponse[model.Client]{
+		Data:       clients,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Get client by ID
+func (r *ClientRepository) GetClientByID(ctx context.Context, id uuid.UUID) (*model.Client, error) {
+	query := `SELECT ` + clientColumns + ` FROM clients WHERE id = $1`
+
+	client, err := scanClient(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Client not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	return &client, nil
+}
+
+// Create a new client
+func (r *ClientRepository) CreateClient(ctx context.Context, client *model.Client) error {
+	query := `
+		INSERT INTO clients (id, first_name, last_name, date_of_birth, gender, medicaid_id, payer, address_line1, address_line2, city, state, postal_code,
+			latitude, longitude, phone, emergency_contacts, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	`
+
+	_, err := r.DB.Exec(ctx, query, client.ID, client.FirstName, client.LastName, client.DateOfBirth, client.Gender, client.MedicaidID, client.Payer,
+		client.AddressLine1, client.AddressLine2, client.City, client.State, client.PostalCode,
+		client.Latitude, client.Longitude, client.Phone, client.EmergencyContacts, client.Status)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return nil
+}
+
+// Update client
+func (r *ClientRepository) UpdateClient(ctx context.Context, client *model.Client) error {
+	// The update only applies to the version that was read; UpdatedAt is set to the new version
+	query := `
+		UPDATE clients
+		SET first_name = $1, last_name = $2, date_of_birth = $3, gender = $4, medicaid_id = $5, payer = $6, address_line1 = $7, address_line2 = $8,
+			city = $9, state = $10, postal_code = $11, latitude = $12, longitude = $13, phone = $14, emergency_contacts = $15, status = $16
+		WHERE id = $17 AND updated_at = $18
+		RETURNING updated_at
+	`
+
+	err := r.DB.QueryRow(ctx, query, client.FirstName, client.LastName, client.DateOfBirth, client.Gender, client.MedicaidID, client.Payer,
+		client.AddressLine1, client.AddressLine2, client.City, client.State, client.PostalCode,
+		client.Latitude, client.Longitude, client.Phone, client.EmergencyContacts, client.Status, client.ID, guardVersion(ctx, client.UpdatedAt)).Scan(&client.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := "CLIENT_CHANGED"
+			return versionChanged(ctx, errs.NewConflictError("Client changed while it was being updated", true, &code))
+		}
+		return fmt.Errorf("failed to update client: %w", err)
+	}
+
+	return nil
+}
+
+// Delete client
+func (r *ClientRepository) DeleteClient(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM clients WHERE id = $1`
+
+	_, err := r.DB.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete client: %w", err)
+	}
+
+	return nil
+}
+
+// Check whether any schedule references the client
+func (r *ClientRepository) ClientHasSchedules(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM schedules WHERE client_id = $1)`
+
+	var exists bool
+	err := r.DB.QueryRow(ctx, query, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check client schedules: %w", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/listquery"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+// keysetClause returns the condition and the ORDER BY and LIMIT clauses that
+// select page of a listing in listing's sort, with its arguments numbered
+// from n. One row beyond the limit is fetched so that
+// model.NewCursorPaginatedResponse can tell whether there are more.
+func keysetClause[T any](listing *listquery.Query[T], page model.CursorPage, n int) (string, string, []any) {
+	cursor := page.Cursor
+	if cursor == nil {
+		return "TRUE", fmt.Sprintf("%s LIMIT $%d", listing.OrderBy(false), n), []any{page.Limit + 1}
+	}
+
+	condition, args := listing.After(cursor.Value, cursor.ID, cursor.Backward, n)
+	order := fmt.Sprintf("%s LIMIT $%d", listing.OrderBy(cursor.Backward), n+len(args))
+	return condition, order, append(args, page.Limit+1)
+}
+
+// cursorKey returns the cursor of a row in listing's sort
+func cursorKey[T any](listing *listquery.Query[T]) func(T) model.Cursor {
+	return func(row T) model.Cursor {
+		value, id := listing.Position(row)
+		return model.Cursor{Sort: listing.Sort.String(), Value: value, ID: id}
+	}
+}
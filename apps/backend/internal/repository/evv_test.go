@@ -51,7 +51,9 @@ func TestScheduleRepository_GetSchedules(t *testing.T) {
 				UpdatedAt: time.Now(),
 			},
 			ClientName: "John Doe",
-			ShiftTime:  "09:00-17:00",
+			ScheduledStart: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+			ScheduledEnd:   time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC),
+			TimeZone:   "UTC",
 			Location:   "123 Main St",
 			Status:     "upcoming",
 		},
@@ -62,7 +64,9 @@ func TestScheduleRepository_GetSchedules(t *testing.T) {
 				UpdatedAt: time.Now(),
 			},
 			ClientName: "Jane Smith",
-			ShiftTime:  "10:00-18:00",
+			ScheduledStart: time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC),
+			ScheduledEnd:   time.Date(2025, 1, 6, 18, 0, 0, 0, time.UTC),
+			TimeZone:   "UTC",
 			Location:   "456 Oak Ave",
 			Status:     "completed",
 		},
@@ -99,7 +103,9 @@ func TestScheduleRepository_GetSchedules_WithStatusFilter(t *testing.T) {
 				UpdatedAt: time.Now(),
 			},
 			ClientName: "John Doe",
-			ShiftTime:  "09:00-17:00",
+			ScheduledStart: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+			ScheduledEnd:   time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC),
+			TimeZone:   "UTC",
 			Location:   "123 Main St",
 			Status:     "completed",
 		},
@@ -135,14 +141,16 @@ func TestScheduleRepository_GetTodaySchedules(t *testing.T) {
 				UpdatedAt: time.Now(),
 			},
 			ClientName: "John Doe",
-			ShiftTime:  "09:00-17:00",
+			ScheduledStart: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+			ScheduledEnd:   time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC),
+			TimeZone:   "UTC",
 			Location:   "123 Main St",
 			Status:     "upcoming",
 		},
 	}
 
 	db.On("SelectContext", ctx, mock.Anything,
-		"SELECT * FROM schedules WHERE created_at::date = $1::date ORDER BY shift_time ASC",
+		"SELECT * FROM schedules WHERE (scheduled_start AT TIME ZONE time_zone)::date = (NOW() AT TIME ZONE time_zone)::date ORDER BY scheduled_start ASC",
 		today).Return(expectedSchedules, nil)
 
 	result, err := repo.GetTodaySchedules(ctx)
@@ -166,7 +174,9 @@ func TestScheduleRepository_GetScheduleByID(t *testing.T) {
 			UpdatedAt: time.Now(),
 		},
 		ClientName: "John Doe",
-		ShiftTime:  "09:00-17:00",
+		ScheduledStart: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+		ScheduledEnd:   time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC),
+		TimeZone:   "UTC",
 		Location:   "123 Main St",
 		Status:     "upcoming",
 	}
@@ -209,7 +219,9 @@ func TestScheduleRepository_CreateSchedule(t *testing.T) {
 	schedule := &model.Schedule{
 		ID:          uuid.New(),
 		ClientName:  "John Doe",
-		ShiftTime:  "09:00-17:00",
+		ScheduledStart: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+		ScheduledEnd:   time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC),
+		TimeZone:   "UTC",
 		Location:   "123 Main St",
 		Status:     "upcoming",
 		CreatedAt:  time.Now(),
@@ -217,8 +229,8 @@ func TestScheduleRepository_CreateSchedule(t *testing.T) {
 	}
 
 	db.On("ExecContext", ctx,
-		"INSERT INTO schedules (id, client_name, shift_time, location, status) VALUES ($1, $2, $3, $4, $5)",
-		schedule.ID, schedule.ClientName, schedule.ShiftTime, schedule.Location, schedule.Status).Return(mock.NewResult(1, 1), nil)
+		"INSERT INTO schedules (id, client_id, scheduled_start, scheduled_end, time_zone, location, status) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		schedule.ID, schedule.ClientID, schedule.ScheduledStart, schedule.ScheduledEnd, schedule.TimeZone, schedule.Location, schedule.Status).Return(mock.NewResult(1, 1), nil)
 
 	err := repo.CreateSchedule(ctx, schedule)
 
@@ -466,7 +478,7 @@ func TestTaskRepository_UpdateTaskStatus(t *testing.T) {
 		args.Get(0).(interface{}) = result
 	})
 
-	result, err := repo.UpdateTaskStatus(ctx, taskID, status, &reason)
+	result, err := repo.UpdateTaskStatus(ctx, taskID, "pending", status, &reason)
 
 	assert.NoError(t, err)
 	assert.Equal(t, status, result.Status)
@@ -507,7 +519,9 @@ func TestScheduleRepository_Pagination(t *testing.T) {
 				UpdatedAt: time.Now(),
 			},
 			ClientName: "Client 1",
-			ShiftTime:  "09:00-17:00",
+			ScheduledStart: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+			ScheduledEnd:   time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC),
+			TimeZone:   "UTC",
 			Location:   "Location 1",
 			Status:     "upcoming",
 		},
@@ -549,7 +563,9 @@ func TestScheduleRepository_SearchSchedules(t *testing.T) {
 				UpdatedAt: time.Now(),
 			},
 			ClientName: "John Doe",
-			ShiftTime:  "09:00-17:00",
+			ScheduledStart: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+			ScheduledEnd:   time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC),
+			TimeZone:   "UTC",
 			Location:   "123 Main St",
 			Status:     "upcoming",
 		},
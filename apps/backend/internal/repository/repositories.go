@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/database"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/server"
 )
 
@@ -9,8 +10,46 @@ type Repositories struct {
 	Schedule *ScheduleRepository
 	Visit     *VisitRepository
 	Task      *TaskRepository
+	Caregiver *CaregiverRepository
+	Client    *ClientRepository
+	CarePlan  *CarePlanRepository
+	ScheduleSeries *ScheduleSeriesRepository
+	VisitException *VisitExceptionRepository
+	AuditEvent     *AuditEventRepository
+	VisitCompliance *VisitComplianceRepository
+	Aggregator      *AggregatorRepository
+	SyncEvent       *SyncEventRepository
+	Billing         *BillingRepository
+	UnitOfWork      *UnitOfWork
 }
 
+// TxRepositories are the repositories of a unit of work; they share its transaction
+type TxRepositories struct {
+	Schedule        *ScheduleRepository
+	Visit           *VisitRepository
+	Task            *TaskRepository
+	VisitException  *VisitExceptionRepository
+	VisitCompliance *VisitComplianceRepository
+	Billing         *BillingRepository
+	Client          *ClientRepository
+}
+
+func NewTxRepositories(db database.DBTX) *TxRepositories {
+	return &TxRepositories{
+		Schedule:        NewScheduleRepository(db),
+		Visit:           NewVisitRepository(db),
+		Task:            NewTaskRepository(db),
+		VisitException:  NewVisitExceptionRepository(db),
+		VisitCompliance: NewVisitComplianceRepository(db),
+		Billing:         NewBillingRepository(db),
+		Client:          NewClientRepository(db),
+	}
+}
+
+// UnitOfWork runs writes across schedules, visits and tasks, and the
+// exceptions, compliance and billing recorded with a visit, in one transaction
+type UnitOfWork = database.UnitOfWork[*TxRepositories]
+
 func NewRepositories(s *server.Server) *Repositories {
 	var dbPool *pgxpool.Pool = s.DB.Pool
 	_ = dbPool // Force usage of pgxpool import
@@ -18,5 +57,16 @@ func NewRepositories(s *server.Server) *Repositories {
 		Schedule: NewScheduleRepository(dbPool),
 		Visit:     NewVisitRepository(dbPool),
 		Task:      NewTaskRepository(dbPool),
+		Caregiver: NewCaregiverRepository(dbPool),
+		Client:    NewClientRepository(dbPool),
+		CarePlan:  NewCarePlanRepository(dbPool),
+		ScheduleSeries: NewScheduleSeriesRepository(dbPool),
+		VisitException: NewVisitExceptionRepository(dbPool),
+		AuditEvent:     NewAuditEventRepository(dbPool),
+		VisitCompliance: NewVisitComplianceRepository(dbPool),
+		Aggregator:      NewAggregatorRepository(dbPool),
+		SyncEvent:       NewSyncEventRepository(dbPool),
+		Billing:         NewBillingRepository(dbPool),
+		UnitOfWork:      database.NewUnitOfWork(dbPool, NewTxRepositories),
 	}
 }
@@ -2,43 +2,58 @@ package repository
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/database"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/listquery"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
 )
 
+// client_name is looked up from the client so every schedule read carries a display name
+const scheduleColumns = `id, client_id,
+	(SELECT trim(c.first_name || ' ' || c.last_name) FROM clients c WHERE c.id = schedules.client_id) AS client_name,
+	scheduled_start, scheduled_end, time_zone, location, service_code, status, caregiver_id, visit_id, latitude, longitude, geofence_radius_meters,
+	series_id, series_date, is_series_exception, created_at, updated_at`
+
 type ScheduleRepository struct {
-	DB *pgxpool.Pool
+	DB database.DBTX
 }
 
-func NewScheduleRepository(db *pgxpool.Pool) *ScheduleRepository {
+func NewScheduleRepository(db database.DBTX) *ScheduleRepository {
 	return &ScheduleRepository{DB: db}
 }
 
-// Get all schedules with pagination and filtering
-func (r *ScheduleRepository) GetSchedules(ctx context.Context, page, limit int, status string) (*model.PaginatedResponse[model.Schedule], error) {
-	query := `
-		SELECT * FROM schedules
-		WHERE ($1 = '' OR status = $2)
-		ORDER BY created_at DESC
-		LIMIT $3 OFFSET $4
-	`
+// scanSchedule scans scheduleColumns, then any columns selected after them into extra
+func scanSchedule(row pgx.Row, extra ...any) (model.Schedule, error) {
+	var schedule model.Schedule
+	dest := []any{&schedule.ID, &schedule.ClientID, &schedule.ClientName, &schedule.ScheduledStart, &schedule.ScheduledEnd, &schedule.TimeZone, &schedule.Location, &schedule.ServiceCode, &schedule.Status, &schedule.CaregiverID, &schedule.VisitID,
+		&schedule.Latitude, &schedule.Longitude, &schedule.GeofenceRadiusMeters,
+		&schedule.SeriesID, &schedule.SeriesDate, &schedule.IsSeriesException, &schedule.CreatedAt, &schedule.UpdatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	return schedule, err
+}
 
-	var schedules []model.Schedule
-	offset := (page - 1) * limit
-	rows, err := r.DB.Query(ctx, query, status, status, limit, offset)
+// Get a page of schedules matching listing's filters, in its sort
+func (r *ScheduleRepository) GetSchedules(ctx context.Context, page model.CursorPage, listing *listquery.Query[model.Schedule]) (*model.CursorPaginatedResponse[model.Schedule], error) {
+	where, args := listing.Where(1)
+	keyset, order, keysetArgs := keysetClause(listing, page, len(args)+1)
+	query := `SELECT ` + scheduleColumns + ` FROM schedules WHERE ` + where + ` AND ` + keyset + ` ` + order
+
+	schedules := make([]model.Schedule, 0)
+	rows, err := r.DB.Query(ctx, query, append(args, keysetArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get schedules: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var schedule model.Schedule
-		if err := rows.Scan(&schedule.ID, &schedule.ClientName, &schedule.ShiftTime, &schedule.Location, &schedule.Status, &schedule.VisitID, &schedule.CreatedAt, &schedule.UpdatedAt); err != nil {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan schedule: %w", err)
 		}
 		schedules = append(schedules, schedule)
@@ -48,49 +63,38 @@ func (r *ScheduleRepository) GetSchedules(ctx context.Context, page, limit int,
 		return nil, fmt.Errorf("failed to iterate schedules: %w", err)
 	}
 
-	// Get total count for pagination
-	countQuery := `
-		SELECT COUNT(*) FROM schedules
-		WHERE ($1 = '' OR status = $2)
-	`
+	response := model.NewCursorPaginatedResponse(schedules, page, cursorKey(listing))
 
-	var total int
-	err = r.DB.QueryRow(ctx, countQuery, status, status).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get schedule count: %w", err)
+	// Counting every row is only done when asked for
+	if page.IncludeTotal {
+		var total int
+		if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM schedules WHERE `+where, args...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to get schedule count: %w", err)
+		}
+		response.Total = &total
 	}
 
-	totalPages := (total + limit - 1) / limit
-
-	return &model.PaginatedResponse[model.Schedule]{
-		Data:       schedules,
-		Page:       page,
-		Limit:      limit,
-		Total:      total,
-		TotalPages: totalPages,
-	}, nil
+	return response, nil
 }
 
-// Get today's schedules
+// Get today's schedules, where "today" is the current date in each schedule's own time zone
 func (r *ScheduleRepository) GetTodaySchedules(ctx context.Context) ([]model.Schedule, error) {
-	today := time.Now().Format("2006-01-02")
-
 	query := `
-		SELECT * FROM schedules
-		WHERE created_at::date = $1::date
-		ORDER BY shift_time ASC
+		SELECT ` + scheduleColumns + ` FROM schedules
+		WHERE (scheduled_start AT TIME ZONE time_zone)::date = (NOW() AT TIME ZONE time_zone)::date
+		ORDER BY scheduled_start ASC
 	`
 
-	var schedules []model.Schedule
-	rows, err := r.DB.Query(ctx, query, today)
+	schedules := make([]model.Schedule, 0)
+	rows, err := r.DB.Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get today's schedules: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var schedule model.Schedule
-		if err := rows.Scan(&schedule.ID, &schedule.ClientName, &schedule.ShiftTime, &schedule.Location, &schedule.Status, &schedule.VisitID, &schedule.CreatedAt, &schedule.UpdatedAt); err != nil {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan schedule: %w", err)
 		}
 		schedules = append(schedules, schedule)
@@ -103,216 +107,293 @@ func (r *ScheduleRepository) GetTodaySchedules(ctx context.Context) ([]model.Sch
 	return schedules, nil
 }
 
-// Get schedule by ID
-func (r *ScheduleRepository) GetScheduleByID(ctx context.Context, id uuid.UUID) (*model.Schedule, error) {
-	query := `SELECT * FROM schedules WHERE id = $1`
+// Get upcoming schedules whose shift has not ended and starts within the
+// next number of days, counted in each schedule's own time zone
+func (r *ScheduleRepository) GetUpcomingSchedules(ctx context.Context, days int) ([]model.Schedule, error) {
+	query := `
+		SELECT ` + scheduleColumns + ` FROM schedules
+		WHERE status = 'upcoming'
+		AND scheduled_end > NOW()
+		AND (scheduled_start AT TIME ZONE time_zone)::date <= (NOW() AT TIME ZONE time_zone)::date + $1::int
+		ORDER BY scheduled_start ASC
+	`
 
-	var schedule model.Schedule
-	err := r.DB.QueryRow(ctx, query, id).Scan(&schedule.ID, &schedule.ClientName, &schedule.ShiftTime, &schedule.Location, &schedule.Status, &schedule.VisitID, &schedule.CreatedAt, &schedule.UpdatedAt)
+	schedules := make([]model.Schedule, 0)
+	rows, err := r.DB.Query(ctx, query, days)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("schedule not found")
+		return nil, fmt.Errorf("failed to get upcoming schedules: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
 		}
-		return nil, fmt.Errorf("failed to get schedule: %w", err)
+		schedules = append(schedules, schedule)
 	}
 
-	return &schedule, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate schedules: %w", err)
+	}
+
+	return schedules, nil
 }
 
-// Get schedule with visit and tasks
-func (r *ScheduleRepository) GetScheduleWithDetails(ctx context.Context, id uuid.UUID) (*model.ScheduleWithTasks, error) {
+// Get upcoming schedules that started before the cutoff and have no visit
+func (r *ScheduleRepository) GetOverdueSchedules(ctx context.Context, cutoff time.Time, limit int) ([]model.Schedule, error) {
 	query := `
-		SELECT s.*, v.*, t.*
-		FROM schedules s
-		LEFT JOIN visits v ON s.visit_id = v.id
-		LEFT JOIN tasks t ON s.id = t.schedule_id
-		WHERE s.id = $1
-		ORDER BY t.created_at ASC
+		SELECT ` + scheduleColumns + ` FROM schedules
+		WHERE status = 'upcoming'
+		AND scheduled_start < $1
+		AND NOT EXISTS (SELECT 1 FROM visits v WHERE v.schedule_id = schedules.id)
+		ORDER BY scheduled_start ASC
+		LIMIT $2
 	`
 
-	type result struct {
-		ScheduleID    uuid.UUID
-		ClientName    string
-		ShiftTime     time.Time
-		Location      string
-		Status        string
-		VisitID       *uuid.UUID
-		CreatedAt     time.Time
-		UpdatedAt     time.Time
-		VisitID_      uuid.UUID      `db:"visit_id"`
-		VisitStart    time.Time      `db:"visit_start"`
-		VisitEnd      *time.Time     `db:"visit_end"`
-		VisitStatus   string         `db:"visit_status"`
-		TaskID        uuid.UUID      `db:"task_id"`
-		TaskName      string         `db:"task_name"`
-		TaskDesc      string         `db:"task_description"`
-		TaskStatus    string         `db:"task_status"`
-		TaskReason    *string        `db:"task_reason"`
-		TaskCompleted *time.Time     `db:"task_completed_at"`
-	}
-
-	var results []result
-	rows, err := r.DB.Query(ctx, query, id)
+	schedules := make([]model.Schedule, 0)
+	rows, err := r.DB.Query(ctx, query, cutoff, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get schedule with details: %w", err)
+		return nil, fmt.Errorf("failed to get overdue schedules: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var res result
-		if err := rows.Scan(&res.ScheduleID, &res.ClientName, &res.ShiftTime, &res.Location, &res.Status, &res.VisitID, &res.CreatedAt, &res.UpdatedAt,
-			&res.VisitID_, &res.VisitStart, &res.VisitEnd, &res.VisitStatus,
-			&res.TaskID, &res.TaskName, &res.TaskDesc, &res.TaskStatus, &res.TaskReason, &res.TaskCompleted); err != nil {
-			return nil, fmt.Errorf("failed to scan result: %w", err)
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
 		}
-		results = append(results, res)
+		schedules = append(schedules, schedule)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate results: %w", err)
+		return nil, fmt.Errorf("failed to iterate schedules: %w", err)
 	}
 
-	if len(results) == 0 {
-		return nil, fmt.Errorf("schedule not found")
+	return schedules, nil
+}
+
+// Get schedule by ID
+func (r *ScheduleRepository) GetScheduleByID(ctx context.Context, id uuid.UUID) (*model.Schedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM schedules WHERE id = $1`
+
+	schedule, err := scanSchedule(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Schedule not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
 	}
 
-	// Build the response
-	schedule := model.Schedule{}
-	schedule.ID = results[0].ScheduleID
-	schedule.ClientName = results[0].ClientName
-	schedule.ShiftTime = results[0].ShiftTime.Format("2006-01-02 15:04:05") // Convert time.Time to string
-	schedule.Location = results[0].Location
-	schedule.Status = results[0].Status
-	schedule.VisitID = results[0].VisitID
+	return &schedule, nil
+}
+
+// Get schedule with visit and tasks
+func (r *ScheduleRepository) GetScheduleWithDetails(ctx context.Context, id uuid.UUID) (*model.ScheduleWithTasks, error) {
+	schedule, err := r.GetScheduleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	visitQuery := `
+		SELECT ` + visitColumns + `
+		FROM visits
+		WHERE schedule_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
 
-	tasks := make([]model.Task, 0)
 	var visit *model.Visit
+	v, err := scanVisit(r.DB.QueryRow(ctx, visitQuery, id))
+	switch {
+	case err == nil:
+		visit = &v
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("failed to get schedule visit: %w", err)
+	}
 
-	// Process results to build tasks and visit
-	for _, res := range results {
-		if res.TaskID != uuid.Nil {
-			task := model.Task{}
-			task.ID = res.TaskID
-			task.ScheduleID = res.ScheduleID
-			task.Name = res.TaskName
-			if res.TaskDesc != "" {
-		desc := res.TaskDesc
-		task.Description = &desc
-	}
-			task.Status = res.TaskStatus
-			task.Reason = res.TaskReason
-			task.CompletedAt = res.TaskCompleted
-			tasks = append(tasks, task)
-		}
+	tasksQuery := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE schedule_id = $1
+		ORDER BY sort_order ASC, created_at ASC
+	`
+
+	rows, err := r.DB.Query(ctx, tasksQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule tasks: %w", err)
+	}
+	defer rows.Close()
 
-		if res.VisitID_ != uuid.Nil && visit == nil {
-			visit = &model.Visit{}
-			visit.ID = res.VisitID_
-			visit.ScheduleID = res.ScheduleID
-			visit.StartTime = res.VisitStart
-			visit.EndTime = res.VisitEnd
-			visit.Status = res.VisitStatus
+	tasks := make([]model.Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tasks: %w", err)
 	}
 
 	return &model.ScheduleWithTasks{
-		Schedule: schedule,
+		Schedule: *schedule,
+		Visit:    visit,
 		Tasks:    tasks,
 	}, nil
 }
 
+const insertScheduleQuery = `
+	INSERT INTO schedules (id, client_id, scheduled_start, scheduled_end, time_zone, location, status, caregiver_id, latitude, longitude, geofence_radius_meters,
+		series_id, series_date, is_series_exception, service_code)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+`
+
+func insertScheduleArgs(schedule *model.Schedule) []any {
+	return []any{schedule.ID, schedule.ClientID, schedule.ScheduledStart, schedule.ScheduledEnd, schedule.TimeZone, schedule.Location, schedule.Status,
+		schedule.CaregiverID, schedule.Latitude, schedule.Longitude, schedule.GeofenceRadiusMeters,
+		schedule.SeriesID, schedule.SeriesDate, schedule.IsSeriesException, schedule.ServiceCode}
+}
+
 // Create a new schedule
 func (r *ScheduleRepository) CreateSchedule(ctx context.Context, schedule *model.Schedule) error {
-	query := `
-		INSERT INTO schedules (id, client_name, shift_time, location, status)
-		VALUES ($1, $2, $3, $4, $5)
-	`
+	_, err := execAudited(ctx, r.DB, insertScheduleQuery, insertScheduleArgs(schedule)...)
+	if err != nil {
+		return fmt.Errorf("failed to create schedule: %w", err)
+	}
+
+	return nil
+}
 
-	_, err := r.DB.Exec(ctx, query, schedule.ID, schedule.ClientName, schedule.ShiftTime, schedule.Location, schedule.Status)
+// Create a new schedule together with its initial tasks in one transaction
+func (r *ScheduleRepository) CreateScheduleWithTasks(ctx context.Context, schedule *model.Schedule, tasks []model.Task) error {
+	tx, err := beginAudited(ctx, r.DB)
 	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, insertScheduleQuery, insertScheduleArgs(schedule)...); err != nil {
 		return fmt.Errorf("failed to create schedule: %w", err)
 	}
 
+	for i := range tasks {
+		if _, err := tx.Exec(ctx, insertTaskQuery, insertTaskArgs(&tasks[i])...); err != nil {
+			return fmt.Errorf("failed to create schedule task: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit schedule: %w", err)
+	}
+
 	return nil
 }
 
 // Update schedule
 func (r *ScheduleRepository) UpdateSchedule(ctx context.Context, schedule *model.Schedule) error {
+	// The update only applies to the version that was read; UpdatedAt is set to the new version
 	query := `
 		UPDATE schedules
-		SET client_name = $1, shift_time = $2, location = $3, status = $4, visit_id = $5
-		WHERE id = $6
+		SET client_id = $1, scheduled_start = $2, scheduled_end = $3, time_zone = $4, location = $5, status = $6, visit_id = $7,
+			latitude = $8, longitude = $9, geofence_radius_meters = $10, is_series_exception = $11, service_code = $12
+		WHERE id = $13 AND updated_at = $14
+		RETURNING updated_at
 	`
 
-	_, err := r.DB.Exec(ctx, query, schedule.ClientName, schedule.ShiftTime, schedule.Location, schedule.Status, schedule.VisitID, schedule.ID)
+	scan := func(row pgx.Row) error {
+		return row.Scan(&schedule.UpdatedAt)
+	}
+	err := queryRowAudited(ctx, r.DB, scan, query, schedule.ClientID, schedule.ScheduledStart, schedule.ScheduledEnd, schedule.TimeZone, schedule.Location, schedule.Status, schedule.VisitID,
+		schedule.Latitude, schedule.Longitude, schedule.GeofenceRadiusMeters, schedule.IsSeriesException, schedule.ServiceCode, schedule.ID, guardVersion(ctx, schedule.UpdatedAt))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return versionChanged(ctx, scheduleChangedError())
+		}
 		return fmt.Errorf("failed to update schedule: %w", err)
 	}
 
 	return nil
 }
 
-// Update schedule status
-func (r *ScheduleRepository) UpdateScheduleStatus(ctx context.Context, id uuid.UUID, status string) error {
-	query := `UPDATE schedules SET status = $1 WHERE id = $2`
+func scheduleChangedError() error {
+	code := "SCHEDULE_CHANGED"
+	return errs.NewConflictError("Schedule changed while it was being updated", true, &code)
+}
+
+// Update schedule status, only if it is still in the expected status
+func (r *ScheduleRepository) UpdateScheduleStatus(ctx context.Context, id uuid.UUID, from, to string) error {
+	query := `UPDATE schedules SET status = $1 WHERE id = $2 AND status = $3`
 
-	_, err := r.DB.Exec(ctx, query, status, id)
+	result, err := execAudited(ctx, r.DB, query, to, id, from)
 	if err != nil {
 		return fmt.Errorf("failed to update schedule status: %w", err)
 	}
 
+	if result.RowsAffected() == 0 {
+		return model.ScheduleStatusMachine.ConflictError(from, to)
+	}
+
 	return nil
 }
 
-// Get schedule statistics
-func (r *ScheduleRepository) GetScheduleStats(ctx context.Context) (*model.TaskStats, error) {
-	query := `
-		SELECT
-			COUNT(*) as total,
-			COUNT(CASE WHEN status = 'completed' THEN 1 END) as completed,
-			COUNT(CASE WHEN status = 'pending' THEN 1 END) as pending,
-			COUNT(CASE WHEN status = 'not_completed' THEN 1 END) as not_completed
-		FROM schedules
-	`
+// Assign a caregiver to a schedule that has none yet
+func (r *ScheduleRepository) AssignCaregiver(ctx context.Context, scheduleID, caregiverID uuid.UUID) error {
+	query := `UPDATE schedules SET caregiver_id = $1 WHERE id = $2 AND caregiver_id IS NULL`
 
-	var total, completed, pending, notCompleted int
-	err := r.DB.QueryRow(ctx, query).Scan(&total, &completed, &pending, &notCompleted)
+	result, err := execAudited(ctx, r.DB, query, caregiverID, scheduleID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get schedule stats: %w", err)
+		return fmt.Errorf("failed to assign caregiver: %w", err)
 	}
 
-	stats := model.TaskStats{
-		TotalTasks:      total,
-		CompletedTasks:  completed,
-		PendingTasks:    pending,
-		NotCompletedTasks: notCompleted,
+	if result.RowsAffected() == 0 {
+		code := "SCHEDULE_ALREADY_ASSIGNED"
+		return errs.NewConflictError("Schedule already has a caregiver assigned", true, &code)
 	}
 
-	return &stats, nil
+	return nil
 }
 
-// Search schedules by client name or location
-func (r *ScheduleRepository) SearchSchedules(ctx context.Context, queryStr string, page, limit int) (*model.PaginatedResponse[model.Schedule], error) {
+// Replace the caregiver on a schedule, only if it is still the version that
+// was read; a nil caregiver unassigns it
+func (r *ScheduleRepository) ReassignCaregiver(ctx context.Context, scheduleID uuid.UUID, caregiverID *uuid.UUID, version time.Time) error {
+	query := `UPDATE schedules SET caregiver_id = $1 WHERE id = $2 AND updated_at = $3`
+
+	result, err := execAudited(ctx, r.DB, query, caregiverID, scheduleID, guardVersion(ctx, version))
+	if err != nil {
+		return fmt.Errorf("failed to reassign caregiver: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return versionChanged(ctx, scheduleChangedError())
+	}
+
+	return nil
+}
+
+// Get schedules assigned to a caregiver with pagination and filtering
+func (r *ScheduleRepository) GetSchedulesByCaregiver(ctx context.Context, caregiverID uuid.UUID, page, limit int, status string) (*model.PaginatedResponse[model.Schedule], error) {
 	query := `
-		SELECT * FROM schedules
-		WHERE LOWER(client_name) LIKE LOWER($1) OR LOWER(location) LIKE LOWER($1)
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		SELECT ` + scheduleColumns + ` FROM schedules
+		WHERE caregiver_id = $1
+		AND ($2 = '' OR status = $2)
+		ORDER BY scheduled_start ASC
+		LIMIT $3 OFFSET $4
 	`
 
-	searchPattern := "%" + queryStr + "%"
-
-	var schedules []model.Schedule
+	schedules := make([]model.Schedule, 0)
 	offset := (page - 1) * limit
-	rows, err := r.DB.Query(ctx, query, searchPattern, limit, offset)
+	rows, err := r.DB.Query(ctx, query, caregiverID, status, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search schedules: %w", err)
+		return nil, fmt.Errorf("failed to get caregiver schedules: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var schedule model.Schedule
-		if err := rows.Scan(&schedule.ID, &schedule.ClientName, &schedule.ShiftTime, &schedule.Location, &schedule.Status, &schedule.VisitID, &schedule.CreatedAt, &schedule.UpdatedAt); err != nil {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan schedule: %w", err)
 		}
 		schedules = append(schedules, schedule)
@@ -322,16 +403,16 @@ func (r *ScheduleRepository) SearchSchedules(ctx context.Context, queryStr strin
 		return nil, fmt.Errorf("failed to iterate schedules: %w", err)
 	}
 
-	// Get total count
 	countQuery := `
 		SELECT COUNT(*) FROM schedules
-		WHERE LOWER(client_name) LIKE LOWER($1) OR LOWER(location) LIKE LOWER($1)
+		WHERE caregiver_id = $1
+		AND ($2 = '' OR status = $2)
 	`
 
 	var total int
-	err = r.DB.QueryRow(ctx, countQuery, searchPattern).Scan(&total)
+	err = r.DB.QueryRow(ctx, countQuery, caregiverID, status).Scan(&total)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get search count: %w", err)
+		return nil, fmt.Errorf("failed to get caregiver schedule count: %w", err)
 	}
 
 	totalPages := (total + limit - 1) / limit
@@ -343,4 +424,112 @@ func (r *ScheduleRepository) SearchSchedules(ctx context.Context, queryStr strin
 		Total:      total,
 		TotalPages: totalPages,
 	}, nil
-}
\ No newline at end of file
+}
+
+// Get schedule counts by status
+func (r *ScheduleRepository) GetScheduleStats(ctx context.Context) (*model.ScheduleStats, error) {
+	query := `
+		SELECT
+			COUNT(*) as total,
+			COUNT(*) FILTER (WHERE status = $1) as upcoming,
+			COUNT(*) FILTER (WHERE status = $2) as in_progress,
+			COUNT(*) FILTER (WHERE status = $3) as completed,
+			COUNT(*) FILTER (WHERE status = $4) as missed,
+			COUNT(*) FILTER (WHERE status = $5) as cancelled
+		FROM schedules
+	`
+
+	var stats model.ScheduleStats
+	err := r.DB.QueryRow(ctx, query, model.ScheduleStatusUpcoming, model.ScheduleStatusInProgress, model.ScheduleStatusCompleted,
+		model.ScheduleStatusMissed, model.ScheduleStatusCancelled).
+		Scan(&stats.Total, &stats.Upcoming, &stats.InProgress, &stats.Completed, &stats.Missed, &stats.Cancelled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// scheduleSearchQuery turns $1 into the full-text query of a schedule search
+const scheduleSearchQuery = `
+	WITH search AS (
+		-- Every word of the search as a prefix, e.g. "jo smi" becomes 'jo':* & 'smi':*
+		SELECT to_tsquery('simple', array_to_string(ARRAY(
+			SELECT quote_literal(lexeme) || ':*' FROM unnest(to_tsvector('simple', $1))
+		), ' & ')) AS query
+	)
+`
+
+// scheduleSearchMatches selects the schedules matching the search in $1 with
+// the status in $2, starting in [$3, $4)
+const scheduleSearchMatches = `
+	FROM schedule_search ss
+	JOIN schedules s ON s.id = ss.schedule_id
+	CROSS JOIN search
+	WHERE (ss.document @@ search.query OR ss.client_name % $1 OR $1 <% ss.location OR $1 <% ss.task_names)
+	AND ($2 = '' OR s.status = $2)
+	AND ($3::timestamptz IS NULL OR s.scheduled_start >= $3)
+	AND ($4::timestamptz IS NULL OR s.scheduled_start < $4)
+`
+
+// Search schedules by client name, location and task names. Words match as
+// prefixes, and misspelled names still match by trigram similarity. Results
+// are ranked by relevance, with the matching words of the schedule's
+// searchable text highlighted in a snippet.
+func (r *ScheduleRepository) SearchSchedules(ctx context.Context, filter model.ScheduleSearchFilter, page, limit int) (*model.PaginatedResponse[model.ScheduleSearchResult], error) {
+	query := scheduleSearchQuery + `
+		SELECT ` + scheduleColumns + `, matches.rank, matches.snippet
+		FROM schedules
+		JOIN (
+			SELECT
+				ss.schedule_id,
+				s.scheduled_start,
+				ts_rank_cd(ss.document, search.query)
+					+ GREATEST(similarity(ss.client_name, $1), word_similarity($1, ss.location), word_similarity($1, ss.task_names)) AS rank,
+				ts_headline('simple', concat_ws(' · ', NULLIF(ss.client_name, ''), NULLIF(ss.location, ''), NULLIF(ss.task_names, '')), search.query,
+					'StartSel=<mark>, StopSel=</mark>, MinWords=5, MaxWords=20') AS snippet
+			` + scheduleSearchMatches + `
+			ORDER BY rank DESC, s.scheduled_start ASC, ss.schedule_id
+			LIMIT $5 OFFSET $6
+		) matches ON matches.schedule_id = schedules.id
+		ORDER BY matches.rank DESC, matches.scheduled_start ASC, schedules.id
+	`
+
+	results := make([]model.ScheduleSearchResult, 0)
+	offset := (page - 1) * limit
+	rows, err := r.DB.Query(ctx, query, filter.Query, filter.Status, filter.From, filter.To, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search schedules: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result model.ScheduleSearchResult
+		result.Schedule, err = scanSchedule(rows, &result.Rank, &result.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate schedules: %w", err)
+	}
+
+	countQuery := scheduleSearchQuery + `SELECT COUNT(*) ` + scheduleSearchMatches
+
+	var total int
+	if err := r.DB.QueryRow(ctx, countQuery, filter.Query, filter.Status, filter.From, filter.To).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to get schedule search count: %w", err)
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	return &model.PaginatedResponse[model.ScheduleSearchResult]{
+		Data:       results,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+const scheduleSeriesColumns = `id, client_id, caregiver_id, rrule, start_date, to_char(start_time, 'HH24:MI') AS start_time, duration_minutes, time_zone,
+	end_date, occurrence_count, exception_dates, location, service_code, latitude, longitude, geofence_radius_meters, materialized_through, created_at, updated_at`
+
+type ScheduleSeriesRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewScheduleSeriesRepository(db *pgxpool.Pool) *ScheduleSeriesRepository {
+	return &ScheduleSeriesRepository{DB: db}
+}
+
+func scanScheduleSeries(row pgx.Row) (model.ScheduleSeries, error) {
+	var series model.ScheduleSeries
+	err := row.Scan(&series.ID, &series.ClientID, &series.CaregiverID, &series.RRule, &series.StartDate, &series.StartTime, &series.DurationMinutes, &series.TimeZone,
+		&series.EndDate, &series.Count, &series.ExceptionDates, &series.Location, &series.ServiceCode, &series.Latitude, &series.Longitude, &series.GeofenceRadiusMeters,
+		&series.MaterializedThrough, &series.CreatedAt, &series.UpdatedAt)
+	return series, err
+}
+
+const insertScheduleSeriesQuery = `
+	INSERT INTO schedule_series (id, client_id, caregiver_id, rrule, start_date, start_time, duration_minutes, time_zone,
+		end_date, occurrence_count, exception_dates, location, latitude, longitude, geofence_radius_meters, materialized_through, service_code)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+`
+
+func insertScheduleSeriesArgs(series *model.ScheduleSeries) []any {
+	return []any{series.ID, series.ClientID, series.CaregiverID, series.RRule, series.StartDate, series.StartTime, series.DurationMinutes, series.TimeZone,
+		series.EndDate, series.Count, series.ExceptionDates, series.Location, series.Latitude, series.Longitude, series.GeofenceRadiusMeters,
+		series.MaterializedThrough, series.ServiceCode}
+}
+
+// Get schedule series by ID
+func (r *ScheduleSeriesRepository) GetScheduleSeriesByID(ctx context.Context, id uuid.UUID) (*model.ScheduleSeries, error) {
+	query := `SELECT ` + scheduleSeriesColumns + ` FROM schedule_series WHERE id = $1`
+
+	series, err := scanScheduleSeries(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Schedule series not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to get schedule series: %w", err)
+	}
+
+	return &series, nil
+}
+
+// Get series whose occurrences have not been created up to horizonDays from today
+func (r *ScheduleSeriesRepository) GetScheduleSeriesToMaterialize(ctx context.Context, horizonDays int) ([]model.ScheduleSeries, error) {
+	query := `
+		SELECT ` + scheduleSeriesColumns + ` FROM schedule_series s
+		WHERE (materialized_through IS NULL OR materialized_through < (NOW() AT TIME ZONE time_zone)::date + $1::int)
+		AND (end_date IS NULL OR materialized_through IS NULL OR end_date > materialized_through)
+		AND EXISTS (SELECT 1 FROM clients c WHERE c.id = s.client_id AND c.status = 'active')
+		ORDER BY created_at ASC
+	`
+
+	seriesList := make([]model.ScheduleSeries, 0)
+	rows, err := r.DB.Query(ctx, query, horizonDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule series: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		series, err := scanScheduleSeries(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule series: %w", err)
+		}
+		seriesList = append(seriesList, series)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate schedule series: %w", err)
+	}
+
+	return seriesList, nil
+}
+
+// Get the schedules generated from a series in occurrence order
+func (r *ScheduleSeriesRepository) GetScheduleSeriesSchedules(ctx context.Context, seriesID uuid.UUID) ([]model.Schedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM schedules WHERE series_id = $1 ORDER BY series_date ASC`
+
+	schedules := make([]model.Schedule, 0)
+	rows, err := r.DB.Query(ctx, query, seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series schedules: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// Create a new schedule series together with the schedules and tasks of its
+// first occurrences. Returns the number of schedules created.
+func (r *ScheduleSeriesRepository) CreateScheduleSeries(ctx context.Context, series *model.ScheduleSeries, occurrences []model.ScheduleWithTasks, through time.Time) (int, error) {
+	tx, err := beginAudited(ctx, r.DB)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, insertScheduleSeriesQuery, insertScheduleSeriesArgs(series)...); err != nil {
+		return 0, fmt.Errorf("failed to create schedule series: %w", err)
+	}
+
+	created, err := materializeOccurrences(ctx, tx, series.ID, occurrences, through)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit schedule series: %w", err)
+	}
+
+	return created, nil
+}
+
+// Create the schedules and tasks for a series' occurrences and advance its
+// materialized_through date. Occurrences that already exist are skipped.
+// Returns the number of schedules created.
+func (r *ScheduleSeriesRepository) MaterializeOccurrences(ctx context.Context, seriesID uuid.UUID, occurrences []model.ScheduleWithTasks, through time.Time) (int, error) {
+	tx, err := beginAudited(ctx, r.DB)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	created, err := materializeOccurrences(ctx, tx, seriesID, occurrences, through)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit series schedules: %w", err)
+	}
+
+	return created, nil
+}
+
+func materializeOccurrences(ctx context.Context, tx pgx.Tx, seriesID uuid.UUID, occurrences []model.ScheduleWithTasks, through time.Time) (int, error) {
+	created := 0
+	for i := range occurrences {
+		result, err := tx.Exec(ctx, insertScheduleQuery+` ON CONFLICT (series_id, series_date) DO NOTHING`, insertScheduleArgs(&occurrences[i].Schedule)...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create series schedule: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			continue
+		}
+		created++
+
+		for j := range occurrences[i].Tasks {
+			if _, err := tx.Exec(ctx, insertTaskQuery, insertTaskArgs(&occurrences[i].Tasks[j])...); err != nil {
+				return 0, fmt.Errorf("failed to create schedule task: %w", err)
+			}
+		}
+	}
+
+	query := `
+		UPDATE schedule_series SET materialized_through = GREATEST(COALESCE(materialized_through, $1), $1)
+		WHERE id = $2
+	`
+	if _, err := tx.Exec(ctx, query, through, seriesID); err != nil {
+		return 0, fmt.Errorf("failed to update schedule series: %w", err)
+	}
+
+	return created, nil
+}
+
+// Split a series at one of its schedules: end the current series, create the
+// series that replaces it from the schedule's date on and move the upcoming
+// schedules from that date onto it, recalculating their shift window.
+// Occurrences edited on their own are left on the current series. The split
+// only applies while the schedule is still the version that was read. Returns
+// the number of schedules moved.
+func (r *ScheduleSeriesRepository) SplitScheduleSeries(ctx context.Context, current, next *model.ScheduleSeries, at *model.Schedule) (int64, error) {
+	tx, err := beginAudited(ctx, r.DB)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	err = tx.QueryRow(ctx, `SELECT TRUE FROM schedules WHERE id = $1 AND updated_at = $2 FOR UPDATE`, at.ID, guardVersion(ctx, at.UpdatedAt)).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, versionChanged(ctx, scheduleChangedError())
+		}
+		return 0, fmt.Errorf("failed to lock schedule: %w", err)
+	}
+
+	query := `UPDATE schedule_series SET end_date = $1, occurrence_count = $2, exception_dates = $3 WHERE id = $4`
+	if _, err := tx.Exec(ctx, query, current.EndDate, current.Count, current.ExceptionDates, current.ID); err != nil {
+		return 0, fmt.Errorf("failed to end schedule series: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, insertScheduleSeriesQuery, insertScheduleSeriesArgs(next)...); err != nil {
+		return 0, fmt.Errorf("failed to create schedule series: %w", err)
+	}
+
+	query = `
+		UPDATE schedules SET
+			series_id = $1, client_id = $2, location = $3, time_zone = $4,
+			latitude = $5, longitude = $6, geofence_radius_meters = $7,
+			scheduled_start = (series_date + $8::time) AT TIME ZONE $4,
+			scheduled_end = ((series_date + $8::time) AT TIME ZONE $4) + make_interval(mins => $9),
+			service_code = $12
+		WHERE series_id = $10 AND series_date >= $11 AND status = 'upcoming' AND NOT is_series_exception
+	`
+	result, err := tx.Exec(ctx, query, next.ID, next.ClientID, next.Location, next.TimeZone,
+		next.Latitude, next.Longitude, next.GeofenceRadiusMeters, next.StartTime, next.DurationMinutes, current.ID, *at.SeriesDate, next.ServiceCode)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move series schedules: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit schedule series: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
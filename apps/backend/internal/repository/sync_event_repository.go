@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+const syncEventColumns = `user_id, client_event_id, type, device_time, received_at, schedule_id, task_id, latitude, longitude,
+	task_status, reason, status, flags, clock_skew_seconds, error_code, error, visit_id, created_at, updated_at`
+
+type SyncEventRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewSyncEventRepository(db *pgxpool.Pool) *SyncEventRepository {
+	return &SyncEventRepository{DB: db}
+}
+
+func scanSyncEvent(row pgx.Row) (model.SyncEvent, error) {
+	var event model.SyncEvent
+	err := row.Scan(&event.UserID, &event.ClientEventID, &event.Type, &event.DeviceTime, &event.ReceivedAt, &event.ScheduleID, &event.TaskID,
+		&event.Latitude, &event.Longitude, &event.TaskStatus, &event.Reason, &event.Status, &event.Flags, &event.ClockSkewSeconds,
+		&event.ErrorCode, &event.Error, &event.VisitID, &event.CreatedAt, &event.UpdatedAt)
+	return event, err
+}
+
+// Get a user's synced event by its client event ID
+func (r *SyncEventRepository) GetSyncEvent(ctx context.Context, userID string, clientEventID uuid.UUID) (*model.SyncEvent, error) {
+	query := `SELECT ` + syncEventColumns + ` FROM sync_events WHERE user_id = $1 AND client_event_id = $2`
+
+	event, err := scanSyncEvent(r.DB.QueryRow(ctx, query, userID, clientEventID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Sync event not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to get sync event: %w", err)
+	}
+
+	return &event, nil
+}
+
+// Claim an event for applying by recording it as pending. Returns false when
+// the user has synced an event with the same client event ID before.
+func (r *SyncEventRepository) ClaimSyncEvent(ctx context.Context, event *model.SyncEvent) (bool, error) {
+	query := `
+		INSERT INTO sync_events (user_id, client_event_id, type, device_time, received_at, schedule_id, task_id, latitude, longitude,
+			task_status, reason, status, flags, clock_skew_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (user_id, client_event_id) DO NOTHING
+	`
+
+	result, err := r.DB.Exec(ctx, query, event.UserID, event.ClientEventID, event.Type, event.DeviceTime, event.ReceivedAt, event.ScheduleID, event.TaskID,
+		event.Latitude, event.Longitude, event.TaskStatus, event.Reason, event.Status, event.Flags, event.ClockSkewSeconds)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim sync event: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// Record the outcome of applying a claimed event
+func (r *SyncEventRepository) CompleteSyncEvent(ctx context.Context, event *model.SyncEvent) error {
+	query := `
+		UPDATE sync_events SET status = $1, error_code = $2, error = $3, visit_id = $4, task_id = $5
+		WHERE user_id = $6 AND client_event_id = $7
+	`
+
+	_, err := r.DB.Exec(ctx, query, event.Status, event.ErrorCode, event.Error, event.VisitID, event.TaskID, event.UserID, event.ClientEventID)
+	if err != nil {
+		return fmt.Errorf("failed to complete sync event: %w", err)
+	}
+
+	return nil
+}
+
+// Release a claimed event that could not be applied so that it is applied
+// when the device resends it
+func (r *SyncEventRepository) ReleaseSyncEvent(ctx context.Context, userID string, clientEventID uuid.UUID) error {
+	query := `DELETE FROM sync_events WHERE user_id = $1 AND client_event_id = $2 AND status = 'pending'`
+
+	if _, err := r.DB.Exec(ctx, query, userID, clientEventID); err != nil {
+		return fmt.Errorf("failed to release sync event: %w", err)
+	}
+
+	return nil
+}
+
+// Get synced events, most recently received first
+func (r *SyncEventRepository) GetSyncEvents(ctx context.Context, filter model.SyncEventFilter, page, limit int) (*model.PaginatedResponse[model.SyncEvent], error) {
+	where := `
+		WHERE ($1 = '' OR user_id = $1)
+		AND ($2 = '' OR status = $2)
+		AND ($3 = '' OR $3 = ANY(flags))
+	`
+	args := []any{filter.UserID, filter.Status, filter.Flag}
+
+	query := `SELECT ` + syncEventColumns + ` FROM sync_events ` + where + ` ORDER BY received_at DESC, device_time DESC LIMIT $4 OFFSET $5`
+
+	events := make([]model.SyncEvent, 0)
+	offset := (page - 1) * limit
+	rows, err := r.DB.Query(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanSyncEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sync event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sync events: %w", err)
+	}
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM sync_events `+where, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to get sync event count: %w", err)
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	return &model.PaginatedResponse[model.SyncEvent]{
+		Data:       events,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
@@ -2,31 +2,52 @@ package repository
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/database"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/listquery"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
 )
 
+const taskColumns = `id, schedule_id, name, description, status, reason, completed_at, is_required, sort_order, care_plan_task_id, created_at, updated_at`
+
+// insertTaskQuery is shared by every path that creates tasks, including schedule creation
+const insertTaskQuery = `
+	INSERT INTO tasks (id, schedule_id, name, description, status, is_required, sort_order, care_plan_task_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
 type TaskRepository struct {
-	DB *pgxpool.Pool
+	DB database.DBTX
 }
 
-func NewTaskRepository(db *pgxpool.Pool) *TaskRepository {
+func NewTaskRepository(db database.DBTX) *TaskRepository {
 	return &TaskRepository{DB: db}
 }
 
+func scanTask(row pgx.Row) (model.Task, error) {
+	var task model.Task
+	err := row.Scan(&task.ID, &task.ScheduleID, &task.Name, &task.Description, &task.Status, &task.Reason, &task.CompletedAt,
+		&task.IsRequired, &task.SortOrder, &task.CarePlanTaskID, &task.CreatedAt, &task.UpdatedAt)
+	return task, err
+}
+
+func insertTaskArgs(task *model.Task) []any {
+	return []any{task.ID, task.ScheduleID, task.Name, task.Description, task.Status, task.IsRequired, task.SortOrder, task.CarePlanTaskID}
+}
+
 // Get task by ID
 func (r *TaskRepository) GetTaskByID(ctx context.Context, id uuid.UUID) (*model.Task, error) {
-	query := `SELECT * FROM tasks WHERE id = $1`
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1`
 
-	var task model.Task
-	err := r.DB.QueryRow(ctx, query, id).Scan(&task.ID, &task.ScheduleID, &task.Name, &task.Description, &task.Status, &task.Reason, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt)
+	task, err := scanTask(r.DB.QueryRow(ctx, query, id))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("task not found")
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Task not found", false, nil)
 		}
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
@@ -36,7 +57,7 @@ func (r *TaskRepository) GetTaskByID(ctx context.Context, id uuid.UUID) (*model.
 
 // Get tasks by schedule ID
 func (r *TaskRepository) GetTasksByScheduleID(ctx context.Context, scheduleID uuid.UUID) ([]model.Task, error) {
-	query := `SELECT * FROM tasks WHERE schedule_id = $1 ORDER BY created_at ASC`
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE schedule_id = $1 ORDER BY sort_order ASC, created_at ASC`
 
 	var tasks []model.Task
 	rows, err := r.DB.Query(ctx, query, scheduleID)
@@ -46,8 +67,8 @@ func (r *TaskRepository) GetTasksByScheduleID(ctx context.Context, scheduleID uu
 	defer rows.Close()
 
 	for rows.Next() {
-		var task model.Task
-		if err := rows.Scan(&task.ID, &task.ScheduleID, &task.Name, &task.Description, &task.Status, &task.Reason, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt); err != nil {
+		task, err := scanTask(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
 		tasks = append(tasks, task)
@@ -60,14 +81,18 @@ func (r *TaskRepository) GetTasksByScheduleID(ctx context.Context, scheduleID uu
 	return tasks, nil
 }
 
-// Create a new task
+// Create a new task, placed after the schedule's existing tasks
 func (r *TaskRepository) CreateTask(ctx context.Context, task *model.Task) error {
 	query := `
-		INSERT INTO tasks (id, schedule_id, name, description, status)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO tasks (id, schedule_id, name, description, status, is_required, sort_order, care_plan_task_id)
+		VALUES ($1, $2, $3, $4, $5, $6, (SELECT COALESCE(MAX(sort_order), 0) + 1 FROM tasks WHERE schedule_id = $2), $7)
+		RETURNING sort_order
 	`
 
-	_, err := r.DB.Exec(ctx, query, task.ID, task.ScheduleID, task.Name, task.Description, task.Status)
+	scan := func(row pgx.Row) error {
+		return row.Scan(&task.SortOrder)
+	}
+	err := queryRowAudited(ctx, r.DB, scan, query, task.ID, task.ScheduleID, task.Name, task.Description, task.Status, task.IsRequired, task.CarePlanTaskID)
 	if err != nil {
 		return fmt.Errorf("failed to create task: %w", err)
 	}
@@ -83,7 +108,7 @@ func (r *TaskRepository) UpdateTask(ctx context.Context, task *model.Task) error
 		WHERE id = $6
 	`
 
-	_, err := r.DB.Exec(ctx, query, task.Name, task.Description, task.Status, task.Reason, task.CompletedAt, task.ID)
+	_, err := execAudited(ctx, r.DB, query, task.Name, task.Description, task.Status, task.Reason, task.CompletedAt, task.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
@@ -91,28 +116,53 @@ func (r *TaskRepository) UpdateTask(ctx context.Context, task *model.Task) error
 	return nil
 }
 
-// Update task status
-func (r *TaskRepository) UpdateTaskStatus(ctx context.Context, taskID uuid.UUID, status string, reason *string) (*model.Task, error) {
+// Update the status of a task, only if it is still the version that was read
+func (r *TaskRepository) UpdateTaskStatus(ctx context.Context, task *model.Task, to string, reason *string) (*model.Task, error) {
 	query := `
 		UPDATE tasks
 		SET status = $1, reason = $2, completed_at = CASE
 			WHEN $1 = 'completed' THEN NOW()
 			ELSE NULL
 		END
-		WHERE id = $3
-		RETURNING *
+		WHERE id = $3 AND updated_at = $4
+		RETURNING ` + taskColumns + `
 	`
 
-	var task model.Task
-	err := r.DB.QueryRow(ctx, query, status, reason, taskID).Scan(&task.ID, &task.ScheduleID, &task.Name, &task.Description, &task.Status, &task.Reason, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt)
+	var updated model.Task
+	scan := func(row pgx.Row) (err error) {
+		updated, err = scanTask(row)
+		return err
+	}
+	err := queryRowAudited(ctx, r.DB, scan, query, to, reason, task.ID, guardVersion(ctx, task.UpdatedAt))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("task not found")
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := "TASK_CHANGED"
+			return nil, versionChanged(ctx, errs.NewConflictError("Task changed while it was being updated", true, &code))
 		}
 		return nil, fmt.Errorf("failed to update task status: %w", err)
 	}
 
-	return &task, nil
+	return &updated, nil
+}
+
+// Get task statistics across all schedules
+func (r *TaskRepository) GetTaskStats(ctx context.Context) (*model.TaskStats, error) {
+	query := `
+		SELECT
+			COUNT(*) as total,
+			COUNT(CASE WHEN status = 'completed' THEN 1 END) as completed,
+			COUNT(CASE WHEN status = 'pending' THEN 1 END) as pending,
+			COUNT(CASE WHEN status = 'not_completed' THEN 1 END) as not_completed
+		FROM tasks
+	`
+
+	var stats model.TaskStats
+	err := r.DB.QueryRow(ctx, query).Scan(&stats.TotalTasks, &stats.CompletedTasks, &stats.PendingTasks, &stats.NotCompletedTasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overall task stats: %w", err)
+	}
+
+	return &stats, nil
 }
 
 // Get task statistics for a schedule
@@ -167,18 +217,23 @@ func (r *TaskRepository) CreateBatchTasks(ctx context.Context, tasks []model.Tas
 		return nil
 	}
 
-	query := `
-		INSERT INTO tasks (id, schedule_id, name, description, status)
-		VALUES ($1, $2, $3, $4, $5)
-	`
+	tx, err := beginAudited(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
 
-	for _, task := range tasks {
-		_, err := r.DB.Exec(ctx, query, task.ID, task.ScheduleID, task.Name, task.Description, task.Status)
+	for i := range tasks {
+		_, err := tx.Exec(ctx, insertTaskQuery, insertTaskArgs(&tasks[i])...)
 		if err != nil {
 			return fmt.Errorf("failed to create batch tasks: %w", err)
 		}
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit batch tasks: %w", err)
+	}
+
 	return nil
 }
 
@@ -186,7 +241,7 @@ func (r *TaskRepository) CreateBatchTasks(ctx context.Context, tasks []model.Tas
 func (r *TaskRepository) DeleteTask(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM tasks WHERE id = $1`
 
-	_, err := r.DB.Exec(ctx, query, id)
+	_, err := execAudited(ctx, r.DB, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
@@ -209,7 +264,7 @@ func (r *TaskRepository) TaskExists(ctx context.Context, id uuid.UUID) (bool, er
 
 // Get tasks by status
 func (r *TaskRepository) GetTasksByStatus(ctx context.Context, status string) ([]model.Task, error) {
-	query := `SELECT * FROM tasks WHERE status = $1 ORDER BY created_at DESC`
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE status = $1 ORDER BY created_at DESC`
 
 	var tasks []model.Task
 	rows, err := r.DB.Query(ctx, query, status)
@@ -219,8 +274,8 @@ func (r *TaskRepository) GetTasksByStatus(ctx context.Context, status string) ([
 	defer rows.Close()
 
 	for rows.Next() {
-		var task model.Task
-		if err := rows.Scan(&task.ID, &task.ScheduleID, &task.Name, &task.Description, &task.Status, &task.Reason, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt); err != nil {
+		task, err := scanTask(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
 		tasks = append(tasks, task)
@@ -233,9 +288,47 @@ func (r *TaskRepository) GetTasksByStatus(ctx context.Context, status string) ([
 	return tasks, nil
 }
 
+// Get a page of tasks matching listing's filters, in its sort
+func (r *TaskRepository) GetTasks(ctx context.Context, page model.CursorPage, listing *listquery.Query[model.Task]) (*model.CursorPaginatedResponse[model.Task], error) {
+	where, args := listing.Where(1)
+	keyset, order, keysetArgs := keysetClause(listing, page, len(args)+1)
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE ` + where + ` AND ` + keyset + ` ` + order
+
+	tasks := make([]model.Task, 0)
+	rows, err := r.DB.Query(ctx, query, append(args, keysetArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tasks: %w", err)
+	}
+
+	response := model.NewCursorPaginatedResponse(tasks, page, cursorKey(listing))
+
+	if page.IncludeTotal {
+		var total int
+		if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM tasks WHERE `+where, args...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to get task count: %w", err)
+		}
+		response.Total = &total
+	}
+
+	return response, nil
+}
+
 // Get incomplete tasks with reasons
 func (r *TaskRepository) GetIncompleteTasks(ctx context.Context) ([]model.Task, error) {
-	query := `SELECT * FROM tasks WHERE status = 'not_completed' AND reason IS NOT NULL ORDER BY created_at DESC`
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE status = 'not_completed' AND reason IS NOT NULL ORDER BY created_at DESC`
 
 	var tasks []model.Task
 	rows, err := r.DB.Query(ctx, query)
@@ -245,8 +338,8 @@ func (r *TaskRepository) GetIncompleteTasks(ctx context.Context) ([]model.Task,
 	defer rows.Close()
 
 	for rows.Next() {
-		var task model.Task
-		if err := rows.Scan(&task.ID, &task.ScheduleID, &task.Name, &task.Description, &task.Status, &task.Reason, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt); err != nil {
+		task, err := scanTask(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
 		tasks = append(tasks, task)
@@ -263,7 +356,7 @@ func (r *TaskRepository) GetIncompleteTasks(ctx context.Context) ([]model.Task,
 func (r *TaskRepository) UpdateTaskReason(ctx context.Context, taskID uuid.UUID, reason string) error {
 	query := `UPDATE tasks SET reason = $1 WHERE id = $2`
 
-	_, err := r.DB.Exec(ctx, query, reason, taskID)
+	_, err := execAudited(ctx, r.DB, query, reason, taskID)
 	if err != nil {
 		return fmt.Errorf("failed to update task reason: %w", err)
 	}
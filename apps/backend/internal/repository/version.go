@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/etag"
+)
+
+// versionChanged is the error of a write guarded by the updated_at it read the
+// row at, when the row changed in between. With an If-Match header the
+// request's precondition no longer holds; without one the write conflicts.
+func versionChanged(ctx context.Context, conflict error) error {
+	if etag.IfMatchFromContext(ctx) != "" {
+		return etag.PreconditionFailedError()
+	}
+	return conflict
+}
+
+// guardVersion returns the updated_at a guarded write applies to: the version
+// the request's If-Match header was checked against, or else the version the
+// caller read the row at
+func guardVersion(ctx context.Context, read time.Time) time.Time {
+	if version, ok := etag.VersionFromContext(ctx); ok {
+		return version
+	}
+	return read
+}
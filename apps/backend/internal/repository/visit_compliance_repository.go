@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/database"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+// schedule_id and service_date are read from the visit for listing
+const visitComplianceColumns = `c.visit_id, c.is_compliant, c.missing_elements, c.checked_at, v.schedule_id, v.start_time AS service_date`
+
+// The service date is the day the visit started in the schedule's time zone
+const visitServiceDateFilter = `(v.start_time AT TIME ZONE s.time_zone)::date BETWEEN $1 AND $2`
+
+type VisitComplianceRepository struct {
+	DB database.DBTX
+}
+
+func NewVisitComplianceRepository(db database.DBTX) *VisitComplianceRepository {
+	return &VisitComplianceRepository{DB: db}
+}
+
+func scanVisitCompliance(row pgx.Row) (model.VisitCompliance, error) {
+	var compliance model.VisitCompliance
+	err := row.Scan(&compliance.VisitID, &compliance.IsCompliant, &compliance.MissingElements, &compliance.CheckedAt,
+		&compliance.ScheduleID, &compliance.ServiceDate)
+	return compliance, err
+}
+
+// Get the latest compliance check of a visit
+func (r *VisitComplianceRepository) GetVisitCompliance(ctx context.Context, visitID uuid.UUID) (*model.VisitCompliance, error) {
+	query := `
+		SELECT ` + visitComplianceColumns + ` FROM visit_compliance c
+		JOIN visits v ON v.id = c.visit_id
+		WHERE c.visit_id = $1
+	`
+
+	compliance, err := scanVisitCompliance(r.DB.QueryRow(ctx, query, visitID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Visit compliance not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to get visit compliance: %w", err)
+	}
+
+	return &compliance, nil
+}
+
+// Get non-compliant visits whose service date falls within [from, to], with pagination
+func (r *VisitComplianceRepository) GetNonCompliantVisits(ctx context.Context, from, to time.Time, page, limit int) (*model.PaginatedResponse[model.VisitCompliance], error) {
+	query := `
+		SELECT ` + visitComplianceColumns + ` FROM visit_compliance c
+		JOIN visits v ON v.id = c.visit_id
+		JOIN schedules s ON s.id = v.schedule_id
+		WHERE NOT c.is_compliant AND ` + visitServiceDateFilter + `
+		ORDER BY v.start_time ASC, c.visit_id ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	results := make([]model.VisitCompliance, 0)
+	offset := (page - 1) * limit
+	rows, err := r.DB.Query(ctx, query, from, to, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get non-compliant visits: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		compliance, err := scanVisitCompliance(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan visit compliance: %w", err)
+		}
+		results = append(results, compliance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate visit compliance: %w", err)
+	}
+
+	countQuery := `
+		SELECT COUNT(*) FROM visit_compliance c
+		JOIN visits v ON v.id = c.visit_id
+		JOIN schedules s ON s.id = v.schedule_id
+		WHERE NOT c.is_compliant AND ` + visitServiceDateFilter
+
+	var total int
+	if err := r.DB.QueryRow(ctx, countQuery, from, to).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to get non-compliant visit count: %w", err)
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	return &model.PaginatedResponse[model.VisitCompliance]{
+		Data:       results,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Get the IDs of completed visits whose service date falls within [from, to]
+func (r *VisitComplianceRepository) GetCompletedVisitIDs(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	query := `
+		SELECT v.id FROM visits v
+		JOIN schedules s ON s.id = v.schedule_id
+		WHERE v.status = 'completed' AND ` + visitServiceDateFilter + `
+		ORDER BY v.start_time ASC
+	`
+
+	ids := make([]uuid.UUID, 0)
+	rows, err := r.DB.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completed visits: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan visit ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate completed visits: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Store a visit's compliance check, replacing any earlier one
+func (r *VisitComplianceRepository) SaveVisitCompliance(ctx context.Context, compliance *model.VisitCompliance) error {
+	query := `
+		INSERT INTO visit_compliance (visit_id, is_compliant, missing_elements, checked_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (visit_id) DO UPDATE SET
+			is_compliant = EXCLUDED.is_compliant,
+			missing_elements = EXCLUDED.missing_elements,
+			checked_at = EXCLUDED.checked_at
+	`
+
+	_, err := r.DB.Exec(ctx, query, compliance.VisitID, compliance.IsCompliant, compliance.MissingElements, compliance.CheckedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save visit compliance: %w", err)
+	}
+
+	return nil
+}
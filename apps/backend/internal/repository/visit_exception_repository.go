@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/database"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+const visitExceptionColumns = `id, visit_id, type, detail, status, reason_code, explanation, explained_by, explained_at,
+	reviewed_by, reviewed_at, review_comment, created_at, updated_at`
+
+const exceptionReasonCodeColumns = `code, description, is_active, created_at, updated_at`
+
+// upsertVisitExceptionQuery records an already explained exception, sending an
+// existing one of the same type back for review with the new explanation
+const upsertVisitExceptionQuery = `
+	INSERT INTO visit_exceptions (id, visit_id, type, detail, status, reason_code, explanation, explained_by, explained_at, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	ON CONFLICT (visit_id, type) DO UPDATE SET
+		detail = EXCLUDED.detail, status = EXCLUDED.status, reason_code = EXCLUDED.reason_code,
+		explanation = EXCLUDED.explanation, explained_by = EXCLUDED.explained_by, explained_at = EXCLUDED.explained_at,
+		reviewed_by = NULL, reviewed_at = NULL, review_comment = NULL
+`
+
+func upsertVisitExceptionArgs(exception *model.VisitException) []any {
+	return []any{exception.ID, exception.VisitID, exception.Type, exception.Detail, exception.Status, exception.ReasonCode,
+		exception.Explanation, exception.ExplainedBy, exception.ExplainedAt, exception.CreatedAt, exception.UpdatedAt}
+}
+
+type VisitExceptionRepository struct {
+	DB database.DBTX
+}
+
+func NewVisitExceptionRepository(db database.DBTX) *VisitExceptionRepository {
+	return &VisitExceptionRepository{DB: db}
+}
+
+func scanVisitException(row pgx.Row) (model.VisitException, error) {
+	var exception model.VisitException
+	err := row.Scan(&exception.ID, &exception.VisitID, &exception.Type, &exception.Detail, &exception.Status, &exception.ReasonCode,
+		&exception.Explanation, &exception.ExplainedBy, &exception.ExplainedAt, &exception.ReviewedBy, &exception.ReviewedAt,
+		&exception.ReviewComment, &exception.CreatedAt, &exception.UpdatedAt)
+	return exception, err
+}
+
+func scanExceptionReasonCode(row pgx.Row) (model.ExceptionReasonCode, error) {
+	var reason model.ExceptionReasonCode
+	err := row.Scan(&reason.Code, &reason.Description, &reason.IsActive, &reason.CreatedAt, &reason.UpdatedAt)
+	return reason, err
+}
+
+// Get reason codes, optionally only the active ones
+func (r *VisitExceptionRepository) GetExceptionReasonCodes(ctx context.Context, activeOnly bool) ([]model.ExceptionReasonCode, error) {
+	query := `SELECT ` + exceptionReasonCodeColumns + ` FROM exception_reason_codes WHERE (NOT $1 OR is_active) ORDER BY code ASC`
+
+	reasons := make([]model.ExceptionReasonCode, 0)
+	rows, err := r.DB.Query(ctx, query, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exception reason codes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		reason, err := scanExceptionReasonCode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan exception reason code: %w", err)
+		}
+		reasons = append(reasons, reason)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate exception reason codes: %w", err)
+	}
+
+	return reasons, nil
+}
+
+// Get a reason code
+func (r *VisitExceptionRepository) GetExceptionReasonCode(ctx context.Context, code string) (*model.ExceptionReasonCode, error) {
+	query := `SELECT ` + exceptionReasonCodeColumns + ` FROM exception_reason_codes WHERE code = $1`
+
+	reason, err := scanExceptionReasonCode(r.DB.QueryRow(ctx, query, code))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Exception reason code not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to get exception reason code: %w", err)
+	}
+
+	return &reason, nil
+}
+
+// Create a reason code
+func (r *VisitExceptionRepository) CreateExceptionReasonCode(ctx context.Context, code, description string) (*model.ExceptionReasonCode, error) {
+	query := `
+		INSERT INTO exception_reason_codes (code, description)
+		VALUES ($1, $2)
+		RETURNING ` + exceptionReasonCodeColumns
+
+	reason, err := scanExceptionReasonCode(r.DB.QueryRow(ctx, query, code, description))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exception reason code: %w", err)
+	}
+
+	return &reason, nil
+}
+
+// Update a reason code's description and whether it can still be used, only
+// if it is still the version that was read
+func (r *VisitExceptionRepository) UpdateExceptionReasonCode(ctx context.Context, reason *model.ExceptionReasonCode, description string, isActive bool) (*model.ExceptionReasonCode, error) {
+	query := `
+		UPDATE exception_reason_codes SET description = $1, is_active = $2
+		WHERE code = $3 AND updated_at = $4
+		RETURNING ` + exceptionReasonCodeColumns
+
+	updated, err := scanExceptionReasonCode(r.DB.QueryRow(ctx, query, description, isActive, reason.Code, guardVersion(ctx, reason.UpdatedAt)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := "EXCEPTION_REASON_CODE_CHANGED"
+			return nil, versionChanged(ctx, errs.NewConflictError("Exception reason code changed while it was being updated", true, &code))
+		}
+		return nil, fmt.Errorf("failed to update exception reason code: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// Record exceptions for a visit. An exception of a type the visit already has is skipped.
+func (r *VisitExceptionRepository) CreateVisitExceptions(ctx context.Context, exceptions []model.VisitException) error {
+	if len(exceptions) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO visit_exceptions (id, visit_id, type, detail, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (visit_id, type) DO NOTHING
+	`
+
+	for _, exception := range exceptions {
+		_, err := r.DB.Exec(ctx, query, exception.ID, exception.VisitID, exception.Type, exception.Detail, exception.Status,
+			exception.CreatedAt, exception.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create visit exceptions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Get visit exception by ID
+func (r *VisitExceptionRepository) GetVisitExceptionByID(ctx context.Context, id uuid.UUID) (*model.VisitException, error) {
+	query := `SELECT ` + visitExceptionColumns + ` FROM visit_exceptions WHERE id = $1`
+
+	exception, err := scanVisitException(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Visit exception not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to get visit exception: %w", err)
+	}
+
+	return &exception, nil
+}
+
+// Get the exceptions recorded for a visit
+func (r *VisitExceptionRepository) GetVisitExceptionsByVisit(ctx context.Context, visitID uuid.UUID) ([]model.VisitException, error) {
+	query := `SELECT ` + visitExceptionColumns + ` FROM visit_exceptions WHERE visit_id = $1 ORDER BY created_at ASC`
+
+	exceptions := make([]model.VisitException, 0)
+	rows, err := r.DB.Query(ctx, query, visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get visit exceptions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		exception, err := scanVisitException(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan visit exception: %w", err)
+		}
+		exceptions = append(exceptions, exception)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate visit exceptions: %w", err)
+	}
+
+	return exceptions, nil
+}
+
+// Get exceptions with pagination, oldest first so the review queue is worked in order
+func (r *VisitExceptionRepository) GetVisitExceptions(ctx context.Context, filter model.VisitExceptionFilter, page, limit int) (*model.PaginatedResponse[model.VisitException], error) {
+	where := `
+		WHERE ($1 = '' OR status = $1)
+		AND ($2::timestamptz IS NULL OR created_at >= $2)
+		AND ($3::timestamptz IS NULL OR created_at < $3)
+	`
+	args := []any{filter.Status, filter.From, filter.To}
+
+	query := `SELECT ` + visitExceptionColumns + ` FROM visit_exceptions ` + where + ` ORDER BY created_at ASC, id ASC LIMIT $4 OFFSET $5`
+
+	exceptions := make([]model.VisitException, 0)
+	offset := (page - 1) * limit
+	rows, err := r.DB.Query(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get visit exceptions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		exception, err := scanVisitException(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan visit exception: %w", err)
+		}
+		exceptions = append(exceptions, exception)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate visit exceptions: %w", err)
+	}
+
+	var total int
+	err = r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM visit_exceptions `+where, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get visit exception count: %w", err)
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	return &model.PaginatedResponse[model.VisitException]{
+		Data:       exceptions,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Record the caregiver's explanation and send the exception for review, only
+// if it is still the version that was read. A previous review is cleared.
+func (r *VisitExceptionRepository) ExplainVisitException(ctx context.Context, exception *model.VisitException, reasonCode, explanation, explainedBy string) (*model.VisitException, error) {
+	query := `
+		UPDATE visit_exceptions SET
+			status = $1, reason_code = $2, explanation = $3, explained_by = $4, explained_at = NOW(),
+			reviewed_by = NULL, reviewed_at = NULL, review_comment = NULL
+		WHERE id = $5 AND updated_at = $6
+		RETURNING ` + visitExceptionColumns
+
+	to := model.VisitExceptionStatusPendingReview
+	explained, err := scanVisitException(r.DB.QueryRow(ctx, query, to, reasonCode, explanation, explainedBy, exception.ID, guardVersion(ctx, exception.UpdatedAt)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, versionChanged(ctx, model.VisitExceptionStatusMachine.ConflictError(exception.Status, to))
+		}
+		return nil, fmt.Errorf("failed to explain visit exception: %w", err)
+	}
+
+	return &explained, nil
+}
+
+// Record a coordinator's approval or rejection of an explained exception
+func (r *VisitExceptionRepository) ReviewVisitException(ctx context.Context, id uuid.UUID, to, reviewedBy string, comment *string) (*model.VisitException, error) {
+	query := `
+		UPDATE visit_exceptions SET status = $1, reviewed_by = $2, reviewed_at = NOW(), review_comment = $3
+		WHERE id = $4 AND status = $5
+		RETURNING ` + visitExceptionColumns
+
+	from := model.VisitExceptionStatusPendingReview
+	exception, err := scanVisitException(r.DB.QueryRow(ctx, query, to, reviewedBy, comment, id, from))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.VisitExceptionStatusMachine.ConflictError(from, to)
+		}
+		return nil, fmt.Errorf("failed to review visit exception: %w", err)
+	}
+
+	return &exception, nil
+}
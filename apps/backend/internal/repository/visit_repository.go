@@ -2,32 +2,52 @@ package repository
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/database"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/listquery"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
 )
 
+// A visit is verified once it is completed and every exception on it is approved
+const visitColumns = `id, schedule_id, start_time, end_time, start_latitude, start_longitude, end_latitude, end_longitude, status, duration_minutes,
+	distance_meters, end_distance_meters, within_geofence, is_manual_entry,
+	(status = 'completed' AND NOT EXISTS (
+		SELECT 1 FROM visit_exceptions e WHERE e.visit_id = visits.id AND e.status <> 'approved'
+	)) AS is_verified,
+	created_at, updated_at`
+
 type VisitRepository struct {
-	DB *pgxpool.Pool
+	DB database.DBTX
 }
 
-func NewVisitRepository(db *pgxpool.Pool) *VisitRepository {
+func NewVisitRepository(db database.DBTX) *VisitRepository {
 	return &VisitRepository{DB: db}
 }
 
+// scanVisit scans visitColumns, then any columns selected after them into extra
+func scanVisit(row pgx.Row, extra ...any) (model.Visit, error) {
+	var visit model.Visit
+	dest := []any{&visit.ID, &visit.ScheduleID, &visit.StartTime, &visit.EndTime, &visit.StartLatitude, &visit.StartLongitude, &visit.EndLatitude, &visit.EndLongitude, &visit.Status, &visit.DurationMinutes,
+		&visit.DistanceMeters, &visit.EndDistanceMeters, &visit.WithinGeofence, &visit.IsManualEntry, &visit.IsVerified,
+		&visit.CreatedAt, &visit.UpdatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	return visit, err
+}
+
 // Get visit by ID
 func (r *VisitRepository) GetVisitByID(ctx context.Context, id uuid.UUID) (*model.Visit, error) {
-	query := `SELECT * FROM visits WHERE id = $1`
+	query := `SELECT ` + visitColumns + ` FROM visits WHERE id = $1`
 
-	var visit model.Visit
-	err := r.DB.QueryRow(ctx, query, id).Scan(&visit.ID, &visit.ScheduleID, &visit.StartTime, &visit.EndTime, &visit.StartLatitude, &visit.StartLongitude, &visit.EndLatitude, &visit.EndLongitude, &visit.Status, &visit.DurationMinutes, &visit.CreatedAt, &visit.UpdatedAt)
+	visit, err := scanVisit(r.DB.QueryRow(ctx, query, id))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("visit not found")
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("Visit not found", false, nil)
 		}
 		return nil, fmt.Errorf("failed to get visit: %w", err)
 	}
@@ -37,13 +57,12 @@ func (r *VisitRepository) GetVisitByID(ctx context.Context, id uuid.UUID) (*mode
 
 // Get visit by schedule ID
 func (r *VisitRepository) GetVisitByScheduleID(ctx context.Context, scheduleID uuid.UUID) (*model.Visit, error) {
-	query := `SELECT * FROM visits WHERE schedule_id = $1 ORDER BY created_at DESC LIMIT 1`
+	query := `SELECT ` + visitColumns + ` FROM visits WHERE schedule_id = $1 ORDER BY created_at DESC LIMIT 1`
 
-	var visit model.Visit
-	err := r.DB.QueryRow(ctx, query, scheduleID).Scan(&visit.ID, &visit.ScheduleID, &visit.StartTime, &visit.EndTime, &visit.StartLatitude, &visit.StartLongitude, &visit.EndLatitude, &visit.EndLongitude, &visit.Status, &visit.DurationMinutes, &visit.CreatedAt, &visit.UpdatedAt)
+	visit, err := scanVisit(r.DB.QueryRow(ctx, query, scheduleID))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("no visit found for schedule")
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("No visit found for schedule", false, nil)
 		}
 		return nil, fmt.Errorf("failed to get visit: %w", err)
 	}
@@ -51,14 +70,59 @@ func (r *VisitRepository) GetVisitByScheduleID(ctx context.Context, scheduleID u
 	return &visit, nil
 }
 
+const insertVisitQuery = `
+	INSERT INTO visits (id, schedule_id, start_time, end_time, start_latitude, start_longitude, end_latitude, end_longitude, status,
+		duration_minutes, distance_meters, within_geofence, is_manual_entry)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+`
+
+func insertVisitArgs(visit *model.Visit) []any {
+	return []any{visit.ID, visit.ScheduleID, visit.StartTime, visit.EndTime, visit.StartLatitude, visit.StartLongitude, visit.EndLatitude,
+		visit.EndLongitude, visit.Status, visit.DurationMinutes, visit.DistanceMeters, visit.WithinGeofence, visit.IsManualEntry}
+}
+
+const visitCorrectionColumns = `id, visit_id, kind, reason_code, reason, corrected_by,
+	previous_start_time, previous_end_time, previous_start_latitude, previous_start_longitude, previous_end_latitude, previous_end_longitude,
+	corrected_start_time, corrected_end_time, corrected_start_latitude, corrected_start_longitude, corrected_end_latitude, corrected_end_longitude,
+	created_at`
+
+func scanVisitCorrection(row pgx.Row) (model.VisitCorrection, error) {
+	var correction model.VisitCorrection
+	var previous model.VisitClockData
+	corrected := &correction.Corrected
+	err := row.Scan(&correction.ID, &correction.VisitID, &correction.Kind, &correction.ReasonCode, &correction.Reason, &correction.CorrectedBy,
+		&previous.StartTime, &previous.EndTime, &previous.StartLatitude, &previous.StartLongitude, &previous.EndLatitude, &previous.EndLongitude,
+		&corrected.StartTime, &corrected.EndTime, &corrected.StartLatitude, &corrected.StartLongitude, &corrected.EndLatitude, &corrected.EndLongitude,
+		&correction.CreatedAt)
+	if correction.Kind == model.VisitCorrectionKindCorrection {
+		correction.Previous = &previous
+	}
+	return correction, err
+}
+
+const insertVisitCorrectionQuery = `
+	INSERT INTO visit_corrections (id, visit_id, kind, reason_code, reason, corrected_by,
+		previous_start_time, previous_end_time, previous_start_latitude, previous_start_longitude, previous_end_latitude, previous_end_longitude,
+		corrected_start_time, corrected_end_time, corrected_start_latitude, corrected_start_longitude, corrected_end_latitude, corrected_end_longitude,
+		created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+`
+
+func insertVisitCorrectionArgs(correction *model.VisitCorrection) []any {
+	var previous model.VisitClockData
+	if correction.Previous != nil {
+		previous = *correction.Previous
+	}
+	corrected := correction.Corrected
+	return []any{correction.ID, correction.VisitID, correction.Kind, correction.ReasonCode, correction.Reason, correction.CorrectedBy,
+		previous.StartTime, previous.EndTime, previous.StartLatitude, previous.StartLongitude, previous.EndLatitude, previous.EndLongitude,
+		corrected.StartTime, corrected.EndTime, corrected.StartLatitude, corrected.StartLongitude, corrected.EndLatitude, corrected.EndLongitude,
+		correction.CreatedAt}
+}
+
 // Create a new visit
 func (r *VisitRepository) CreateVisit(ctx context.Context, visit *model.Visit) error {
-	query := `
-		INSERT INTO visits (id, schedule_id, start_time, start_latitude, start_longitude, status)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`
-
-	_, err := r.DB.Exec(ctx, query, visit.ID, visit.ScheduleID, visit.StartTime, visit.StartLatitude, visit.StartLongitude, visit.Status)
+	_, err := execAudited(ctx, r.DB, insertVisitQuery, insertVisitArgs(visit)...)
 	if err != nil {
 		return fmt.Errorf("failed to create visit: %w", err)
 	}
@@ -74,7 +138,7 @@ func (r *VisitRepository) UpdateVisit(ctx context.Context, visit *model.Visit) e
 		WHERE id = $6
 	`
 
-	_, err := r.DB.Exec(ctx, query, visit.EndTime, visit.EndLatitude, visit.EndLongitude, visit.Status, visit.DurationMinutes, visit.ID)
+	_, err := execAudited(ctx, r.DB, query, visit.EndTime, visit.EndLatitude, visit.EndLongitude, visit.Status, visit.DurationMinutes, visit.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update visit: %w", err)
 	}
@@ -82,63 +146,76 @@ func (r *VisitRepository) UpdateVisit(ctx context.Context, visit *model.Visit) e
 	return nil
 }
 
-// Update visit status
-func (r *VisitRepository) UpdateVisitStatus(ctx context.Context, visitID uuid.UUID, status string) error {
-	query := `UPDATE visits SET status = $1 WHERE id = $2`
+// Update visit status, only if it is still in the expected status
+func (r *VisitRepository) UpdateVisitStatus(ctx context.Context, visitID uuid.UUID, from, to string) error {
+	query := `UPDATE visits SET status = $1 WHERE id = $2 AND status = $3`
 
-	_, err := r.DB.Exec(ctx, query, status, visitID)
+	result, err := execAudited(ctx, r.DB, query, to, visitID, from)
 	if err != nil {
 		return fmt.Errorf("failed to update visit status: %w", err)
 	}
 
+	if result.RowsAffected() == 0 {
+		return model.VisitStatusMachine.ConflictError(from, to)
+	}
+
 	return nil
 }
 
-// Start visit (set start time and location)
-func (r *VisitRepository) StartVisit(ctx context.Context, scheduleID uuid.UUID, startTime time.Time, startLat, startLong float64) (*model.Visit, error) {
+// Start visit (set start time, location and geofence result)
+func (r *VisitRepository) StartVisit(ctx context.Context, scheduleID uuid.UUID, startTime time.Time, startLat, startLong float64, geofence *model.GeofenceResult) (*model.Visit, error) {
 	visit := &model.Visit{
-		ScheduleID:    scheduleID,
-		StartTime:     startTime,
-		StartLatitude: startLat,
+		ScheduleID:     scheduleID,
+		StartTime:      startTime,
+		StartLatitude:  startLat,
 		StartLongitude: startLong,
-		Status:        "in_progress",
+		Status:         model.VisitStatusInProgress,
 	}
-
-	query := `
-		INSERT INTO visits (id, schedule_id, start_time, start_latitude, start_longitude, status)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`
-
 	visit.ID = uuid.New()
 
-	_, err := r.DB.Exec(ctx, query, visit.ID, visit.ScheduleID, visit.StartTime, visit.StartLatitude, visit.StartLongitude, visit.Status)
-	if err != nil {
+	if geofence != nil {
+		visit.DistanceMeters = &geofence.DistanceMeters
+		visit.WithinGeofence = &geofence.WithinGeofence
+	}
+
+	if err := r.CreateVisit(ctx, visit); err != nil {
 		return nil, fmt.Errorf("failed to start visit: %w", err)
 	}
 
 	return visit, nil
 }
 
-// End visit (set end time and location)
-func (r *VisitRepository) EndVisit(ctx context.Context, visitID uuid.UUID, endTime time.Time, endLat, endLong float64) (*model.Visit, error) {
-	visit := &model.Visit{
-		EndTime:     &endTime,
-		EndLatitude: &endLat,
-		EndLongitude: &endLong,
-		Status:      "completed",
+// End visit (set end time, location, duration and geofence result)
+func (r *VisitRepository) EndVisit(ctx context.Context, visitID uuid.UUID, endTime time.Time, endLat, endLong float64, geofence *model.GeofenceResult) (*model.Visit, error) {
+	var endDistance *float64
+	var endWithin *bool
+	if geofence != nil {
+		endDistance = &geofence.DistanceMeters
+		endWithin = &geofence.WithinGeofence
 	}
 
+	// A visit is only within the geofence when both clock events were
 	query := `
 		UPDATE visits
-		SET end_time = $1, end_latitude = $2, end_longitude = $3, status = $4
-		WHERE id = $5
+		SET end_time = $1, end_latitude = $2, end_longitude = $3, status = $4,
+			duration_minutes = FLOOR(EXTRACT(EPOCH FROM ($1 - start_time)) / 60)::int,
+			end_distance_meters = $5,
+			within_geofence = CASE
+				WHEN $6::boolean IS NULL THEN within_geofence
+				ELSE COALESCE(within_geofence, TRUE) AND $6::boolean
+			END
+		WHERE id = $7 AND status = $8
 	`
 
-	_, err := r.DB.Exec(ctx, query, visit.EndTime, visit.EndLatitude, visit.EndLongitude, visit.Status, visit.ID)
+	result, err := execAudited(ctx, r.DB, query, endTime, endLat, endLong, model.VisitStatusCompleted, endDistance, endWithin, visitID, model.VisitStatusInProgress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to end visit: %w", err)
 	}
 
+	if result.RowsAffected() == 0 {
+		return nil, model.VisitStatusMachine.ConflictError(model.VisitStatusInProgress, model.VisitStatusCompleted)
+	}
+
 	// Get updated visit with calculated duration
 	updatedVisit, err := r.GetVisitByID(ctx, visitID)
 	if err != nil {
@@ -148,6 +225,123 @@ func (r *VisitRepository) EndVisit(ctx context.Context, visitID uuid.UUID, endTi
 	return updatedVisit, nil
 }
 
+// Record a visit entered by a coordinator after the fact together with its
+// correction history, its manual entry exception and the schedule's new status
+func (r *VisitRepository) CreateManualVisit(ctx context.Context, visit *model.Visit, correction *model.VisitCorrection, exception *model.VisitException,
+	scheduleFrom, scheduleTo string) error {
+	tx, err := beginAudited(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, insertVisitQuery, insertVisitArgs(visit)...); err != nil {
+		return fmt.Errorf("failed to create manual visit: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, insertVisitCorrectionQuery, insertVisitCorrectionArgs(correction)...); err != nil {
+		return fmt.Errorf("failed to create visit correction: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, upsertVisitExceptionQuery, upsertVisitExceptionArgs(exception)...); err != nil {
+		return fmt.Errorf("failed to record manual entry exception: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, `UPDATE schedules SET status = $1 WHERE id = $2 AND status = $3`, scheduleTo, visit.ScheduleID, scheduleFrom)
+	if err != nil {
+		return fmt.Errorf("failed to update schedule status: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return model.ScheduleStatusMachine.ConflictError(scheduleFrom, scheduleTo)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit manual visit: %w", err)
+	}
+
+	return nil
+}
+
+// Save a coordinator's correction of a visit, only if the visit is still the
+// version it was read as, together with its correction history and manual
+// entry exception. A correction that completes the visit completes its schedule.
+func (r *VisitRepository) CorrectVisit(ctx context.Context, visit *model.Visit, from string, correction *model.VisitCorrection, exception *model.VisitException) error {
+	tx, err := beginAudited(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE visits
+		SET start_time = $1, end_time = $2, start_latitude = $3, start_longitude = $4, end_latitude = $5, end_longitude = $6,
+			status = $7, duration_minutes = $8
+		WHERE id = $9 AND status = $10 AND updated_at = $11
+	`
+	result, err := tx.Exec(ctx, query, visit.StartTime, visit.EndTime, visit.StartLatitude, visit.StartLongitude, visit.EndLatitude, visit.EndLongitude,
+		visit.Status, visit.DurationMinutes, visit.ID, from, guardVersion(ctx, visit.UpdatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to correct visit: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		code := "VISIT_CHANGED"
+		return versionChanged(ctx, errs.NewConflictError("Visit changed while it was being corrected", true, &code))
+	}
+
+	if _, err := tx.Exec(ctx, insertVisitCorrectionQuery, insertVisitCorrectionArgs(correction)...); err != nil {
+		return fmt.Errorf("failed to create visit correction: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, upsertVisitExceptionQuery, upsertVisitExceptionArgs(exception)...); err != nil {
+		return fmt.Errorf("failed to record manual entry exception: %w", err)
+	}
+
+	if from != visit.Status && visit.Status == model.VisitStatusCompleted {
+		query := `UPDATE schedules SET status = $1 WHERE id = $2 AND status = $3`
+		result, err := tx.Exec(ctx, query, model.ScheduleStatusCompleted, visit.ScheduleID, model.ScheduleStatusInProgress)
+		if err != nil {
+			return fmt.Errorf("failed to update schedule status: %w", err)
+		}
+		// A schedule that is no longer in progress, e.g. cancelled meanwhile,
+		// can't complete, so the correction is rolled back with it
+		if result.RowsAffected() == 0 {
+			return model.ScheduleStatusMachine.ConflictError(model.ScheduleStatusInProgress, model.ScheduleStatusCompleted)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit visit correction: %w", err)
+	}
+
+	return nil
+}
+
+// Get a visit's manual entry and corrections, oldest first
+func (r *VisitRepository) GetVisitCorrections(ctx context.Context, visitID uuid.UUID) ([]model.VisitCorrection, error) {
+	query := `SELECT ` + visitCorrectionColumns + ` FROM visit_corrections WHERE visit_id = $1 ORDER BY created_at ASC`
+
+	corrections := make([]model.VisitCorrection, 0)
+	rows, err := r.DB.Query(ctx, query, visitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get visit corrections: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		correction, err := scanVisitCorrection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan visit correction: %w", err)
+		}
+		corrections = append(corrections, correction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate visit corrections: %w", err)
+	}
+
+	return corrections, nil
+}
+
 // Get visit statistics
 func (r *VisitRepository) GetVisitStats(ctx context.Context) (*model.TaskStats, error) {
 	query := `
@@ -177,7 +371,7 @@ func (r *VisitRepository) GetVisitStats(ctx context.Context) (*model.TaskStats,
 
 // Get visit by status
 func (r *VisitRepository) GetVisitsByStatus(ctx context.Context, status string) ([]model.Visit, error) {
-	query := `SELECT * FROM visits WHERE status = $1 ORDER BY created_at DESC`
+	query := `SELECT ` + visitColumns + ` FROM visits WHERE status = $1 ORDER BY created_at DESC`
 
 	var visits []model.Visit
 	rows, err := r.DB.Query(ctx, query, status)
@@ -187,8 +381,8 @@ func (r *VisitRepository) GetVisitsByStatus(ctx context.Context, status string)
 	defer rows.Close()
 
 	for rows.Next() {
-		var visit model.Visit
-		if err := rows.Scan(&visit.ID, &visit.ScheduleID, &visit.StartTime, &visit.EndTime, &visit.StartLatitude, &visit.StartLongitude, &visit.EndLatitude, &visit.EndLongitude, &visit.Status, &visit.DurationMinutes, &visit.CreatedAt, &visit.UpdatedAt); err != nil {
+		visit, err := scanVisit(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan visit: %w", err)
 		}
 		visits = append(visits, visit)
@@ -201,6 +395,44 @@ func (r *VisitRepository) GetVisitsByStatus(ctx context.Context, status string)
 	return visits, nil
 }
 
+// Get a page of visits matching listing's filters, in its sort
+func (r *VisitRepository) GetVisits(ctx context.Context, page model.CursorPage, listing *listquery.Query[model.Visit]) (*model.CursorPaginatedResponse[model.Visit], error) {
+	where, args := listing.Where(1)
+	keyset, order, keysetArgs := keysetClause(listing, page, len(args)+1)
+	query := `SELECT ` + visitColumns + ` FROM visits WHERE ` + where + ` AND ` + keyset + ` ` + order
+
+	visits := make([]model.Visit, 0)
+	rows, err := r.DB.Query(ctx, query, append(args, keysetArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get visits: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		visit, err := scanVisit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan visit: %w", err)
+		}
+		visits = append(visits, visit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate visits: %w", err)
+	}
+
+	response := model.NewCursorPaginatedResponse(visits, page, cursorKey(listing))
+
+	if page.IncludeTotal {
+		var total int
+		if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM visits WHERE `+where, args...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to get visit count: %w", err)
+		}
+		response.Total = &total
+	}
+
+	return response, nil
+}
+
 // Check if visit exists for schedule
 func (r *VisitRepository) VisitExistsForSchedule(ctx context.Context, scheduleID uuid.UUID) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM visits WHERE schedule_id = $1)`
@@ -242,4 +474,44 @@ func (r *VisitRepository) GetVisitDurationStats(ctx context.Context) (map[string
 	}
 
 	return stats, nil
-}
\ No newline at end of file
+}
+
+// GetCompletedVisitsByCaregiver returns the completed visits that started in
+// [from, to) on schedules assigned to a caregiver, or to any caregiver when
+// caregiverID is nil, ordered by caregiver and start time
+func (r *VisitRepository) GetCompletedVisitsByCaregiver(ctx context.Context, from, to time.Time, caregiverID *uuid.UUID) ([]model.CaregiverVisit, error) {
+	query := `
+		SELECT ` + visitColumns + `,
+			(SELECT s.caregiver_id FROM schedules s WHERE s.id = visits.schedule_id) AS caregiver_id
+		FROM visits
+		WHERE status = 'completed' AND end_time IS NOT NULL
+			AND start_time >= $1 AND start_time < $2
+			AND schedule_id IN (
+				SELECT s.id FROM schedules s
+				WHERE s.caregiver_id IS NOT NULL AND ($3::uuid IS NULL OR s.caregiver_id = $3)
+			)
+		ORDER BY caregiver_id, start_time, id
+	`
+
+	rows, err := r.DB.Query(ctx, query, from, to, caregiverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completed visits by caregiver: %w", err)
+	}
+	defer rows.Close()
+
+	visits := make([]model.CaregiverVisit, 0)
+	for rows.Next() {
+		var visit model.CaregiverVisit
+		visit.Visit, err = scanVisit(rows, &visit.CaregiverID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan visit: %w", err)
+		}
+		visits = append(visits, visit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate completed visits: %w", err)
+	}
+
+	return visits, nil
+}
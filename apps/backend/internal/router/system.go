@@ -2,6 +2,7 @@ package router
 
 import (
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/handler"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/middleware"
 
 	"github.com/labstack/echo/v4"
 )
@@ -16,27 +17,124 @@ func registerSystemRoutes(r *echo.Echo, h *handler.Handlers) {
 	r.GET("/docs/swagger.json", h.Swagger.ServeOpenAPISpec)
 }
 
-func registerEVVRoutes(r *echo.Echo, h *handler.Handlers) {
+func registerEVVRoutes(r *echo.Echo, h *handler.Handlers, auth *middleware.AuthMiddleware, idempotency *middleware.IdempotencyMiddleware) {
+	// Retried POST and PATCH requests carrying an Idempotency-Key get the original
+	// response. Keys are scoped to the user, so idempotent routes run it after
+	// RequireAuth.
+	idempotent := idempotency.Idempotent
+
 	// Schedule endpoints
 	r.GET("/api/v1/schedules", h.EVV.GetSchedules)
 	r.GET("/api/v1/schedules/today", h.EVV.GetTodaySchedules)
 	r.GET("/api/v1/schedules/:id", h.EVV.GetScheduleById)
-	r.POST("/api/v1/schedules", h.EVV.CreateSchedule)
-	r.PATCH("/api/v1/schedules/:id/status", h.EVV.UpdateScheduleStatus)
-	r.GET("/api/v1/schedules/stats", h.EVV.GetMockStats)
+	r.POST("/api/v1/schedules", h.EVV.CreateSchedule, auth.RequireAuth, idempotent)
+	r.PUT("/api/v1/schedules/:id", h.EVV.UpdateSchedule, auth.RequireAuth)
+	r.PATCH("/api/v1/schedules/:id/status", h.EVV.UpdateScheduleStatus, auth.RequireAuth, idempotent)
+	r.GET("/api/v1/schedules/stats", h.EVV.GetScheduleStats)
 	r.GET("/api/v1/schedules/search", h.EVV.SearchSchedules)
 
-	// Visit tracking endpoints - simplified for now
-	r.POST("/api/v1/schedules/:id/start", h.EVV.CreateSchedule)  // Temp: use create schedule
-	r.POST("/api/v1/schedules/:id/end", h.EVV.CreateSchedule)     // Temp: use create schedule
-	r.GET("/api/v1/schedules/:id/visit", h.EVV.GetScheduleById)   // Temp: return schedule data
+	// Recurring schedule series endpoints
+	r.POST("/api/v1/schedule-series", h.ScheduleSeries.CreateScheduleSeries, auth.RequireAuth, idempotent)
+	r.GET("/api/v1/schedule-series/:id", h.ScheduleSeries.GetScheduleSeriesById)
+	r.GET("/api/v1/schedule-series/:id/schedules", h.ScheduleSeries.GetScheduleSeriesSchedules)
+
+	// Caregiver assignment endpoints
+	r.POST("/api/v1/schedules/:id/assign", h.Caregiver.AssignCaregiver, auth.RequireAuth, idempotent)
+	r.PUT("/api/v1/schedules/:id/caregiver", h.Caregiver.ReassignCaregiver, auth.RequireAuth)
+
+	// Caregiver endpoints
+	r.GET("/api/v1/caregivers", h.Caregiver.GetCaregivers)
+	r.POST("/api/v1/caregivers", h.Caregiver.CreateCaregiver, auth.RequireAuth, idempotent)
+	r.GET("/api/v1/caregivers/:id", h.Caregiver.GetCaregiverById)
+
+	// Client endpoints
+	r.GET("/api/v1/clients", h.Client.GetClients)
+	r.POST("/api/v1/clients", h.Client.CreateClient, auth.RequireAuth, idempotent)
+	r.GET("/api/v1/clients/:id", h.Client.GetClientById)
+	r.PUT("/api/v1/clients/:id", h.Client.UpdateClient, auth.RequireAuth)
+	r.DELETE("/api/v1/clients/:id", h.Client.DeleteClient, auth.RequireAuth)
+
+	// Care plan endpoints
+	r.GET("/api/v1/clients/:id/care-plans", h.CarePlan.GetClientCarePlans)
+	r.POST("/api/v1/clients/:id/care-plans", h.CarePlan.CreateCarePlan, auth.RequireAuth, idempotent)
+	r.GET("/api/v1/clients/:id/care-plan", h.CarePlan.GetActiveCarePlan)
+	r.GET("/api/v1/care-plans/:id", h.CarePlan.GetCarePlanById)
+	r.POST("/api/v1/care-plans/:id/activate", h.CarePlan.ActivateCarePlan, auth.RequireAuth, idempotent)
+	r.POST("/api/v1/care-plans/:id/deactivate", h.CarePlan.DeactivateCarePlan, auth.RequireAuth, idempotent)
+
+	// Endpoints scoped to the authenticated user
+	me := r.Group("/api/v1/me", auth.RequireAuth)
+	me.GET("/schedules", h.Caregiver.GetMySchedules)
+
+	// Visit tracking endpoints
+	r.POST("/api/v1/schedules/:id/start", h.EVV.StartVisit, auth.RequireAuth, idempotent)
+	r.POST("/api/v1/schedules/:id/end", h.EVV.EndVisit, auth.RequireAuth, idempotent)
+	r.GET("/api/v1/schedules/:id/visit", h.EVV.GetVisit)
+	r.GET("/api/v1/visits", h.EVV.GetVisits)
+
+	// Batch sync of clock and task events recorded offline on a device
+	r.POST("/api/v1/sync/events", h.Sync.SyncEvents, auth.RequireAuth, idempotent)
+
+	// Manual visit entry and correction, coordinators only
+	coordinator := auth.RequireRole(middleware.RoleCoordinator, middleware.RoleAdmin)
+	r.POST("/api/v1/schedules/:id/visit/manual", h.EVV.CreateManualVisit, auth.RequireAuth, coordinator, idempotent)
+	r.PATCH("/api/v1/visits/:id", h.EVV.CorrectVisit, auth.RequireAuth, coordinator, idempotent)
+	r.GET("/api/v1/visits/:id/corrections", h.EVV.GetVisitCorrections)
+
+	// Visit exception endpoints: caregivers explain, coordinators review
+	r.GET("/api/v1/visit-exception-reasons", h.VisitException.GetExceptionReasonCodes)
+	r.POST("/api/v1/visit-exception-reasons", h.VisitException.CreateExceptionReasonCode, auth.RequireAuth, coordinator, idempotent)
+	r.PUT("/api/v1/visit-exception-reasons/:code", h.VisitException.UpdateExceptionReasonCode, auth.RequireAuth, coordinator)
+	r.GET("/api/v1/visits/:id/exceptions", h.VisitException.GetVisitExceptionsByVisit)
+	r.GET("/api/v1/visit-exceptions", h.VisitException.GetVisitExceptions, auth.RequireAuth, coordinator)
+	r.GET("/api/v1/visit-exceptions/:id", h.VisitException.GetVisitExceptionById, auth.RequireAuth)
+	r.PUT("/api/v1/visit-exceptions/:id/explanation", h.VisitException.ExplainVisitException, auth.RequireAuth)
+	r.POST("/api/v1/visit-exceptions/:id/approve", h.VisitException.ApproveVisitException, auth.RequireAuth, coordinator, idempotent)
+	r.POST("/api/v1/visit-exceptions/:id/reject", h.VisitException.RejectVisitException, auth.RequireAuth, coordinator, idempotent)
+
+	// EVV completeness of completed visits against the six Cures Act data elements
+	r.GET("/api/v1/visit-compliance", h.VisitCompliance.GetNonCompliantVisits, auth.RequireAuth, coordinator)
+	r.POST("/api/v1/visit-compliance/check", h.VisitCompliance.CheckVisits, auth.RequireAuth, coordinator, idempotent)
+	r.GET("/api/v1/visits/:id/compliance", h.VisitCompliance.GetVisitCompliance)
+	r.POST("/api/v1/visits/:id/compliance", h.VisitCompliance.CheckVisit, auth.RequireAuth, coordinator, idempotent)
+
+	// Billing rates per payer and service code, and billing of completed visits at them
+	r.GET("/api/v1/billing/rates", h.Billing.GetBillingRates, auth.RequireAuth, coordinator)
+	r.POST("/api/v1/billing/rates", h.Billing.CreateBillingRate, auth.RequireAuth, coordinator, idempotent)
+	r.GET("/api/v1/billing/rates/:id", h.Billing.GetBillingRateById, auth.RequireAuth, coordinator)
+	r.POST("/api/v1/billing/recalculate", h.Billing.BillVisits, auth.RequireAuth, coordinator, idempotent)
+	r.GET("/api/v1/visits/:id/billing", h.Billing.GetVisitBilling, auth.RequireAuth, coordinator)
+	r.POST("/api/v1/visits/:id/billing", h.Billing.BillVisit, auth.RequireAuth, coordinator, idempotent)
+
+	// Export of verified visits to the state EVV aggregator
+	r.POST("/api/v1/aggregator/batches", h.Aggregator.ExportVisits, auth.RequireAuth, coordinator, idempotent)
+	r.GET("/api/v1/aggregator/batches", h.Aggregator.GetAggregatorBatches, auth.RequireAuth, coordinator)
+	r.GET("/api/v1/aggregator/batches/:id", h.Aggregator.GetAggregatorBatchById, auth.RequireAuth, coordinator)
+	r.GET("/api/v1/aggregator/submissions", h.Aggregator.GetSubmissions, auth.RequireAuth, coordinator)
+	r.GET("/api/v1/visits/:id/submissions", h.Aggregator.GetVisitSubmissions)
+
+	// Synced events for review, e.g. those flagged for clock skew or late arrival
+	r.GET("/api/v1/sync/events", h.Sync.GetSyncEvents, auth.RequireAuth, coordinator)
+
+	// Audit trail of schedule, visit and task changes
+	r.GET("/api/v1/audit-events", h.Audit.GetAuditEvents, auth.RequireAuth, coordinator)
+
+	// Downloadable reports of a period as CSV, XLSX or PDF
+	r.GET("/api/v1/reports/visit-log", h.Report.GetVisitLogReport, auth.RequireAuth, coordinator)
+	r.GET("/api/v1/reports/task-completion", h.Report.GetTaskCompletionReport, auth.RequireAuth, coordinator)
+	r.GET("/api/v1/reports/exceptions", h.Report.GetExceptionsReport, auth.RequireAuth, coordinator)
+
+	// Caregiver hours and overtime per pay period for payroll
+	r.GET("/api/v1/timesheets", h.Timesheet.GetTimesheets, auth.RequireAuth, coordinator)
+	r.GET("/api/v1/timesheets/export", h.Timesheet.ExportTimesheets, auth.RequireAuth, coordinator)
 
-	// Task management endpoints - simplified for now
-	r.GET("/api/v1/schedules/:id/tasks", h.EVV.GetScheduleById)   // Temp: return schedule data
-	r.POST("/api/v1/schedules/:id/tasks", h.EVV.CreateSchedule)  // Temp: use create schedule
-	r.PATCH("/api/v1/schedules/:scheduleId/tasks/:taskId/status", h.EVV.UpdateTaskStatus)
+	// Task management endpoints
+	r.GET("/api/v1/tasks", h.EVV.GetTasks)
+	r.GET("/api/v1/schedules/:id/tasks", h.EVV.GetScheduleTasks)
+	r.POST("/api/v1/schedules/:id/tasks", h.EVV.CreateTask, auth.RequireAuth, idempotent)
+	r.PATCH("/api/v1/schedules/:scheduleId/tasks/:taskId/status", h.EVV.UpdateTaskStatus, auth.RequireAuth, idempotent)
 
 	// Analytics endpoints
-	r.GET("/api/v1/schedules/:id/analytics", h.EVV.GetMockStats)
+	r.GET("/api/v1/schedules/:id/analytics", h.EVV.GetScheduleAnalytics)
 	r.GET("/api/v1/tasks/stats", h.EVV.GetTaskStats)
 }
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/aggregator"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
+)
+
+type AggregatorService struct {
+	aggregatorRepo *repository.AggregatorRepository
+	visitRepo      *repository.VisitRepository
+	format         aggregator.Format
+	transport      aggregator.Transport
+	cfg            *config.AggregatorConfig
+}
+
+func NewAggregatorService(aggregatorRepo *repository.AggregatorRepository, visitRepo *repository.VisitRepository,
+	cfg *config.AggregatorConfig) (*AggregatorService, error) {
+	format, err := aggregator.NewFormat(cfg.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := aggregator.NewTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AggregatorService{
+		aggregatorRepo: aggregatorRepo,
+		visitRepo:      visitRepo,
+		format:         format,
+		transport:      transport,
+		cfg:            cfg,
+	}, nil
+}
+
+// ExportVisits submits every visit that is due to the aggregator, in batches
+// of the configured size, and returns the batches created. A failed delivery
+// is recorded on its batch and stops the export; its visits are retried on
+// the next run.
+func (s *AggregatorService) ExportVisits(ctx context.Context) ([]model.AggregatorBatch, error) {
+	batches := make([]model.AggregatorBatch, 0)
+	for {
+		records, err := s.aggregatorRepo.GetVisitsToSubmit(ctx, s.cfg.BatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return batches, nil
+		}
+
+		batch, err := s.exportBatch(ctx, records)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, *batch)
+
+		if batch.Status != model.AggregatorBatchStatusDelivered || len(records) < s.cfg.BatchSize {
+			return batches, nil
+		}
+	}
+}
+
+// exportBatch renders the records, records the batch as pending and delivers it
+func (s *AggregatorService) exportBatch(ctx context.Context, records []aggregator.Record) (*model.AggregatorBatch, error) {
+	now := time.Now()
+	visitIDs := make([]uuid.UUID, 0, len(records))
+	for i := range records {
+		records[i].ProviderID = s.cfg.ProviderID
+		visitIDs = append(visitIDs, records[i].VisitID)
+	}
+
+	doc, err := aggregator.Render(s.format, &aggregator.Batch{ID: uuid.New(), CreatedAt: now, Records: records})
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &model.AggregatorBatch{
+		Base: model.Base{
+			BaseWithId:        model.BaseWithId{ID: doc.BatchID},
+			BaseWithCreatedAt: model.BaseWithCreatedAt{CreatedAt: now},
+			BaseWithUpdatedAt: model.BaseWithUpdatedAt{UpdatedAt: now},
+		},
+		Format:     s.format.Name(),
+		Transport:  s.transport.Name(),
+		Status:     model.AggregatorBatchStatusPending,
+		FileName:   doc.FileName,
+		VisitCount: len(records),
+	}
+	if err := s.aggregatorRepo.CreateAggregatorBatch(ctx, batch, visitIDs); err != nil {
+		return nil, err
+	}
+
+	receipt, deliverErr := s.transport.Deliver(ctx, doc)
+	if deliverErr != nil {
+		if err := s.aggregatorRepo.FailAggregatorBatch(ctx, batch.ID, deliverErr.Error()); err != nil {
+			return nil, fmt.Errorf("failed to record delivery failure (%v): %w", deliverErr, err)
+		}
+	} else if err := s.aggregatorRepo.CompleteAggregatorBatch(ctx, batch.ID, receipt); err != nil {
+		return nil, err
+	}
+
+	return s.aggregatorRepo.GetAggregatorBatchByID(ctx, batch.ID)
+}
+
+// Get aggregator batches, optionally filtered by status
+func (s *AggregatorService) GetAggregatorBatches(ctx context.Context, page, limit int, status string) (*model.PaginatedResponse[model.AggregatorBatch], error) {
+	return s.aggregatorRepo.GetAggregatorBatches(ctx, page, limit, status)
+}
+
+// Get an aggregator batch with the submission status of each of its visits
+func (s *AggregatorService) GetAggregatorBatchByID(ctx context.Context, id uuid.UUID) (*model.AggregatorBatchWithSubmissions, error) {
+	batch, err := s.aggregatorRepo.GetAggregatorBatchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	submissions, err := s.aggregatorRepo.GetBatchSubmissions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AggregatorBatchWithSubmissions{
+		AggregatorBatch: *batch,
+		Submissions:     submissions,
+	}, nil
+}
+
+// Get visit submissions, optionally filtered by status
+func (s *AggregatorService) GetSubmissions(ctx context.Context, page, limit int, status string) (*model.PaginatedResponse[model.VisitSubmission], error) {
+	return s.aggregatorRepo.GetSubmissions(ctx, page, limit, status)
+}
+
+// Get a visit's submissions, newest first
+func (s *AggregatorService) GetVisitSubmissions(ctx context.Context, visitID uuid.UUID) ([]model.VisitSubmission, error) {
+	if _, err := s.visitRepo.GetVisitByID(ctx, visitID); err != nil {
+		return nil, err
+	}
+
+	return s.aggregatorRepo.GetVisitSubmissions(ctx, visitID)
+}
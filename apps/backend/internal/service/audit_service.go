@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
+)
+
+type AuditService struct {
+	auditRepo *repository.AuditEventRepository
+}
+
+func NewAuditService(auditRepo *repository.AuditEventRepository) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+	}
+}
+
+// Get the audit trail, newest first
+func (s *AuditService) GetAuditEvents(ctx context.Context, filter model.AuditEventFilter, page, limit int) (*model.PaginatedResponse[model.AuditEvent], error) {
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, errs.NewBadRequestError("to must be after from", true, nil, nil, nil)
+	}
+
+	return s.auditRepo.GetAuditEvents(ctx, filter, page, limit)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
+)
+
+type BillingService struct {
+	billingRepo   *repository.BillingRepository
+	visitRepo     *repository.VisitRepository
+	scheduleRepo  *repository.ScheduleRepository
+	clientRepo    *repository.ClientRepository
+	caregiverRepo *repository.CaregiverRepository
+}
+
+func NewBillingService(billingRepo *repository.BillingRepository, visitRepo *repository.VisitRepository, scheduleRepo *repository.ScheduleRepository,
+	clientRepo *repository.ClientRepository, caregiverRepo *repository.CaregiverRepository) *BillingService {
+	return &BillingService{
+		billingRepo:   billingRepo,
+		visitRepo:     visitRepo,
+		scheduleRepo:  scheduleRepo,
+		clientRepo:    clientRepo,
+		caregiverRepo: caregiverRepo,
+	}
+}
+
+// Get billing rates with pagination, optionally of one payer and service code
+func (s *BillingService) GetBillingRates(ctx context.Context, page, limit int, payer, serviceCode string) (*model.PaginatedResponse[model.BillingRate], error) {
+	return s.billingRepo.GetBillingRates(ctx, page, limit, payer, serviceCode)
+}
+
+// Get billing rate by ID
+func (s *BillingService) GetBillingRateByID(ctx context.Context, id uuid.UUID) (*model.BillingRate, error) {
+	return s.billingRepo.GetBillingRateByID(ctx, id)
+}
+
+// Create a billing rate. Rates are not edited once visits may have been billed
+// at them; a new rate takes over from the date it is effective.
+func (s *BillingService) CreateBillingRate(ctx context.Context, create model.BillingRateCreate) (*model.BillingRate, error) {
+	if create.EffectiveTo != nil && create.EffectiveTo.Before(create.EffectiveFrom) {
+		return nil, errs.NewBadRequestError("effectiveTo must not be before effectiveFrom", true, nil, nil, nil)
+	}
+
+	if create.CaregiverID != nil {
+		if _, err := s.caregiverRepo.GetCaregiverByID(ctx, *create.CaregiverID); err != nil {
+			return nil, err
+		}
+	}
+
+	if create.UnitMinutes == 0 {
+		create.UnitMinutes = model.DefaultBillingUnitMinutes
+	}
+
+	rate := &model.BillingRate{
+		Payer:         create.Payer,
+		ServiceCode:   create.ServiceCode,
+		CaregiverID:   create.CaregiverID,
+		UnitMinutes:   create.UnitMinutes,
+		Rounding:      create.Rounding,
+		UnitRateCents: create.UnitRateCents,
+		EffectiveFrom: create.EffectiveFrom,
+		EffectiveTo:   create.EffectiveTo,
+	}
+	rate.ID = uuid.New()
+
+	if err := s.billingRepo.CreateBillingRate(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+// Get the latest billing calculation of a visit
+func (s *BillingService) GetVisitBilling(ctx context.Context, visitID uuid.UUID) (*model.VisitBilling, error) {
+	if _, err := s.visitRepo.GetVisitByID(ctx, visitID); err != nil {
+		return nil, err
+	}
+
+	return s.billingRepo.GetVisitBilling(ctx, visitID)
+}
+
+// Bill a completed visit again, e.g. after a rate was added or its client's
+// payer or its schedule's service code was filled in
+func (s *BillingService) BillVisit(ctx context.Context, visitID uuid.UUID) (*model.VisitBilling, error) {
+	visit, err := s.visitRepo.GetVisitByID(ctx, visitID)
+	if err != nil {
+		return nil, err
+	}
+
+	if visit.Status != model.VisitStatusCompleted {
+		code := "VISIT_NOT_COMPLETED"
+		return nil, errs.NewBadRequestError("Only completed visits are billed", true, &code, nil, nil)
+	}
+
+	schedule, err := s.scheduleRepo.GetScheduleByID(ctx, visit.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return recordVisitBilling(ctx, s.billingRepo, s.clientRepo, visit, schedule)
+}
+
+// Bill every completed visit with a service date in [from, to] again
+func (s *BillingService) BillVisits(ctx context.Context, from, to time.Time) (*model.VisitBillingRun, error) {
+	if to.Before(from) {
+		return nil, errs.NewBadRequestError("to must not be before from", true, nil, nil, nil)
+	}
+
+	ids, err := s.billingRepo.GetCompletedVisitIDs(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// The total covers the visits that were billed; a coordinator can fix and
+	// bill the failed ones on their own
+	run := &model.VisitBillingRun{From: from, To: to, Failures: []model.VisitRunFailure{}}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		billing, err := s.BillVisit(ctx, id)
+		if err != nil {
+			run.Failures = append(run.Failures, model.VisitRunFailure{VisitID: id, Error: err.Error()})
+			continue
+		}
+		run.Calculated++
+		if billing.AmountCents == nil {
+			run.Unbilled++
+			continue
+		}
+		run.AmountCents += *billing.AmountCents
+	}
+
+	return run, nil
+}
+
+// recordVisitBilling bills a completed visit at the rate of its client's
+// payer and its schedule's service code on the service date, and stores the
+// result
+func recordVisitBilling(ctx context.Context, billingRepo *repository.BillingRepository, clientRepo *repository.ClientRepository,
+	visit *model.Visit, schedule *model.Schedule) (*model.VisitBilling, error) {
+	var payer *string
+	if schedule.ClientID != uuid.Nil {
+		client, err := clientRepo.GetClientByID(ctx, schedule.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		payer = client.Payer
+	}
+
+	var rate *model.BillingRate
+	if payer != nil && *payer != "" && schedule.ServiceCode != nil && *schedule.ServiceCode != "" {
+		// The service date is the day the visit started in the schedule's time zone
+		serviceDate := visit.StartTime.In(scheduleZone(*schedule))
+		var err error
+		rate, err = billingRepo.FindBillingRate(ctx, *payer, *schedule.ServiceCode, schedule.CaregiverID, serviceDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	billing := model.CalculateVisitBilling(visit, schedule, payer, rate, time.Now())
+	if err := billingRepo.SaveVisitBilling(ctx, &billing); err != nil {
+		return nil, err
+	}
+
+	return &billing, nil
+}
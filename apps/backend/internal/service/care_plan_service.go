@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
+)
+
+type CarePlanService struct {
+	carePlanRepo *repository.CarePlanRepository
+	clientRepo   *repository.ClientRepository
+}
+
+func NewCarePlanService(carePlanRepo *repository.CarePlanRepository, clientRepo *repository.ClientRepository) *CarePlanService {
+	return &CarePlanService{
+		carePlanRepo: carePlanRepo,
+		clientRepo:   clientRepo,
+	}
+}
+
+// Get all care plans for a client
+func (s *CarePlanService) GetCarePlansByClient(ctx context.Context, clientID uuid.UUID) ([]model.CarePlan, error) {
+	if _, err := s.clientRepo.GetClientByID(ctx, clientID); err != nil {
+		return nil, err
+	}
+
+	return s.carePlanRepo.GetCarePlansByClient(ctx, clientID)
+}
+
+// Get the client's active care plan
+func (s *CarePlanService) GetActiveCarePlan(ctx context.Context, clientID uuid.UUID) (*model.CarePlan, error) {
+	if _, err := s.clientRepo.GetClientByID(ctx, clientID); err != nil {
+		return nil, err
+	}
+
+	plan, err := s.carePlanRepo.GetActiveCarePlan(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan == nil {
+		return nil, errs.NewNotFoundError("Client has no active care plan", true, nil)
+	}
+
+	return plan, nil
+}
+
+// Get care plan by ID
+func (s *CarePlanService) GetCarePlanByID(ctx context.Context, id uuid.UUID) (*model.CarePlan, error) {
+	return s.carePlanRepo.GetCarePlanByID(ctx, id)
+}
+
+// Create a care plan for a client; it replaces the client's active plan
+func (s *CarePlanService) CreateCarePlan(ctx context.Context, create model.CarePlanCreate) (*model.CarePlan, error) {
+	if _, err := s.clientRepo.GetClientByID(ctx, create.ClientID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	plan := &model.CarePlan{
+		Base: model.Base{
+			BaseWithId: model.BaseWithId{
+				ID: uuid.New(),
+			},
+			BaseWithCreatedAt: model.BaseWithCreatedAt{
+				CreatedAt: now,
+			},
+			BaseWithUpdatedAt: model.BaseWithUpdatedAt{
+				UpdatedAt: now,
+			},
+		},
+		ClientID: create.ClientID,
+		Name:     create.Name,
+		Tasks:    make([]model.CarePlanTask, len(create.Tasks)),
+	}
+
+	// Templates keep the order they were submitted in
+	for i, task := range create.Tasks {
+		plan.Tasks[i] = model.CarePlanTask{
+			ID:          uuid.New(),
+			CarePlanID:  plan.ID,
+			Position:    i + 1,
+			Name:        task.Name,
+			Description: task.Description,
+			IsRequired:  task.IsRequired,
+			CreatedAt:   now,
+		}
+	}
+
+	if err := s.carePlanRepo.CreateCarePlan(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to create care plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+// Make a care plan the client's active plan
+func (s *CarePlanService) ActivateCarePlan(ctx context.Context, id uuid.UUID) (*model.CarePlan, error) {
+	plan, err := s.carePlanRepo.GetCarePlanByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.IsActive {
+		return plan, nil
+	}
+
+	if err := s.carePlanRepo.ActivateCarePlan(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to activate care plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+// Deactivate a care plan
+func (s *CarePlanService) DeactivateCarePlan(ctx context.Context, id uuid.UUID) (*model.CarePlan, error) {
+	plan, err := s.carePlanRepo.GetCarePlanByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !plan.IsActive {
+		return plan, nil
+	}
+
+	if err := s.carePlanRepo.DeactivateCarePlan(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to deactivate care plan: %w", err)
+	}
+
+	plan.IsActive = false
+	return plan, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
+)
+
+type CaregiverService struct {
+	caregiverRepo *repository.CaregiverRepository
+	scheduleRepo  *repository.ScheduleRepository
+}
+
+func NewCaregiverService(caregiverRepo *repository.CaregiverRepository, scheduleRepo *repository.ScheduleRepository) *CaregiverService {
+	return &CaregiverService{
+		caregiverRepo: caregiverRepo,
+		scheduleRepo:  scheduleRepo,
+	}
+}
+
+// Get all caregivers with pagination
+func (s *CaregiverService) GetCaregivers(ctx context.Context, page, limit int) (*model.PaginatedResponse[model.Caregiver], error) {
+	return s.caregiverRepo.GetCaregivers(ctx, page, limit)
+}
+
+// Get caregiver by ID
+func (s *CaregiverService) GetCaregiverByID(ctx context.Context, id uuid.UUID) (*model.Caregiver, error) {
+	return s.caregiverRepo.GetCaregiverByID(ctx, id)
+}
+
+// Create a new caregiver linked to a Clerk user
+func (s *CaregiverService) CreateCaregiver(ctx context.Context, create model.CaregiverCreate) (*model.Caregiver, error) {
+	caregiver := &model.Caregiver{
+		Base: model.Base{
+			BaseWithId: model.BaseWithId{
+				ID: uuid.New(),
+			},
+			BaseWithCreatedAt: model.BaseWithCreatedAt{
+				CreatedAt: time.Now(),
+			},
+			BaseWithUpdatedAt: model.BaseWithUpdatedAt{
+				UpdatedAt: time.Now(),
+			},
+		},
+		UserID:    create.UserID,
+		FirstName: create.FirstName,
+		LastName:  create.LastName,
+		Email:     create.Email,
+		Phone:     create.Phone,
+		IsActive:  true,
+	}
+
+	if err := s.caregiverRepo.CreateCaregiver(ctx, caregiver); err != nil {
+		return nil, fmt.Errorf("failed to create caregiver: %w", err)
+	}
+
+	return caregiver, nil
+}
+
+// Assign a caregiver to an unassigned schedule
+func (s *CaregiverService) AssignCaregiver(ctx context.Context, scheduleID, caregiverID uuid.UUID) (*model.Schedule, error) {
+	if _, err := s.assignableSchedule(ctx, scheduleID, caregiverID); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.AssignCaregiver(ctx, scheduleID, caregiverID); err != nil {
+		return nil, err
+	}
+
+	return s.scheduleRepo.GetScheduleByID(ctx, scheduleID)
+}
+
+// Move a schedule to a different caregiver, whether or not it was assigned before
+func (s *CaregiverService) ReassignCaregiver(ctx context.Context, scheduleID, caregiverID uuid.UUID) (*model.Schedule, error) {
+	schedule, err := s.assignableSchedule(ctx, scheduleID, caregiverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.CaregiverID != nil && *schedule.CaregiverID == caregiverID {
+		return schedule, nil
+	}
+
+	if err := s.scheduleRepo.ReassignCaregiver(ctx, scheduleID, &caregiverID, schedule.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return s.scheduleRepo.GetScheduleByID(ctx, scheduleID)
+}
+
+// Get the shifts assigned to the caregiver linked to a Clerk user
+func (s *CaregiverService) GetMySchedules(ctx context.Context, userID string, page, limit int, status string) (*model.PaginatedResponse[model.Schedule], error) {
+	if userID == "" {
+		return nil, errs.NewUnauthorizedError("Unauthorized", false)
+	}
+
+	caregiver, err := s.caregiverRepo.GetCaregiverByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scheduleRepo.GetSchedulesByCaregiver(ctx, caregiver.ID, page, limit, status)
+}
+
+// assignableSchedule loads a schedule and checks that it can take the given caregiver.
+// Only schedules that have not started can change hands.
+func (s *CaregiverService) assignableSchedule(ctx context.Context, scheduleID, caregiverID uuid.UUID) (*model.Schedule, error) {
+	schedule, err := s.scheduleRepo.GetScheduleByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.Status != model.ScheduleStatusUpcoming {
+		code := "SCHEDULE_NOT_ASSIGNABLE"
+		message := fmt.Sprintf("Cannot assign a caregiver to a %s schedule", schedule.Status)
+		return nil, errs.NewConflictError(message, true, &code)
+	}
+
+	caregiver, err := s.caregiverRepo.GetCaregiverByID(ctx, caregiverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !caregiver.IsActive {
+		code := "CAREGIVER_INACTIVE"
+		return nil, errs.NewBadRequestError("Caregiver is not active", true, &code, nil, nil)
+	}
+
+	return schedule, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
+)
+
+type ClientService struct {
+	clientRepo *repository.ClientRepository
+}
+
+func NewClientService(clientRepo *repository.ClientRepository) *ClientService {
+	return &ClientService{
+		clientRepo: clientRepo,
+	}
+}
+
+// Get all clients with pagination and filtering
+func (s *ClientService) GetClients(ctx context.Context, page, limit int, status, search string) (*model.PaginatedResponse[model.Client], error) {
+	return s.clientRepo.GetClients(ctx, page, limit, status, search)
+}
+
+// Get client by ID
+func (s *ClientService) GetClientByID(ctx context.Context, id uuid.UUID) (*model.Client, error) {
+	return s.clientRepo.GetClientByID(ctx, id)
+}
+
+// Create a new client
+func (s *ClientService) CreateClient(ctx context.Context, create model.ClientCreate) (*model.Client, error) {
+	client := &model.Client{
+		Base: model.Base{
+			BaseWithId: model.BaseWithId{
+				ID: uuid.New(),
+			},
+			BaseWithCreatedAt: model.BaseWithCreatedAt{
+				CreatedAt: time.Now(),
+			},
+			BaseWithUpdatedAt: model.BaseWithUpdatedAt{
+				UpdatedAt: time.Now(),
+			},
+		},
+		Status: model.ClientStatusActive,
+	}
+	applyClientFields(client, create)
+
+	if err := s.clientRepo.CreateClient(ctx, client); err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return client, nil
+}
+
+// Update client
+func (s *ClientService) UpdateClient(ctx context.Context, id uuid.UUID, update model.ClientUpdate) (*model.Client, error) {
+	client, err := s.clientRepo.GetClientByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyClientFields(client, update.ClientCreate)
+	if update.Status != "" {
+		client.Status = update.Status
+	}
+
+	if err := s.clientRepo.UpdateClient(ctx, client); err != nil {
+		return nil, fmt.Errorf("failed to update client: %w", err)
+	}
+
+	return client, nil
+}
+
+// Delete client; clients with schedules are kept for the visit record and must be deactivated instead
+func (s *ClientService) DeleteClient(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.clientRepo.GetClientByID(ctx, id); err != nil {
+		return err
+	}
+
+	hasSchedules, err := s.clientRepo.ClientHasSchedules(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check client schedules: %w", err)
+	}
+
+	if hasSchedules {
+		code := "CLIENT_HAS_SCHEDULES"
+		return errs.NewConflictError("Client has schedules and cannot be deleted; set the client to inactive instead", true, &code)
+	}
+
+	return s.clientRepo.DeleteClient(ctx, id)
+}
+
+func applyClientFields(client *model.Client, fields model.ClientCreate) {
+	client.FirstName = fields.FirstName
+	client.LastName = fields.LastName
+	client.DateOfBirth = fields.DateOfBirth
+	client.Gender = fields.Gender
+	client.MedicaidID = fields.MedicaidID
+	client.Payer = fields.Payer
+	client.AddressLine1 = fields.AddressLine1
+	client.AddressLine2 = fields.AddressLine2
+	client.City = fields.City
+	client.State = fields.State
+	client.PostalCode = fields.PostalCode
+	client.Latitude = fields.Latitude
+	client.Longitude = fields.Longitude
+	client.Phone = fields.Phone
+	client.EmergencyContacts = fields.EmergencyContacts
+
+	if client.EmergencyContacts == nil {
+		client.EmergencyContacts = make([]model.EmergencyContact, 0)
+	}
+}
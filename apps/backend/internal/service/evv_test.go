@@ -47,14 +47,14 @@ func (m *MockScheduleRepository) UpdateSchedule(ctx context.Context, schedule *m
 	return args.Error(0)
 }
 
-func (m *MockScheduleRepository) UpdateScheduleStatus(ctx context.Context, id uuid.UUID, status string) error {
-	args := m.Called(ctx, id, status)
+func (m *MockScheduleRepository) UpdateScheduleStatus(ctx context.Context, id uuid.UUID, from, to string) error {
+	args := m.Called(ctx, id, from, to)
 	return args.Error(0)
 }
 
-func (m *MockScheduleRepository) GetScheduleStats(ctx context.Context) (*model.TaskStats, error) {
+func (m *MockScheduleRepository) GetScheduleStats(ctx context.Context) (*model.ScheduleStats, error) {
 	args := m.Called(ctx)
-	return args.Get(0).(*model.TaskStats), args.Error(1)
+	return args.Get(0).(*model.ScheduleStats), args.Error(1)
 }
 
 func (m *MockScheduleRepository) SearchSchedules(ctx context.Context, queryStr string, page, limit int) (*model.PaginatedResponse[model.Schedule], error) {
@@ -86,8 +86,8 @@ func (m *MockVisitRepository) UpdateVisit(ctx context.Context, visit *model.Visi
 	return args.Error(0)
 }
 
-func (m *MockVisitRepository) UpdateVisitStatus(ctx context.Context, visitID uuid.UUID, status string) error {
-	args := m.Called(ctx, visitID, status)
+func (m *MockVisitRepository) UpdateVisitStatus(ctx context.Context, visitID uuid.UUID, from, to string) error {
+	args := m.Called(ctx, visitID, from, to)
 	return args.Error(0)
 }
 
@@ -145,8 +145,8 @@ func (m *MockTaskRepository) UpdateTask(ctx context.Context, task *model.Task) e
 	return args.Error(0)
 }
 
-func (m *MockTaskRepository) UpdateTaskStatus(ctx context.Context, taskID uuid.UUID, status string, reason *string) (*model.Task, error) {
-	args := m.Called(ctx, taskID, status, reason)
+func (m *MockTaskRepository) UpdateTaskStatus(ctx context.Context, taskID uuid.UUID, from, to string, reason *string) (*model.Task, error) {
+	args := m.Called(ctx, taskID, from, to, reason)
 	return args.Get(0).(*model.Task), args.Error(1)
 }
 
@@ -212,7 +212,9 @@ func TestScheduleService_GetSchedules(t *testing.T) {
 				UpdatedAt: time.Now(),
 			},
 			ClientName: "John Doe",
-			ShiftTime:  "09:00-17:00",
+			ScheduledStart: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+			ScheduledEnd:   time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC),
+			TimeZone:   "UTC",
 			Location:   "123 Main St",
 			Status:     "upcoming",
 		},
@@ -252,7 +254,9 @@ func TestScheduleService_GetScheduleByID(t *testing.T) {
 				UpdatedAt: time.Now(),
 			},
 			ClientName: "John Doe",
-			ShiftTime:  "09:00-17:00",
+			ScheduledStart: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+			ScheduledEnd:   time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC),
+			TimeZone:   "UTC",
 			Location:   "123 Main St",
 			Status:     "upcoming",
 		},
@@ -282,19 +286,29 @@ func TestScheduleService_CreateSchedule(t *testing.T) {
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
+		ClientID:   uuid.New(),
 		ClientName: "John Doe",
-		ShiftTime:  "09:00-17:00",
+		ScheduledStart: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+		ScheduledEnd:   time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC),
+		TimeZone:   "UTC",
 		Location:   "123 Main St",
 		Status:     "upcoming",
 	}
 
 	mockScheduleRepo.On("CreateSchedule", ctx, expectedSchedule).Return(nil)
 
-	result, err := scheduleService.CreateSchedule(ctx, "John Doe", "09:00-17:00", "123 Main St")
+	result, err := scheduleService.CreateSchedule(ctx, model.ScheduleCreate{
+		ClientID:       expectedSchedule.ClientID,
+		ScheduledStart: expectedSchedule.ScheduledStart,
+		ScheduledEnd:   expectedSchedule.ScheduledEnd,
+		TimeZone:       "UTC",
+		Location:       "123 Main St",
+	})
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedSchedule.ClientName, result.ClientName)
-	assert.Equal(t, expectedSchedule.ShiftTime, result.ShiftTime)
+	assert.Equal(t, expectedSchedule.ScheduledStart, result.ScheduledStart)
+	assert.Equal(t, expectedSchedule.ScheduledEnd, result.ScheduledEnd)
 	assert.Equal(t, expectedSchedule.Location, result.Location)
 	mockScheduleRepo.AssertExpectations(t)
 }
@@ -332,14 +346,16 @@ func TestVisitService_StartVisit(t *testing.T) {
 			UpdatedAt: time.Now(),
 		},
 		ClientName: "John Doe",
-		ShiftTime:  "09:00-17:00",
+		ScheduledStart: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+		ScheduledEnd:   time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC),
+		TimeZone:   "UTC",
 		Location:   "123 Main St",
 		Status:     "upcoming",
 	}, nil)
 
 	mockVisitRepo.On("VisitExistsForSchedule", ctx, scheduleID).Return(false, nil)
 	mockVisitRepo.On("StartVisit", ctx, scheduleID, startTime, startLat, startLong).Return(expectedVisit, nil)
-	mockScheduleRepo.On("UpdateScheduleStatus", ctx, scheduleID, "in_progress").Return(nil)
+	mockScheduleRepo.On("UpdateScheduleStatus", ctx, scheduleID, "upcoming", "in_progress").Return(nil)
 
 	result, err := visitService.StartVisit(ctx, scheduleID, startTime, startLat, startLong)
 
@@ -397,7 +413,7 @@ func TestVisitService_EndVisit(t *testing.T) {
 
 	mockVisitRepo.On("GetVisitByScheduleID", ctx, scheduleID).Return(existingVisit, nil)
 	mockVisitRepo.On("EndVisit", ctx, visitID, endTime, endLat, endLong).Return(expectedVisit, nil)
-	mockScheduleRepo.On("UpdateScheduleStatus", ctx, scheduleID, "completed").Return(nil)
+	mockScheduleRepo.On("UpdateScheduleStatus", ctx, scheduleID, "in_progress", "completed").Return(nil)
 
 	result, err := visitService.EndVisit(ctx, scheduleID, endTime, endLat, endLong)
 
@@ -467,8 +483,17 @@ func TestTaskService_UpdateTaskStatus(t *testing.T) {
 		CompletedAt: &completedAt,
 	}
 
-	mockTaskRepo.On("GetTaskByID", ctx, taskID).Return(expectedTask, nil)
-	mockTaskRepo.On("UpdateTaskStatus", ctx, taskID, status, (*string)(nil)).Return(expectedTask, nil)
+	mockTaskRepo.On("GetTaskByID", ctx, taskID).Return(&model.Task{
+		Base: model.Base{
+			ID:        taskID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		ScheduleID: scheduleID,
+		Name:       "Medication Administration",
+		Status:     "pending",
+	}, nil)
+	mockTaskRepo.On("UpdateTaskStatus", ctx, taskID, "pending", status, (*string)(nil)).Return(expectedTask, nil)
 
 	result, err := taskService.UpdateTaskStatus(ctx, taskID, status, nil)
 
@@ -500,7 +525,7 @@ func TestTaskService_UpdateTaskStatusWithReason(t *testing.T) {
 		Name:       "Medication Administration",
 		Status:     "pending",
 	}, nil)
-	mockTaskRepo.On("UpdateTaskStatus", ctx, taskID, status, &reason).Return(&model.Task{
+	mockTaskRepo.On("UpdateTaskStatus", ctx, taskID, "pending", status, &reason).Return(&model.Task{
 		Base: model.Base{
 			ID:        taskID,
 			CreatedAt: time.Now(),
@@ -542,11 +567,4 @@ func TestValidationFunctions(t *testing.T) {
 		assert.False(t, isValidVisitStatus("invalid"))
 	})
 
-	t.Run("Valid shift time", func(t *testing.T) {
-		assert.True(t, isValidShiftTime("09:00-17:00"))
-		assert.True(t, isValidShiftTime("08:30-16:30"))
-		assert.False(t, isValidShiftTime("09:00-17"))
-		assert.False(t, isValidShiftTime("9:00-17:00"))
-		assert.False(t, isValidShiftTime("invalid-time"))
-	})
 }
\ No newline at end of file
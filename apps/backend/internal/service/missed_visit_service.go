@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
+)
+
+const (
+	// missedVisitBatchSize caps how many schedules a single scan marks as missed
+	missedVisitBatchSize  = 500
+	missedVisitTaskReason = "Visit missed"
+)
+
+type MissedVisitService struct {
+	scheduleRepo *repository.ScheduleRepository
+	uow          *repository.UnitOfWork
+	evvConfig    *config.EVVConfig
+}
+
+func NewMissedVisitService(scheduleRepo *repository.ScheduleRepository, uow *repository.UnitOfWork, evvConfig *config.EVVConfig) *MissedVisitService {
+	return &MissedVisitService{
+		scheduleRepo: scheduleRepo,
+		uow:          uow,
+		evvConfig:    evvConfig,
+	}
+}
+
+// MarkMissedVisits marks upcoming schedules with no visit past the grace period as missed
+// and closes out their pending tasks. It returns the schedules that were marked.
+func (s *MissedVisitService) MarkMissedVisits(ctx context.Context) ([]model.Schedule, error) {
+	cutoff := time.Now().Add(-s.evvConfig.MissedVisitGracePeriod)
+
+	schedules, err := s.scheduleRepo.GetOverdueSchedules(ctx, cutoff, missedVisitBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overdue schedules: %w", err)
+	}
+
+	missed := make([]model.Schedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		if err := model.ScheduleStatusMachine.Transition(schedule.Status, model.ScheduleStatusMissed); err != nil {
+			return missed, err
+		}
+
+		// A schedule is only missed together with its closed-out tasks
+		err := s.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+			if err := repos.Schedule.UpdateScheduleStatus(ctx, schedule.ID, schedule.Status, model.ScheduleStatusMissed); err != nil {
+				return err
+			}
+			if err := markPendingTasksAsNotCompleted(ctx, repos.Task, schedule.ID, missedVisitTaskReason); err != nil {
+				return fmt.Errorf("failed to close tasks: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			// The caregiver clocked in or the schedule or its tasks changed since
+			// the scan; leave it to a later scan
+			var httpErr *errs.HTTPError
+			if errors.As(err, &httpErr) && httpErr.Status == http.StatusConflict {
+				continue
+			}
+			return missed, fmt.Errorf("failed to mark schedule %s as missed: %w", schedule.ID, err)
+		}
+
+		schedule.Status = model.ScheduleStatusMissed
+		missed = append(missed, schedule)
+	}
+
+	return missed, nil
+}
@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/listquery"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/report"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+const (
+	// Rows read per query while collecting a report
+	reportPageSize = 100
+	// Reports larger than this are refused rather than built in memory
+	reportMaxRows = 10000
+	// Times are shown in the schedule's own time zone
+	reportTimeLayout = "2006-01-02 15:04"
+)
+
+// ReportService builds downloadable reports from the same service queries
+// the JSON listings use
+type ReportService struct {
+	scheduleService  *ScheduleService
+	visitService     *VisitService
+	taskService      *TaskService
+	caregiverService *CaregiverService
+	exceptionService *VisitExceptionService
+}
+
+func NewReportService(scheduleService *ScheduleService, visitService *VisitService, taskService *TaskService, caregiverService *CaregiverService, exceptionService *VisitExceptionService) *ReportService {
+	return &ReportService{
+		scheduleService:  scheduleService,
+		visitService:     visitService,
+		taskService:      taskService,
+		caregiverService: caregiverService,
+		exceptionService: exceptionService,
+	}
+}
+
+// VisitLog lists the visits clocked in between from (inclusive) and to (exclusive)
+func (s *ReportService) VisitLog(ctx context.Context, from, to time.Time) (*report.Table, error) {
+	if err := checkReportRange(from, to); err != nil {
+		return nil, err
+	}
+
+	listing, err := reportListing(model.VisitListing, map[string][]string{
+		"startTime[gte]":    {from.Format(time.RFC3339)},
+		"startTime[lt]":     {to.Format(time.RFC3339)},
+		listquery.SortParam: {"startTime:asc"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	visits, err := collectPages(func(page model.CursorPage) (*model.CursorPaginatedResponse[model.Visit], error) {
+		return s.visitService.GetVisits(ctx, page, listing)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleIDs := make([]uuid.UUID, len(visits))
+	for i, visit := range visits {
+		scheduleIDs[i] = visit.ScheduleID
+	}
+	schedules, err := s.schedulesByID(ctx, scheduleIDs)
+	if err != nil {
+		return nil, err
+	}
+	caregivers, err := s.caregiverNames(ctx, schedules)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &report.Table{
+		Title: reportTitle("Visit Log", from, to),
+		Columns: []report.Column{
+			{Name: "Visit ID"}, {Name: "Client"}, {Name: "Caregiver"}, {Name: "Service Code"}, {Name: "Time Zone"},
+			{Name: "Scheduled Start"}, {Name: "Scheduled End"}, {Name: "Clock In"}, {Name: "Clock Out"},
+			{Name: "Minutes", Numeric: true}, {Name: "Status"}, {Name: "Within Geofence"}, {Name: "Manual Entry"}, {Name: "Verified"},
+		},
+		Rows: make([][]string, 0, len(visits)),
+	}
+	for _, visit := range visits {
+		schedule := schedules[visit.ScheduleID]
+		zone := scheduleZone(schedule)
+		table.Rows = append(table.Rows, []string{
+			visit.ID.String(),
+			schedule.ClientName,
+			caregivers(schedule.CaregiverID),
+			optional(schedule.ServiceCode),
+			zone.String(),
+			formatReportTime(&schedule.ScheduledStart, zone),
+			formatReportTime(&schedule.ScheduledEnd, zone),
+			formatReportTime(&visit.StartTime, zone),
+			formatReportTime(visit.EndTime, zone),
+			optionalInt(visit.DurationMinutes),
+			visit.Status,
+			optionalBool(visit.WithinGeofence),
+			yesNo(visit.IsManualEntry),
+			yesNo(visit.IsVerified),
+		})
+	}
+
+	return table, nil
+}
+
+// TaskCompletion lists the tasks of the schedules starting between from
+// (inclusive) and to (exclusive), by schedule and then in care plan order
+func (s *ReportService) TaskCompletion(ctx context.Context, from, to time.Time) (*report.Table, error) {
+	if err := checkReportRange(from, to); err != nil {
+		return nil, err
+	}
+
+	listing, err := reportListing(model.TaskListing, map[string][]string{
+		"scheduledStart[gte]": {from.Format(time.RFC3339)},
+		"scheduledStart[lt]":  {to.Format(time.RFC3339)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := collectPages(func(page model.CursorPage) (*model.CursorPaginatedResponse[model.Task], error) {
+		return s.taskService.GetTasks(ctx, page, listing)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleIDs := make([]uuid.UUID, len(tasks))
+	for i, task := range tasks {
+		scheduleIDs[i] = task.ScheduleID
+	}
+	schedules, err := s.schedulesByID(ctx, scheduleIDs)
+	if err != nil {
+		return nil, err
+	}
+	caregivers, err := s.caregiverNames(ctx, schedules)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := schedules[tasks[i].ScheduleID], schedules[tasks[j].ScheduleID]
+		if !a.ScheduledStart.Equal(b.ScheduledStart) {
+			return a.ScheduledStart.Before(b.ScheduledStart)
+		}
+		if a.ID != b.ID {
+			return a.ID.String() < b.ID.String()
+		}
+		return tasks[i].SortOrder < tasks[j].SortOrder
+	})
+
+	table := &report.Table{
+		Title: reportTitle("Task Completion", from, to),
+		Columns: []report.Column{
+			{Name: "Schedule ID"}, {Name: "Client"}, {Name: "Caregiver"}, {Name: "Time Zone"}, {Name: "Scheduled Start"},
+			{Name: "Task"}, {Name: "Required"}, {Name: "Status"}, {Name: "Reason"}, {Name: "Completed At"},
+		},
+		Rows: make([][]string, 0, len(tasks)),
+	}
+	for _, task := range tasks {
+		schedule := schedules[task.ScheduleID]
+		zone := scheduleZone(schedule)
+		table.Rows = append(table.Rows, []string{
+			schedule.ID.String(),
+			schedule.ClientName,
+			caregivers(schedule.CaregiverID),
+			zone.String(),
+			formatReportTime(&schedule.ScheduledStart, zone),
+			task.Name,
+			yesNo(task.IsRequired),
+			task.Status,
+			optional(task.Reason),
+			formatReportTime(task.CompletedAt, zone),
+		})
+	}
+
+	return table, nil
+}
+
+// Exceptions lists the visit exceptions detected between from (inclusive)
+// and to (exclusive), in any status
+func (s *ReportService) Exceptions(ctx context.Context, from, to time.Time) (*report.Table, error) {
+	if err := checkReportRange(from, to); err != nil {
+		return nil, err
+	}
+
+	filter := model.VisitExceptionFilter{From: &from, To: &to}
+	exceptions := make([]model.VisitException, 0)
+	for page := 1; ; page++ {
+		response, err := s.exceptionService.GetVisitExceptions(ctx, filter, page, reportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		if response.Total > reportMaxRows {
+			return nil, reportTooLarge()
+		}
+		exceptions = append(exceptions, response.Data...)
+		if page >= response.TotalPages {
+			break
+		}
+	}
+
+	visitIDs := make([]uuid.UUID, len(exceptions))
+	for i, exception := range exceptions {
+		visitIDs[i] = exception.VisitID
+	}
+	visits, err := byID(visitIDs, func(ids []string) ([]model.Visit, error) {
+		return listByID(ctx, ids, model.VisitListing, s.visitService.GetVisits)
+	}, func(v model.Visit) uuid.UUID { return v.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleIDs := make([]uuid.UUID, 0, len(visits))
+	for _, visit := range visits {
+		scheduleIDs = append(scheduleIDs, visit.ScheduleID)
+	}
+	schedules, err := s.schedulesByID(ctx, scheduleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &report.Table{
+		Title: reportTitle("Visit Exceptions", from, to),
+		Columns: []report.Column{
+			{Name: "Exception ID"}, {Name: "Visit ID"}, {Name: "Client"}, {Name: "Time Zone"}, {Name: "Detected At"},
+			{Name: "Type"}, {Name: "Detail"}, {Name: "Status"}, {Name: "Reason Code"}, {Name: "Explanation"},
+			{Name: "Reviewed By"}, {Name: "Reviewed At"}, {Name: "Review Comment"},
+		},
+		Rows: make([][]string, 0, len(exceptions)),
+	}
+	for _, exception := range exceptions {
+		schedule := schedules[visits[exception.VisitID].ScheduleID]
+		zone := scheduleZone(schedule)
+		table.Rows = append(table.Rows, []string{
+			exception.ID.String(),
+			exception.VisitID.String(),
+			schedule.ClientName,
+			zone.String(),
+			formatReportTime(&exception.CreatedAt, zone),
+			exception.Type,
+			exception.Detail,
+			exception.Status,
+			optional(exception.ReasonCode),
+			optional(exception.Explanation),
+			optional(exception.ReviewedBy),
+			formatReportTime(exception.ReviewedAt, zone),
+			optional(exception.ReviewComment),
+		})
+	}
+
+	return table, nil
+}
+
+// schedulesByID looks up the schedules of report rows through the schedule listing
+func (s *ReportService) schedulesByID(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]model.Schedule, error) {
+	return byID(ids, func(ids []string) ([]model.Schedule, error) {
+		return listByID(ctx, ids, model.ScheduleListing, s.scheduleService.GetSchedules)
+	}, func(schedule model.Schedule) uuid.UUID { return schedule.ID })
+}
+
+// listByID reads the rows of a listing with the given IDs
+func listByID[T any](ctx context.Context, ids []string, schema *listquery.Schema[T],
+	list func(context.Context, model.CursorPage, *listquery.Query[T]) (*model.CursorPaginatedResponse[T], error),
+) ([]T, error) {
+	listing, err := reportListing(schema, map[string][]string{"id[in]": {strings.Join(ids, ",")}})
+	if err != nil {
+		return nil, err
+	}
+	response, err := list(ctx, model.CursorPage{Limit: len(ids)}, listing)
+	if err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// caregiverNames reads the caregivers of the schedules once and returns a
+// lookup of their names. Unassigned schedules and caregivers that no longer
+// exist are blank.
+func (s *ReportService) caregiverNames(ctx context.Context, schedules map[uuid.UUID]model.Schedule) (func(id *uuid.UUID) string, error) {
+	names := make(map[uuid.UUID]string)
+	for _, schedule := range schedules {
+		if schedule.CaregiverID == nil {
+			continue
+		}
+		if _, ok := names[*schedule.CaregiverID]; ok {
+			continue
+		}
+
+		caregiver, err := s.caregiverService.GetCaregiverByID(ctx, *schedule.CaregiverID)
+		var httpErr *errs.HTTPError
+		if err != nil && !(errors.As(err, &httpErr) && httpErr.Status == http.StatusNotFound) {
+			return nil, err
+		}
+
+		name := ""
+		if caregiver != nil {
+			name = strings.TrimSpace(caregiver.FirstName + " " + caregiver.LastName)
+		}
+		names[*schedule.CaregiverID] = name
+	}
+
+	return func(id *uuid.UUID) string {
+		if id == nil {
+			return ""
+		}
+		return names[*id]
+	}, nil
+}
+
+// byID reads rows by their distinct IDs in batches of a page
+func byID[T any](ids []uuid.UUID, read func(ids []string) ([]T, error), key func(T) uuid.UUID) (map[uuid.UUID]T, error) {
+	rows := make(map[uuid.UUID]T)
+	pending := make([]string, 0, reportPageSize)
+	seen := make(map[uuid.UUID]bool)
+
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		batch, err := read(pending)
+		if err != nil {
+			return err
+		}
+		for _, row := range batch {
+			rows[key(row)] = row
+		}
+		pending = pending[:0]
+		return nil
+	}
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		pending = append(pending, id.String())
+		if len(pending) == reportPageSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// collectPages reads every page of a cursor-paginated listing
+func collectPages[T any](list func(page model.CursorPage) (*model.CursorPaginatedResponse[T], error)) ([]T, error) {
+	rows := make([]T, 0)
+	page := model.CursorPage{Limit: reportPageSize}
+	for {
+		response, err := list(page)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, response.Data...)
+		if len(rows) > reportMaxRows {
+			return nil, reportTooLarge()
+		}
+		if response.NextCursor == nil {
+			return rows, nil
+		}
+
+		cursor, err := model.DecodeCursor(*response.NextCursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read next page: %w", err)
+		}
+		page.Cursor = cursor
+	}
+}
+
+func reportListing[T any](schema *listquery.Schema[T], values map[string][]string) (*listquery.Query[T], error) {
+	listing, fieldErrors := listquery.Parse(values, schema)
+	if fieldErrors != nil {
+		return nil, errs.NewBadRequestError("Invalid report query", true, nil, fieldErrors, nil)
+	}
+	return listing, nil
+}
+
+func checkReportRange(from, to time.Time) error {
+	if !to.After(from) {
+		return errs.NewBadRequestError("to must be after from", true, nil, nil, nil)
+	}
+	return nil
+}
+
+func reportTooLarge() error {
+	code := "REPORT_TOO_LARGE"
+	return errs.NewUnprocessableEntityError(fmt.Sprintf("Report has more than %d rows; choose a shorter date range", reportMaxRows), true, &code)
+}
+
+func reportTitle(name string, from, to time.Time) string {
+	return fmt.Sprintf("%s: %s to %s", name, from.Format(time.RFC3339), to.Format(time.RFC3339))
+}
+
+// scheduleZone returns the time zone of a schedule, or UTC when it is unknown
+func scheduleZone(schedule model.Schedule) *time.Location {
+	if zone, err := time.LoadLocation(schedule.TimeZone); err == nil && schedule.TimeZone != "" {
+		return zone
+	}
+	return time.UTC
+}
+
+func formatReportTime(t *time.Time, zone *time.Location) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.In(zone).Format(reportTimeLayout)
+}
+
+func optional(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func optionalInt(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(*i)
+}
+
+func optionalBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return yesNo(*b)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/rrule"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
+)
+
+// seriesLookaheadYears bounds the check that a new series produces any occurrences
+const seriesLookaheadYears = 5
+
+type ScheduleSeriesService struct {
+	seriesRepo    *repository.ScheduleSeriesRepository
+	scheduleRepo  *repository.ScheduleRepository
+	clientRepo    *repository.ClientRepository
+	caregiverRepo *repository.CaregiverRepository
+	carePlanRepo  *repository.CarePlanRepository
+	evvConfig     *config.EVVConfig
+}
+
+func NewScheduleSeriesService(seriesRepo *repository.ScheduleSeriesRepository, scheduleRepo *repository.ScheduleRepository, clientRepo *repository.ClientRepository,
+	caregiverRepo *repository.CaregiverRepository, carePlanRepo *repository.CarePlanRepository, evvConfig *config.EVVConfig) *ScheduleSeriesService {
+	return &ScheduleSeriesService{
+		seriesRepo:    seriesRepo,
+		scheduleRepo:  scheduleRepo,
+		clientRepo:    clientRepo,
+		caregiverRepo: caregiverRepo,
+		carePlanRepo:  carePlanRepo,
+		evvConfig:     evvConfig,
+	}
+}
+
+// Get schedule series by ID
+func (s *ScheduleSeriesService) GetScheduleSeriesByID(ctx context.Context, id uuid.UUID) (*model.ScheduleSeries, error) {
+	return s.seriesRepo.GetScheduleSeriesByID(ctx, id)
+}
+
+// Get the schedules generated from a series
+func (s *ScheduleSeriesService) GetScheduleSeriesSchedules(ctx context.Context, id uuid.UUID) ([]model.Schedule, error) {
+	if _, err := s.seriesRepo.GetScheduleSeriesByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.seriesRepo.GetScheduleSeriesSchedules(ctx, id)
+}
+
+// Create a recurring series and materialize its first occurrences
+func (s *ScheduleSeriesService) CreateScheduleSeries(ctx context.Context, create model.ScheduleSeriesCreate) (*model.ScheduleSeries, error) {
+	client, err := schedulableClient(ctx, s.clientRepo, create.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if create.CaregiverID != nil {
+		caregiver, err := s.caregiverRepo.GetCaregiverByID(ctx, *create.CaregiverID)
+		if err != nil {
+			return nil, err
+		}
+		if !caregiver.IsActive {
+			code := "CAREGIVER_INACTIVE"
+			return nil, errs.NewBadRequestError("Caregiver is not active", true, &code, nil, nil)
+		}
+	}
+
+	// The service location defaults to the client's primary address
+	if create.Location == "" {
+		create.Location = client.FormattedAddress()
+	}
+	if create.Latitude == nil && create.Longitude == nil {
+		create.Latitude = client.Latitude
+		create.Longitude = client.Longitude
+	}
+
+	now := time.Now()
+	series := &model.ScheduleSeries{
+		Base: model.Base{
+			BaseWithId: model.BaseWithId{
+				ID: uuid.New(),
+			},
+			BaseWithCreatedAt: model.BaseWithCreatedAt{
+				CreatedAt: now,
+			},
+			BaseWithUpdatedAt: model.BaseWithUpdatedAt{
+				UpdatedAt: now,
+			},
+		},
+		ClientID:             client.ID,
+		CaregiverID:          create.CaregiverID,
+		RRule:                create.RRule,
+		StartDate:            rrule.Date(create.StartDate),
+		StartTime:            create.StartTime,
+		DurationMinutes:      create.DurationMinutes,
+		TimeZone:             create.TimeZone,
+		EndDate:              create.EndDate,
+		Count:                create.Count,
+		ExceptionDates:       create.ExceptionDates,
+		Location:             create.Location,
+		ServiceCode:          create.ServiceCode,
+		Latitude:             create.Latitude,
+		Longitude:            create.Longitude,
+		GeofenceRadiusMeters: create.GeofenceRadiusMeters,
+	}
+	if series.ExceptionDates == nil {
+		series.ExceptionDates = make([]time.Time, 0)
+	}
+
+	dates, err := series.OccurrenceDates(series.StartDate, series.StartDate.AddDate(seriesLookaheadYears, 0, 0))
+	if err != nil {
+		code := "INVALID_RRULE"
+		return nil, errs.NewBadRequestError(err.Error(), true, &code, nil, nil)
+	}
+	if len(dates) == 0 {
+		code := "SERIES_HAS_NO_OCCURRENCES"
+		return nil, errs.NewBadRequestError("Recurrence produces no occurrences", true, &code, nil, nil)
+	}
+
+	// The series is only created together with its first occurrences
+	occurrences, through, err := s.occurrences(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.seriesRepo.CreateScheduleSeries(ctx, series, occurrences, through); err != nil {
+		return nil, err
+	}
+
+	return s.seriesRepo.GetScheduleSeriesByID(ctx, series.ID)
+}
+
+// Edit a series occurrence and every later one. The series is split at the
+// occurrence: the current series ends the day before and a new series carries
+// the edit forward, taking over the upcoming schedules from that date on.
+func (s *ScheduleSeriesService) UpdateFutureOccurrences(ctx context.Context, scheduleID uuid.UUID, update model.ScheduleUpdate) (*model.ScheduleSeries, error) {
+	schedule, err := s.scheduleRepo.GetScheduleByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.SeriesID == nil || schedule.SeriesDate == nil {
+		code := "SCHEDULE_NOT_IN_SERIES"
+		return nil, errs.NewBadRequestError("Schedule is not part of a recurring series", true, &code, nil, nil)
+	}
+
+	series, err := s.seriesRepo.GetScheduleSeriesByID(ctx, *schedule.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := series.Split(*schedule.SeriesDate, time.Now())
+	if err != nil {
+		return nil, errs.NewBadRequestError(err.Error(), true, nil, nil, nil)
+	}
+
+	if update.ClientID != nil && *update.ClientID != next.ClientID {
+		client, err := schedulableClient(ctx, s.clientRepo, *update.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		next.ClientID = client.ID
+	}
+	if update.TimeZone != nil {
+		next.TimeZone = *update.TimeZone
+	}
+	if update.Location != nil {
+		next.Location = *update.Location
+	}
+	if update.ServiceCode != nil {
+		next.ServiceCode = update.ServiceCode
+	}
+	if update.ScheduledStart != nil && update.ScheduledEnd != nil {
+		if err := applySeriesShiftWindow(next, *schedule.SeriesDate, *update.ScheduledStart, *update.ScheduledEnd); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.seriesRepo.SplitScheduleSeries(ctx, series, next, schedule); err != nil {
+		return nil, err
+	}
+
+	return s.seriesRepo.GetScheduleSeriesByID(ctx, next.ID)
+}
+
+// MaterializeScheduleSeries creates the schedules of every series up to the
+// configured number of days ahead. It returns the number of schedules created.
+func (s *ScheduleSeriesService) MaterializeScheduleSeries(ctx context.Context) (int, error) {
+	seriesList, err := s.seriesRepo.GetScheduleSeriesToMaterialize(ctx, s.evvConfig.SeriesMaterializeDays)
+	if err != nil {
+		return 0, err
+	}
+
+	// One broken series should not hold back the others
+	created := 0
+	var errList []error
+	for i := range seriesList {
+		count, err := s.materialize(ctx, &seriesList[i])
+		if err != nil {
+			errList = append(errList, fmt.Errorf("series %s: %w", seriesList[i].ID, err))
+			continue
+		}
+		created += count
+	}
+
+	return created, errors.Join(errList...)
+}
+
+// materialize creates the series' schedules from today, or where it left off,
+// through the materialization horizon
+func (s *ScheduleSeriesService) materialize(ctx context.Context, series *model.ScheduleSeries) (int, error) {
+	occurrences, through, err := s.occurrences(ctx, series)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.seriesRepo.MaterializeOccurrences(ctx, series.ID, occurrences, through)
+}
+
+// occurrences builds the series' schedules and tasks still to be created, and
+// the date through which they cover the series
+func (s *ScheduleSeriesService) occurrences(ctx context.Context, series *model.ScheduleSeries) ([]model.ScheduleWithTasks, time.Time, error) {
+	loc, err := time.LoadLocation(series.TimeZone)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid series time zone: %w", err)
+	}
+
+	now := time.Now()
+	today := rrule.Date(now.In(loc))
+	through := today.AddDate(0, 0, s.evvConfig.SeriesMaterializeDays)
+
+	from := series.StartDate
+	if from.Before(today) {
+		from = today
+	}
+	if series.MaterializedThrough != nil && !series.MaterializedThrough.Before(from) {
+		from = series.MaterializedThrough.AddDate(0, 0, 1)
+	}
+
+	dates, err := series.OccurrenceDates(from, through)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	carePlan, err := s.carePlanRepo.GetActiveCarePlan(ctx, series.ClientID)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to get care plan: %w", err)
+	}
+
+	occurrences := make([]model.ScheduleWithTasks, 0, len(dates))
+	for _, date := range dates {
+		schedule, err := series.NewSchedule(date, now)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		tasks := make([]model.Task, 0)
+		if carePlan != nil {
+			tasks = carePlan.NewTasks(schedule.ID, now)
+		}
+
+		occurrences = append(occurrences, model.ScheduleWithTasks{Schedule: schedule, Tasks: tasks})
+	}
+
+	return occurrences, through, nil
+}
+
+// applySeriesShiftWindow sets a series' local start time and duration from an
+// edited occurrence. The shift may move within its day but not to another day,
+// since that would change the recurrence itself.
+func applySeriesShiftWindow(series *model.ScheduleSeries, occurrenceDate, start, end time.Time) error {
+	if end.Sub(start) < time.Minute {
+		return errs.NewBadRequestError("Scheduled end must be after scheduled start", true, nil, nil, nil)
+	}
+
+	loc, err := time.LoadLocation(series.TimeZone)
+	if err != nil {
+		return fmt.Errorf("invalid series time zone: %w", err)
+	}
+
+	localStart := start.In(loc)
+	if !rrule.Date(localStart).Equal(rrule.Date(occurrenceDate)) {
+		code := "SERIES_OCCURRENCE_DATE_CHANGED"
+		return errs.NewBadRequestError("Editing future occurrences cannot move the shift to another day", true, &code, nil, nil)
+	}
+
+	series.StartTime = localStart.Format(model.SeriesTimeLayout)
+	series.DurationMinutes = int(end.Sub(start).Minutes())
+	return nil
+}
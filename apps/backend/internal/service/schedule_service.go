@@ -6,224 +6,61 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/listquery"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
 )
 
-// Helper function to create string pointer
-func ptr(s string) *string {
-	return &s
-}
-
-// Helper function to create time pointer
-func ptrTime(t time.Time) *time.Time {
-	return &t
-}
-
 type ScheduleService struct {
 	scheduleRepo *repository.ScheduleRepository
 	visitRepo    *repository.VisitRepository
 	taskRepo     *repository.TaskRepository
+	clientRepo   *repository.ClientRepository
+	carePlanRepo *repository.CarePlanRepository
 }
 
-func NewScheduleService(scheduleRepo *repository.ScheduleRepository, visitRepo *repository.VisitRepository, taskRepo *repository.TaskRepository) *ScheduleService {
+func NewScheduleService(scheduleRepo *repository.ScheduleRepository, visitRepo *repository.VisitRepository, taskRepo *repository.TaskRepository, clientRepo *repository.ClientRepository, carePlanRepo *repository.CarePlanRepository) *ScheduleService {
 	return &ScheduleService{
 		scheduleRepo: scheduleRepo,
 		visitRepo:    visitRepo,
 		taskRepo:     taskRepo,
+		clientRepo:   clientRepo,
+		carePlanRepo: carePlanRepo,
 	}
 }
 
-// Get all schedules with pagination and filtering
-func (s *ScheduleService) GetSchedules(ctx context.Context, page, limit int, status string) (*model.PaginatedResponse[model.Schedule], error) {
-	result, err := s.scheduleRepo.GetSchedules(ctx, page, limit, status)
-	if err != nil {
-		// Return mock data if database fails
-		return s.getMockSchedulesResponse(page, limit)
-	}
-	return result, nil
-}
-
-// Mock response for schedule service
-func (s *ScheduleService) getMockSchedulesResponse(page, limit int) (*model.PaginatedResponse[model.Schedule], error) {
-	// Generate mock data similar to repository structure
-	mockSchedules := []model.Schedule{
-		{
-			Base: model.Base{
-				BaseWithId: model.BaseWithId{
-					ID: uuid.MustParse("1"),
-				},
-				BaseWithCreatedAt: model.BaseWithCreatedAt{
-					CreatedAt: time.Now(),
-				},
-				BaseWithUpdatedAt: model.BaseWithUpdatedAt{
-					UpdatedAt: time.Now(),
-				},
-			},
-			ClientName: "John Smith",
-			ShiftTime:  "09:00 - 12:00",
-			Location:   "123 Main St, Anytown",
-			Status:     "upcoming",
-		},
-		{
-			Base: model.Base{
-				BaseWithId: model.BaseWithId{
-					ID: uuid.MustParse("2"),
-				},
-				BaseWithCreatedAt: model.BaseWithCreatedAt{
-					CreatedAt: time.Now(),
-				},
-				BaseWithUpdatedAt: model.BaseWithUpdatedAt{
-					UpdatedAt: time.Now(),
-				},
-			},
-			ClientName: "Jane Doe",
-			ShiftTime:  "10:00 - 14:00",
-			Location:   "456 Oak Ave, Somewhere",
-			Status:     "in_progress",
-		},
-	}
-
-	// Apply pagination
-	start := (page - 1) * limit
-	if start > len(mockSchedules) {
-		start = 0
-	}
-	end := start + limit
-	if end > len(mockSchedules) {
-		end = len(mockSchedules)
-	}
-
-	paginatedData := mockSchedules[start:end]
-
-	totalPages := (len(mockSchedules) + limit - 1) / limit
-	return &model.PaginatedResponse[model.Schedule]{
-		Data:       paginatedData,
-		Page:       page,
-		Limit:      limit,
-		Total:      len(mockSchedules),
-		TotalPages: totalPages,
-	}, nil
+// Get a page of schedules matching listing's filters, in its sort
+func (s *ScheduleService) GetSchedules(ctx context.Context, page model.CursorPage, listing *listquery.Query[model.Schedule]) (*model.CursorPaginatedResponse[model.Schedule], error) {
+	return s.scheduleRepo.GetSchedules(ctx, page, listing)
 }
 
 // Get today's schedules
 func (s *ScheduleService) GetTodaySchedules(ctx context.Context) ([]model.Schedule, error) {
-	schedules, err := s.scheduleRepo.GetTodaySchedules(ctx)
-	if err != nil {
-		// Return mock data if database fails
-		return s.getMockTodaySchedulesResponse()
-	}
-
-	return schedules, nil
-}
-
-// Mock response for today's schedules
-func (s *ScheduleService) getMockTodaySchedulesResponse() ([]model.Schedule, error) {
-	mockSchedules := []model.Schedule{
-		{
-			Base: model.Base{
-				BaseWithId: model.BaseWithId{
-					ID: uuid.MustParse("2"),
-				},
-				BaseWithCreatedAt: model.BaseWithCreatedAt{
-					CreatedAt: time.Now(),
-				},
-				BaseWithUpdatedAt: model.BaseWithUpdatedAt{
-					UpdatedAt: time.Now(),
-				},
-			},
-			ClientName: "Jane Doe",
-			ShiftTime:  "10:00 - 14:00",
-			Location:   "456 Oak Ave, Somewhere",
-			Status:     "in_progress",
-		},
-	}
-
-	return mockSchedules, nil
+	return s.scheduleRepo.GetTodaySchedules(ctx)
 }
 
 // Get schedule by ID with full details
 func (s *ScheduleService) GetScheduleByID(ctx context.Context, id uuid.UUID) (*model.ScheduleWithTasks, error) {
-	schedule, err := s.scheduleRepo.GetScheduleWithDetails(ctx, id)
-	if err != nil {
-		// Return mock data if database fails
-		return s.getMockScheduleByIDResponse(id)
-	}
-
-	return schedule, nil
+	return s.scheduleRepo.GetScheduleWithDetails(ctx, id)
 }
 
-// Mock response for schedule by ID
-func (s *ScheduleService) getMockScheduleByIDResponse(id uuid.UUID) (*model.ScheduleWithTasks, error) {
-	// Return mock data based on ID
-	switch id.String() {
-	case "1":
-		tasks := []model.Task{
-			{
-				Base: model.Base{
-					BaseWithId: model.BaseWithId{
-						ID: uuid.MustParse("task1"),
-					},
-					BaseWithCreatedAt: model.BaseWithCreatedAt{
-						CreatedAt: time.Now(),
-					},
-					BaseWithUpdatedAt: model.BaseWithUpdatedAt{
-						UpdatedAt: time.Now(),
-					},
-				},
-				ScheduleID: id,
-				Name:       "Morning Medication",
-				Description: ptr("Administer morning medication"),
-				Status:     "completed",
-				Reason:     ptr("Administered as prescribed"),
-				CompletedAt: ptrTime(time.Now()),
-			},
-			{
-				Base: model.Base{
-					BaseWithId: model.BaseWithId{
-						ID: uuid.MustParse("task2"),
-					},
-					BaseWithCreatedAt: model.BaseWithCreatedAt{
-						CreatedAt: time.Now(),
-					},
-					BaseWithUpdatedAt: model.BaseWithUpdatedAt{
-						UpdatedAt: time.Now(),
-					},
-				},
-				ScheduleID: id,
-				Name:       "Vital Signs Check",
-				Description: ptr("Check blood pressure and temperature"),
-				Status:     "pending",
-			},
-		}
+// Create a new schedule, seeded with tasks from the client's active care plan
+func (s *ScheduleService) CreateSchedule(ctx context.Context, create model.ScheduleCreate) (*model.ScheduleWithTasks, error) {
+	client, err := schedulableClient(ctx, s.clientRepo, create.ClientID)
+	if err != nil {
+		return nil, err
+	}
 
-		return &model.ScheduleWithTasks{
-			Schedule: model.Schedule{
-				Base: model.Base{
-					BaseWithId: model.BaseWithId{
-						ID: id,
-					},
-					BaseWithCreatedAt: model.BaseWithCreatedAt{
-						CreatedAt: time.Now(),
-					},
-					BaseWithUpdatedAt: model.BaseWithUpdatedAt{
-						UpdatedAt: time.Now(),
-					},
-				},
-				ClientName: "John Smith",
-				ShiftTime:  "09:00 - 12:00",
-				Location:   "123 Main St, Anytown",
-				Status:     "upcoming",
-			},
-			Tasks: tasks,
-		}, nil
-	default:
-		return nil, fmt.Errorf("schedule not found")
+	// The service location defaults to the client's primary address
+	if create.Location == "" {
+		create.Location = client.FormattedAddress()
+	}
+	if create.Latitude == nil && create.Longitude == nil {
+		create.Latitude = client.Latitude
+		create.Longitude = client.Longitude
 	}
-}
 
-// Create a new schedule
-func (s *ScheduleService) CreateSchedule(ctx context.Context, clientName, shiftTime, location string) (*model.Schedule, error) {
 	schedule := &model.Schedule{
 		Base: model.Base{
 			BaseWithId: model.BaseWithId{
@@ -236,32 +73,75 @@ func (s *ScheduleService) CreateSchedule(ctx context.Context, clientName, shiftT
 				UpdatedAt: time.Now(),
 			},
 		},
-		ClientName: clientName,
-		ShiftTime:  shiftTime,
-		Location:   location,
-		Status:     "upcoming",
+		ClientID:             client.ID,
+		ClientName:           client.FullName(),
+		ScheduledStart:       create.ScheduledStart,
+		ScheduledEnd:         create.ScheduledEnd,
+		TimeZone:             create.TimeZone,
+		Location:             create.Location,
+		ServiceCode:          create.ServiceCode,
+		Status:               model.ScheduleStatusUpcoming,
+		Latitude:             create.Latitude,
+		Longitude:            create.Longitude,
+		GeofenceRadiusMeters: create.GeofenceRadiusMeters,
+	}
+
+	carePlan, err := s.carePlanRepo.GetActiveCarePlan(ctx, client.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get care plan: %w", err)
+	}
+
+	tasks := make([]model.Task, 0)
+	if carePlan != nil {
+		tasks = carePlan.NewTasks(schedule.ID, schedule.CreatedAt)
 	}
 
-	if err := s.scheduleRepo.CreateSchedule(ctx, schedule); err != nil {
+	if err := s.scheduleRepo.CreateScheduleWithTasks(ctx, schedule, tasks); err != nil {
 		return nil, fmt.Errorf("failed to create schedule: %w", err)
 	}
 
-	return schedule, nil
+	return &model.ScheduleWithTasks{
+		Schedule: *schedule,
+		Tasks:    tasks,
+	}, nil
 }
 
-// Update schedule
-func (s *ScheduleService) UpdateSchedule(ctx context.Context, id uuid.UUID, clientName, shiftTime, location string) (*model.Schedule, error) {
+// Update a single schedule. A schedule generated from a series becomes an
+// exception, so later edits to the series leave it alone.
+func (s *ScheduleService) UpdateSchedule(ctx context.Context, id uuid.UUID, update model.ScheduleUpdate) (*model.Schedule, error) {
 	// Get existing schedule
 	schedule, err := s.scheduleRepo.GetScheduleByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get schedule: %w", err)
 	}
 
+	if update.ClientID != nil && *update.ClientID != schedule.ClientID {
+		client, err := schedulableClient(ctx, s.clientRepo, *update.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		schedule.ClientID = client.ID
+		schedule.ClientName = client.FullName()
+	}
+
 	// Update fields
-	schedule.ClientName = clientName
-	schedule.ShiftTime = shiftTime
-	schedule.Location = location
-	schedule.UpdatedAt = time.Now()
+	if update.ScheduledStart != nil && update.ScheduledEnd != nil {
+		if !update.ScheduledEnd.After(*update.ScheduledStart) {
+			return nil, errs.NewBadRequestError("Scheduled end must be after scheduled start", true, nil, nil, nil)
+		}
+		schedule.ScheduledStart = *update.ScheduledStart
+		schedule.ScheduledEnd = *update.ScheduledEnd
+	}
+	if update.TimeZone != nil {
+		schedule.TimeZone = *update.TimeZone
+	}
+	if update.Location != nil {
+		schedule.Location = *update.Location
+	}
+	if update.ServiceCode != nil {
+		schedule.ServiceCode = update.ServiceCode
+	}
+	schedule.IsSeriesException = schedule.SeriesID != nil
 
 	// Save changes
 	if err := s.scheduleRepo.UpdateSchedule(ctx, schedule); err != nil {
@@ -271,21 +151,27 @@ func (s *ScheduleService) UpdateSchedule(ctx context.Context, id uuid.UUID, clie
 	return schedule, nil
 }
 
-// Update schedule status
+// Cancel a schedule or mark it missed. A schedule only goes in progress and
+// completes through its visit, so those statuses can't be set by hand.
 func (s *ScheduleService) UpdateScheduleStatus(ctx context.Context, id uuid.UUID, status string) error {
-	// Validate status
-	if !isValidScheduleStatus(status) {
-		return fmt.Errorf("invalid schedule status: %s", status)
+	if status != model.ScheduleStatusMissed && status != model.ScheduleStatusCancelled {
+		code := "SCHEDULE_STATUS_FOLLOWS_VISIT"
+		return errs.NewBadRequestError("Schedules go in progress and complete by clocking in and out", true, &code, nil, nil)
 	}
 
-	// Check if schedule exists
-	_, err := s.scheduleRepo.GetScheduleByID(ctx, id)
+	schedule, err := s.scheduleRepo.GetScheduleByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get schedule: %w", err)
 	}
 
-	// Update status
-	if err := s.scheduleRepo.UpdateScheduleStatus(ctx, id, status); err != nil {
+	// Validate the change against the schedule lifecycle
+	if err := model.ScheduleStatusMachine.Transition(schedule.Status, status); err != nil {
+		return err
+	}
+
+	// Update status; the write only applies to the version that was checked
+	schedule.Status = status
+	if err := s.scheduleRepo.UpdateSchedule(ctx, schedule); err != nil {
 		return fmt.Errorf("failed to update schedule status: %w", err)
 	}
 
@@ -293,34 +179,27 @@ func (s *ScheduleService) UpdateScheduleStatus(ctx context.Context, id uuid.UUID
 }
 
 // Calculate schedule statistics
-func (s *ScheduleService) GetScheduleStats(ctx context.Context) (*model.TaskStats, error) {
-	stats, err := s.scheduleRepo.GetScheduleStats(ctx)
-	if err != nil {
-		// Return mock stats if database fails
-		return s.getMockScheduleStatsResponse()
-	}
-
-	return stats, nil
+func (s *ScheduleService) GetScheduleStats(ctx context.Context) (*model.ScheduleStats, error) {
+	return s.scheduleRepo.GetScheduleStats(ctx)
 }
 
-// Mock response for schedule statistics
-func (s *ScheduleService) getMockScheduleStatsResponse() (*model.TaskStats, error) {
-	return &model.TaskStats{
-		TotalTasks:       8,
-		CompletedTasks:   4,
-		PendingTasks:     3,
-		NotCompletedTasks: 1,
-	}, nil
-}
+// Search schedules by client name, location and task names
+func (s *ScheduleService) SearchSchedules(ctx context.Context, filter model.ScheduleSearchFilter, page, limit int) (*model.PaginatedResponse[model.ScheduleSearchResult], error) {
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, errs.NewBadRequestError("to must be after from", true, nil, nil, nil)
+	}
 
-// Search schedules by client name or location
-func (s *ScheduleService) SearchSchedules(ctx context.Context, queryStr string, page, limit int) (*model.PaginatedResponse[model.Schedule], error) {
-	return s.scheduleRepo.SearchSchedules(ctx, queryStr, page, limit)
+	return s.scheduleRepo.SearchSchedules(ctx, filter, page, limit)
 }
 
 // Get schedules by status with statistics
 func (s *ScheduleService) GetSchedulesByStatus(ctx context.Context, status string) ([]model.Schedule, map[string]interface{}, error) {
-	schedules, err := s.scheduleRepo.GetSchedules(ctx, 1, 100, status)
+	listing, fieldErrors := listquery.Parse(map[string][]string{"status": {status}}, model.ScheduleListing)
+	if fieldErrors != nil {
+		return nil, nil, errs.NewBadRequestError("Invalid status", true, nil, fieldErrors, nil)
+	}
+
+	schedules, err := s.scheduleRepo.GetSchedules(ctx, model.CursorPage{Limit: 100}, listing)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get schedules by status: %w", err)
 	}
@@ -331,10 +210,12 @@ func (s *ScheduleService) GetSchedulesByStatus(ctx context.Context, status strin
 	}
 
 	return schedules.Data, map[string]interface{}{
-		"total":      stats.TotalTasks,
-		"completed":  stats.CompletedTasks,
-		"pending":     stats.PendingTasks,
-		"notCompleted": stats.NotCompletedTasks,
+		"total":      stats.Total,
+		"upcoming":   stats.Upcoming,
+		"inProgress": stats.InProgress,
+		"completed":  stats.Completed,
+		"missed":     stats.Missed,
+		"cancelled":  stats.Cancelled,
 	}, nil
 }
 
@@ -377,8 +258,11 @@ func (s *ScheduleService) GetScheduleAnalytics(ctx context.Context, scheduleID u
 
 	result := map[string]interface{}{
 		"scheduleId":        schedule.ID,
+		"clientId":          schedule.ClientID,
 		"clientName":        schedule.ClientName,
-		"shiftTime":         schedule.ShiftTime,
+		"scheduledStart":    schedule.ScheduledStart,
+		"scheduledEnd":      schedule.ScheduledEnd,
+		"timeZone":          schedule.TimeZone,
 		"location":          schedule.Location,
 		"status":            schedule.Status,
 		"taskCompletionRate": completionRate,
@@ -397,17 +281,6 @@ func (s *ScheduleService) GetScheduleAnalytics(ctx context.Context, scheduleID u
 	return result, nil
 }
 
-// Helper function to check if status is valid
-func isValidScheduleStatus(status string) bool {
-	validStatuses := map[string]bool{
-		"missed":     true,
-		"upcoming":   true,
-		"in_progress": true,
-		"completed":  true,
-	}
-	return validStatuses[status]
-}
-
 // Helper function to count tasks by status
 func countTasksByStatus(tasks []model.Task, status string) int {
 	count := 0
@@ -435,32 +308,20 @@ func (s *ScheduleService) DeleteSchedule(ctx context.Context, id uuid.UUID) erro
 
 // Get upcoming schedules within next 7 days
 func (s *ScheduleService) GetUpcomingSchedules(ctx context.Context, days int) ([]model.Schedule, error) {
-	query := fmt.Sprintf(`
-		SELECT * FROM schedules
-		WHERE status = 'upcoming'
-		AND created_at >= NOW()
-		AND created_at <= NOW() + INTERVAL '%d days'
-		ORDER BY shift_time ASC
-	`, days)
-
-	var schedules []model.Schedule
-	rows, err := s.scheduleRepo.DB.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get upcoming schedules: %w", err)
-	}
-	defer rows.Close()
+	return s.scheduleRepo.GetUpcomingSchedules(ctx, days)
+}
 
-	for rows.Next() {
-		var schedule model.Schedule
-		if err := rows.Scan(&schedule.ID, &schedule.ClientName, &schedule.ShiftTime, &schedule.Location, &schedule.Status, &schedule.VisitID, &schedule.CreatedAt, &schedule.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan schedule: %w", err)
-		}
-		schedules = append(schedules, schedule)
+// schedulableClient loads a client that new shifts can be booked for
+func schedulableClient(ctx context.Context, clientRepo *repository.ClientRepository, clientID uuid.UUID) (*model.Client, error) {
+	client, err := clientRepo.GetClientByID(ctx, clientID)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate schedules: %w", err)
+	if !client.IsActive() {
+		code := "CLIENT_INACTIVE"
+		return nil, errs.NewBadRequestError("Client is inactive", true, &code, nil, nil)
 	}
 
-	return schedules, nil
-}
\ No newline at end of file
+	return client, nil
+}
@@ -12,13 +12,47 @@ type Services struct {
 	ScheduleService *ScheduleService
 	VisitService    *VisitService
 	TaskService     *TaskService
+	CaregiverService *CaregiverService
+	ClientService    *ClientService
+	CarePlanService  *CarePlanService
+	MissedVisitService *MissedVisitService
+	ScheduleSeriesService *ScheduleSeriesService
+	VisitExceptionService *VisitExceptionService
+	AuditService          *AuditService
+	VisitComplianceService *VisitComplianceService
+	AggregatorService      *AggregatorService
+	SyncService            *SyncService
+	ReportService          *ReportService
+	TimesheetService       *TimesheetService
+	BillingService         *BillingService
 }
 
 func NewServices(s *server.Server, repos *repository.Repositories) (*Services, error) {
 	authService := NewAuthService(s)
-	scheduleService := NewScheduleService(repos.Schedule, repos.Visit, repos.Task)
-	visitService := NewVisitService(repos.Visit, repos.Schedule)
-	taskService := NewTaskService(repos.Task, repos.Schedule)
+	scheduleService := NewScheduleService(repos.Schedule, repos.Visit, repos.Task, repos.Client, repos.CarePlan)
+	visitService := NewVisitService(repos.Visit, repos.Schedule, repos.VisitException, repos.UnitOfWork, s.Config.EVV)
+	taskService := NewTaskService(repos.Task, repos.Schedule, repos.UnitOfWork)
+	caregiverService := NewCaregiverService(repos.Caregiver, repos.Schedule)
+	clientService := NewClientService(repos.Client)
+	carePlanService := NewCarePlanService(repos.CarePlan, repos.Client)
+	missedVisitService := NewMissedVisitService(repos.Schedule, repos.UnitOfWork, s.Config.EVV)
+	scheduleSeriesService := NewScheduleSeriesService(repos.ScheduleSeries, repos.Schedule, repos.Client, repos.Caregiver, repos.CarePlan, s.Config.EVV)
+	visitExceptionService := NewVisitExceptionService(repos.VisitException, repos.Visit)
+	auditService := NewAuditService(repos.AuditEvent)
+	visitComplianceService := NewVisitComplianceService(repos.VisitCompliance, repos.Visit, repos.Schedule)
+	aggregatorService, err := NewAggregatorService(repos.Aggregator, repos.Visit, s.Config.Aggregator)
+	if err != nil {
+		return nil, err
+	}
+	syncService := NewSyncService(repos.SyncEvent, visitService, taskService, s.Config.EVV)
+	reportService := NewReportService(scheduleService, visitService, taskService, caregiverService, visitExceptionService)
+	timesheetService := NewTimesheetService(repos.Visit, caregiverService, s.Config.Timesheet)
+	billingService := NewBillingService(repos.Billing, repos.Visit, repos.Schedule, repos.Client, repos.Caregiver)
+
+	// Periodic jobs run against the services built here
+	s.Job.SetMissedVisitDetector(missedVisitService)
+	s.Job.SetSeriesMaterializer(scheduleSeriesService)
+	s.Job.SetAggregatorExporter(aggregatorService)
 
 	return &Services{
 		Auth:           authService,
@@ -26,5 +60,18 @@ func NewServices(s *server.Server, repos *repository.Repositories) (*Services, e
 		ScheduleService: scheduleService,
 		VisitService:    visitService,
 		TaskService:     taskService,
+		CaregiverService: caregiverService,
+		ClientService:    clientService,
+		CarePlanService:  carePlanService,
+		MissedVisitService: missedVisitService,
+		ScheduleSeriesService: scheduleSeriesService,
+		VisitExceptionService: visitExceptionService,
+		AuditService:          auditService,
+		VisitComplianceService: visitComplianceService,
+		AggregatorService:      aggregatorService,
+		SyncService:            syncService,
+		ReportService:          reportService,
+		TimesheetService:       timesheetService,
+		BillingService:         billingService,
 	}, nil
 }
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
+)
+
+type SyncService struct {
+	syncRepo     *repository.SyncEventRepository
+	visitService *VisitService
+	taskService  *TaskService
+	evvConfig    *config.EVVConfig
+}
+
+func NewSyncService(syncRepo *repository.SyncEventRepository, visitService *VisitService, taskService *TaskService, evvConfig *config.EVVConfig) *SyncService {
+	return &SyncService{
+		syncRepo:     syncRepo,
+		visitService: visitService,
+		taskService:  taskService,
+		evvConfig:    evvConfig,
+	}
+}
+
+// Apply a batch of events recorded on a user's device. Events are applied in
+// device time order and each is applied at most once; an event rejected by
+// the visit or task rules is recorded as rejected and not retried. Results
+// are returned in request order. sentAt is the device time the batch was
+// sent at and is used to measure the device's clock skew.
+func (s *SyncService) SyncEvents(ctx context.Context, userID string, sentAt *time.Time, events []model.SyncEvent) (*model.SyncBatchResult, error) {
+	if userID == "" {
+		return nil, errs.NewUnauthorizedError("Unauthorized", false)
+	}
+
+	receivedAt := time.Now()
+	skew := model.ClockSkew(sentAt, receivedAt)
+	var skewSeconds *int
+	if skew != nil {
+		seconds := int(skew.Seconds())
+		skewSeconds = &seconds
+	}
+
+	// A stable sort keeps the device's own order for events recorded at the same time
+	order := make([]int, len(events))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return events[a].DeviceTime.Compare(events[b].DeviceTime)
+	})
+
+	results := make([]model.SyncEventResult, len(events))
+	for _, i := range order {
+		event := &events[i]
+		event.UserID = userID
+		event.ReceivedAt = receivedAt
+		event.ClockSkewSeconds = skewSeconds
+		event.Status = model.SyncEventStatusPending
+		event.Flags = model.DetectSyncFlags(event.DeviceTime, receivedAt, skew, s.evvConfig.SyncClockSkewThreshold, s.evvConfig.SyncLateArrivalThreshold)
+
+		result, err := s.syncEvent(ctx, event)
+		if err != nil {
+			// Events applied so far are reported as duplicates when the batch is resent
+			return nil, fmt.Errorf("failed to sync event %s: %w", event.ClientEventID, err)
+		}
+		results[i] = result
+	}
+
+	return &model.SyncBatchResult{
+		ReceivedAt:       receivedAt,
+		ClockSkewSeconds: skewSeconds,
+		Results:          results,
+	}, nil
+}
+
+// Get synced events for review
+func (s *SyncService) GetSyncEvents(ctx context.Context, filter model.SyncEventFilter, page, limit int) (*model.PaginatedResponse[model.SyncEvent], error) {
+	return s.syncRepo.GetSyncEvents(ctx, filter, page, limit)
+}
+
+// syncEvent claims and applies one event. Errors other than the rule
+// violations recorded on the event release the claim and are returned.
+func (s *SyncService) syncEvent(ctx context.Context, event *model.SyncEvent) (model.SyncEventResult, error) {
+	claimed, err := s.syncRepo.ClaimSyncEvent(ctx, event)
+	if err != nil {
+		return model.SyncEventResult{}, err
+	}
+	if !claimed {
+		existing, err := s.syncRepo.GetSyncEvent(ctx, event.UserID, event.ClientEventID)
+		if err != nil {
+			return model.SyncEventResult{}, err
+		}
+		return existing.DuplicateResult(), nil
+	}
+
+	if err := s.applyEvent(ctx, event); err != nil {
+		var httpErr *errs.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Status >= http.StatusInternalServerError {
+			if releaseErr := s.syncRepo.ReleaseSyncEvent(ctx, event.UserID, event.ClientEventID); releaseErr != nil {
+				return model.SyncEventResult{}, errors.Join(err, releaseErr)
+			}
+			return model.SyncEventResult{}, err
+		}
+
+		event.Status = model.SyncEventStatusRejected
+		event.ErrorCode = &httpErr.Code
+		event.Error = &httpErr.Message
+	} else {
+		event.Status = model.SyncEventStatusApplied
+	}
+
+	if err := s.syncRepo.CompleteSyncEvent(ctx, event); err != nil {
+		return model.SyncEventResult{}, err
+	}
+
+	return event.Result(), nil
+}
+
+// applyEvent applies an event through the same rules as the live endpoints,
+// except that clock times are not checked against the server clock
+func (s *SyncService) applyEvent(ctx context.Context, event *model.SyncEvent) error {
+	switch event.Type {
+	case model.SyncEventTypeClockIn, model.SyncEventTypeClockOut:
+		if event.ScheduleID == nil || event.Latitude == nil || event.Longitude == nil {
+			return errs.NewBadRequestError("Clock events require a schedule and coordinates", true, nil, nil, nil)
+		}
+
+		var visit *model.Visit
+		var err error
+		if event.Type == model.SyncEventTypeClockIn {
+			visit, err = s.visitService.startVisit(ctx, *event.ScheduleID, event.DeviceTime, *event.Latitude, *event.Longitude)
+		} else {
+			visit, err = s.visitService.endVisit(ctx, *event.ScheduleID, event.DeviceTime, *event.Latitude, *event.Longitude)
+		}
+		if err != nil {
+			return err
+		}
+		event.VisitID = &visit.ID
+
+	case model.SyncEventTypeTaskStatus:
+		if event.TaskID == nil || event.TaskStatus == nil {
+			return errs.NewBadRequestError("Task status events require a task and status", true, nil, nil, nil)
+		}
+
+		if _, err := s.taskService.UpdateTaskStatus(ctx, *event.TaskID, *event.TaskStatus, event.Reason); err != nil {
+			return err
+		}
+
+	default:
+		return errs.NewBadRequestError(fmt.Sprintf("Unknown sync event type: %s", event.Type), true, nil, nil, nil)
+	}
+
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/listquery"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
 )
@@ -13,12 +15,14 @@ import (
 type TaskService struct {
 	taskRepo     *repository.TaskRepository
 	scheduleRepo *repository.ScheduleRepository
+	uow          *repository.UnitOfWork
 }
 
-func NewTaskService(taskRepo *repository.TaskRepository, scheduleRepo *repository.ScheduleRepository) *TaskService {
+func NewTaskService(taskRepo *repository.TaskRepository, scheduleRepo *repository.ScheduleRepository, uow *repository.UnitOfWork) *TaskService {
 	return &TaskService{
 		taskRepo:     taskRepo,
 		scheduleRepo: scheduleRepo,
+		uow:          uow,
 	}
 }
 
@@ -39,11 +43,24 @@ func (t *TaskService) CreateTask(ctx context.Context, scheduleID uuid.UUID, name
 		ScheduleID:  scheduleID,
 		Name:        name,
 		Description: &description,
-		Status:      "pending",
+		Status:      model.TaskStatusPending,
+		IsRequired:  true,
 	}
 
-	if err := t.taskRepo.CreateTask(ctx, task); err != nil {
-		return nil, fmt.Errorf("failed to create task: %w", err)
+	// The schedule is checked in the same transaction that adds the task
+	err := t.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		if _, err := repos.Schedule.GetScheduleByID(ctx, scheduleID); err != nil {
+			return fmt.Errorf("failed to get schedule: %w", err)
+		}
+
+		if err := repos.Task.CreateTask(ctx, task); err != nil {
+			return fmt.Errorf("failed to create task: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return task, nil
@@ -68,15 +85,24 @@ func (t *TaskService) CreateBatchTasks(ctx context.Context, scheduleID uuid.UUID
 			ScheduleID:  scheduleID,
 			Name:        taskCreate.Name,
 			Description: taskCreate.Description,
-			Status:      "pending",
+			Status:      model.TaskStatusPending,
+			IsRequired:  true,
+			SortOrder:   i + 1,
 		}
 	}
 
-	if err := t.taskRepo.CreateBatchTasks(ctx, taskModels); err != nil {
-		return fmt.Errorf("failed to create batch tasks: %w", err)
-	}
+	// The schedule is checked in the same transaction that adds its tasks
+	return t.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		if _, err := repos.Schedule.GetScheduleByID(ctx, scheduleID); err != nil {
+			return fmt.Errorf("failed to get schedule: %w", err)
+		}
 
-	return nil
+		if err := repos.Task.CreateBatchTasks(ctx, taskModels); err != nil {
+			return fmt.Errorf("failed to create batch tasks: %w", err)
+		}
+
+		return nil
+	})
 }
 
 // Get task by ID
@@ -89,30 +115,34 @@ func (t *TaskService) GetTasksByScheduleID(ctx context.Context, scheduleID uuid.
 	return t.taskRepo.GetTasksByScheduleID(ctx, scheduleID)
 }
 
-// Update task status
-func (t *TaskService) UpdateTaskStatus(ctx context.Context, taskID uuid.UUID, status string, reason *string) (*model.Task, error) {
-	// Validate status
-	if !isValidTaskStatus(status) {
-		return nil, fmt.Errorf("invalid task status: %s", status)
+// Get the tasks of a schedule that exists
+func (t *TaskService) GetScheduleTasks(ctx context.Context, scheduleID uuid.UUID) ([]model.Task, error) {
+	if _, err := t.scheduleRepo.GetScheduleByID(ctx, scheduleID); err != nil {
+		return nil, err
 	}
 
+	return t.taskRepo.GetTasksByScheduleID(ctx, scheduleID)
+}
+
+// Update task status
+func (t *TaskService) UpdateTaskStatus(ctx context.Context, taskID uuid.UUID, status string, reason *string) (*model.Task, error) {
 	// For not_completed tasks, reason is required
-	if status == "not_completed" && (reason == nil || *reason == "") {
-		return nil, fmt.Errorf("reason is required for not_completed tasks")
+	if status == model.TaskStatusNotCompleted && (reason == nil || *reason == "") {
+		return nil, errs.NewBadRequestError("Reason is required for not_completed tasks", true, nil, nil, nil)
 	}
 
-	// Validate that task exists
-	exists, err := t.taskRepo.TaskExists(ctx, taskID)
+	task, err := t.taskRepo.GetTaskByID(ctx, taskID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check task existence: %w", err)
+		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
 
-	if !exists {
-		return nil, fmt.Errorf("task not found")
+	// Validate the change against the task lifecycle
+	if err := model.TaskStatusMachine.Transition(task.Status, status); err != nil {
+		return nil, err
 	}
 
 	// Update task
-	updatedTask, err := t.taskRepo.UpdateTaskStatus(ctx, taskID, status, reason)
+	updatedTask, err := t.taskRepo.UpdateTaskStatus(ctx, task, status, reason)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update task status: %w", err)
 	}
@@ -122,14 +152,9 @@ func (t *TaskService) UpdateTaskStatus(ctx context.Context, taskID uuid.UUID, st
 
 // Update task details
 func (t *TaskService) UpdateTask(ctx context.Context, taskID uuid.UUID, name, description string, status string, reason *string) (*model.Task, error) {
-	// Validate status
-	if status != "" && !isValidTaskStatus(status) {
-		return nil, fmt.Errorf("invalid task status: %s", status)
-	}
-
 	// For not_completed tasks, reason is required
-	if status == "not_completed" && (reason == nil || *reason == "") {
-		return nil, fmt.Errorf("reason is required for not_completed tasks")
+	if status == model.TaskStatusNotCompleted && (reason == nil || *reason == "") {
+		return nil, errs.NewBadRequestError("Reason is required for not_completed tasks", true, nil, nil, nil)
 	}
 
 	// Get existing task
@@ -138,6 +163,13 @@ func (t *TaskService) UpdateTask(ctx context.Context, taskID uuid.UUID, name, de
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
 
+	// Status changes must follow the task lifecycle
+	if status != "" && status != task.Status {
+		if err := model.TaskStatusMachine.Transition(task.Status, status); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update fields
 	if name != "" {
 		task.Name = name
@@ -171,6 +203,11 @@ func (t *TaskService) GetTaskCompletionRate(ctx context.Context, scheduleID uuid
 	return t.taskRepo.GetTaskCompletionRate(ctx, scheduleID)
 }
 
+// Get a page of tasks matching listing's filters, in its sort
+func (t *TaskService) GetTasks(ctx context.Context, page model.CursorPage, listing *listquery.Query[model.Task]) (*model.CursorPaginatedResponse[model.Task], error) {
+	return t.taskRepo.GetTasks(ctx, page, listing)
+}
+
 // Get tasks by status
 func (t *TaskService) GetTasksByStatus(ctx context.Context, status string) ([]model.Task, error) {
 	return t.taskRepo.GetTasksByStatus(ctx, status)
@@ -217,29 +254,7 @@ func (t *TaskService) ValidateTaskUpdate(ctx context.Context, taskID uuid.UUID,
 
 // Calculate overall task statistics for all schedules
 func (t *TaskService) GetOverallTaskStats(ctx context.Context) (*model.TaskStats, error) {
-	query := `
-		SELECT
-			COUNT(*) as total,
-			COUNT(CASE WHEN status = 'completed' THEN 1 END) as completed,
-			COUNT(CASE WHEN status = 'pending' THEN 1 END) as pending,
-			COUNT(CASE WHEN status = 'not_completed' THEN 1 END) as not_completed
-		FROM tasks
-	`
-
-	var total, completed, pending, notCompleted int
-	err := t.taskRepo.DB.QueryRow(ctx, query).Scan(&total, &completed, &pending, &notCompleted)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get overall task stats: %w", err)
-	}
-
-	stats := &model.TaskStats{
-		TotalTasks:      total,
-		CompletedTasks:  completed,
-		PendingTasks:    pending,
-		NotCompletedTasks: notCompleted,
-	}
-
-	return stats, nil
+	return t.taskRepo.GetTaskStats(ctx)
 }
 
 // Get tasks that require attention (not completed with reasons)
@@ -298,8 +313,11 @@ func (t *TaskService) GenerateTaskReport(ctx context.Context, scheduleID uuid.UU
 
 	report := map[string]interface{}{
 		"scheduleId":    schedule.ID,
+		"clientId":      schedule.ClientID,
 		"clientName":    schedule.ClientName,
-		"shiftTime":     schedule.ShiftTime,
+		"scheduledStart": schedule.ScheduledStart,
+		"scheduledEnd":  schedule.ScheduledEnd,
+		"timeZone":      schedule.TimeZone,
 		"location":      schedule.Location,
 		"totalTasks":    stats.TotalTasks,
 		"completed":     stats.CompletedTasks,
@@ -313,31 +331,33 @@ func (t *TaskService) GenerateTaskReport(ctx context.Context, scheduleID uuid.UU
 	return report, nil
 }
 
-// Helper function to check if task status is valid
-func isValidTaskStatus(status string) bool {
-	validStatuses := map[string]bool{
-		"pending":      true,
-		"completed":    true,
-		"not_completed": true,
-	}
-	return validStatuses[status]
-}
-
 // Mark all pending tasks as not completed (for bulk operations)
 func (t *TaskService) MarkPendingTasksAsNotCompleted(ctx context.Context, scheduleID uuid.UUID, reason string) error {
+	return t.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		return markPendingTasksAsNotCompleted(ctx, repos.Task, scheduleID, reason)
+	})
+}
+
+// markPendingTasksAsNotCompleted closes out a schedule's pending tasks through
+// taskRepo, so callers can do it in the transaction that changes the schedule
+func markPendingTasksAsNotCompleted(ctx context.Context, taskRepo *repository.TaskRepository, scheduleID uuid.UUID, reason string) error {
 	// Get pending tasks
-	tasks, err := t.GetTasksByScheduleID(ctx, scheduleID)
+	tasks, err := taskRepo.GetTasksByScheduleID(ctx, scheduleID)
 	if err != nil {
 		return fmt.Errorf("failed to get tasks: %w", err)
 	}
 
 	// Update each pending task
-	for _, task := range tasks {
-		if task.Status == "pending" {
-			_, err := t.UpdateTaskStatus(ctx, task.ID, "not_completed", &reason)
-			if err != nil {
-				return fmt.Errorf("failed to update task %s: %w", task.ID, err)
-			}
+	for i := range tasks {
+		task := &tasks[i]
+		if task.Status != model.TaskStatusPending {
+			continue
+		}
+		if err := model.TaskStatusMachine.Transition(task.Status, model.TaskStatusNotCompleted); err != nil {
+			return err
+		}
+		if _, err := taskRepo.UpdateTaskStatus(ctx, task, model.TaskStatusNotCompleted, &reason); err != nil {
+			return fmt.Errorf("failed to update task %s: %w", task.ID, err)
 		}
 	}
 
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/report"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
+)
+
+// TimesheetService adds up caregivers' completed visits per pay period for payroll
+type TimesheetService struct {
+	visitRepo        *repository.VisitRepository
+	caregiverService *CaregiverService
+	config           *config.TimesheetConfig
+}
+
+func NewTimesheetService(visitRepo *repository.VisitRepository, caregiverService *CaregiverService, cfg *config.TimesheetConfig) *TimesheetService {
+	return &TimesheetService{
+		visitRepo:        visitRepo,
+		caregiverService: caregiverService,
+		config:           cfg,
+	}
+}
+
+// GetTimesheets returns the timesheets of the pay period containing date, a
+// YYYY-MM-DD date in the pay zone or today when empty. With a caregiver, only
+// that caregiver's timesheet is returned, even when it has no visits.
+func (s *TimesheetService) GetTimesheets(ctx context.Context, date string, caregiverID *uuid.UUID) (*model.TimesheetReport, error) {
+	period, err := s.payPeriod(date)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uuid.UUID]string)
+	if caregiverID != nil {
+		caregiver, err := s.caregiverService.GetCaregiverByID(ctx, *caregiverID)
+		if err != nil {
+			return nil, err
+		}
+		names[caregiver.ID] = strings.TrimSpace(caregiver.FullName())
+	}
+
+	visits, err := s.visitRepo.GetCompletedVisitsByCaregiver(ctx, period.Start, period.End, caregiverID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Visits come ordered by caregiver
+	caregivers := make([]uuid.UUID, 0)
+	byCaregiver := make(map[uuid.UUID][]model.Visit)
+	if caregiverID != nil {
+		caregivers = append(caregivers, *caregiverID)
+	}
+	for _, visit := range visits {
+		if _, ok := byCaregiver[visit.CaregiverID]; !ok && caregiverID == nil {
+			caregivers = append(caregivers, visit.CaregiverID)
+		}
+		byCaregiver[visit.CaregiverID] = append(byCaregiver[visit.CaregiverID], visit.Visit)
+	}
+
+	rules := model.OvertimeRules{
+		Daily:  s.config.DailyOvertimeThreshold,
+		Weekly: s.config.WeeklyOvertimeThreshold,
+	}
+	timesheets := make([]model.Timesheet, 0, len(caregivers))
+	for _, id := range caregivers {
+		sheet := model.NewTimesheet(id, period, byCaregiver[id], rules)
+		sheet.CaregiverName, err = s.caregiverName(ctx, names, id)
+		if err != nil {
+			return nil, err
+		}
+		timesheets = append(timesheets, sheet)
+	}
+
+	return &model.TimesheetReport{Period: period, Timesheets: timesheets}, nil
+}
+
+// TimesheetTable lays timesheets out with a row per caregiver workday and a
+// total row per caregiver. Hours are decimal.
+func (s *TimesheetService) TimesheetTable(timesheets *model.TimesheetReport) *report.Table {
+	start, end := s.PeriodDates(timesheets.Period)
+	table := &report.Table{
+		Title: fmt.Sprintf("Timesheets: %s to %s", start, end),
+		Columns: []report.Column{
+			{Name: "Caregiver ID"}, {Name: "Caregiver"}, {Name: "Period Start"}, {Name: "Period End"}, {Name: "Date"},
+			{Name: "Visits", Numeric: true}, {Name: "Hours", Numeric: true}, {Name: "Regular Hours", Numeric: true},
+			{Name: "Daily OT Hours", Numeric: true}, {Name: "Weekly OT Hours", Numeric: true}, {Name: "Overlapping Visits", Numeric: true},
+		},
+		Rows: make([][]string, 0),
+	}
+
+	for _, sheet := range timesheets.Timesheets {
+		row := func(date string, visits, minutes, regular, dailyOvertime, weeklyOvertime, overlapping int) []string {
+			return []string{
+				sheet.CaregiverID.String(), sheet.CaregiverName, start, end, date,
+				strconv.Itoa(visits), hours(minutes), hours(regular), hours(dailyOvertime), hours(weeklyOvertime), strconv.Itoa(overlapping),
+			}
+		}
+
+		for _, day := range sheet.Days {
+			overlapping := 0
+			for _, visit := range day.Visits {
+				if len(visit.OverlapsWith) > 0 {
+					overlapping++
+				}
+			}
+			table.Rows = append(table.Rows, row(day.Date, len(day.Visits), day.Minutes, day.RegularMinutes,
+				day.DailyOvertimeMinutes, day.WeeklyOvertimeMinutes, overlapping))
+		}
+		table.Rows = append(table.Rows, row("Total", sheet.VisitCount, sheet.Minutes, sheet.RegularMinutes,
+			sheet.DailyOvertimeMinutes, sheet.WeeklyOvertimeMinutes, sheet.OverlappingVisits))
+	}
+
+	return table
+}
+
+// PeriodDates returns the first and last day of a pay period as YYYY-MM-DD
+func (s *TimesheetService) PeriodDates(period model.PayPeriod) (string, string) {
+	return period.Start.Format(time.DateOnly), period.End.AddDate(0, 0, -1).Format(time.DateOnly)
+}
+
+func (s *TimesheetService) payPeriod(date string) (model.PayPeriod, error) {
+	location := s.config.Location()
+	day := time.Now().In(location)
+	if date != "" {
+		var err error
+		day, err = time.ParseInLocation(time.DateOnly, date, location)
+		if err != nil {
+			return model.PayPeriod{}, errs.NewBadRequestError("date must be a YYYY-MM-DD date", true, nil, nil, nil)
+		}
+	}
+	return model.PayPeriodContaining(day, s.config.Anchor(), s.config.PeriodDays()), nil
+}
+
+// caregiverName reads each caregiver's name once into names
+func (s *TimesheetService) caregiverName(ctx context.Context, names map[uuid.UUID]string, id uuid.UUID) (string, error) {
+	if name, ok := names[id]; ok {
+		return name, nil
+	}
+	caregiver, err := s.caregiverService.GetCaregiverByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	names[id] = strings.TrimSpace(caregiver.FullName())
+	return names[id], nil
+}
+
+// hours formats minutes as decimal hours
+func hours(minutes int) string {
+	return strconv.FormatFloat(float64(minutes)/60, 'f', 2, 64)
+}
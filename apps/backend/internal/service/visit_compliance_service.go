@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
+)
+
+type VisitComplianceService struct {
+	complianceRepo *repository.VisitComplianceRepository
+	visitRepo      *repository.VisitRepository
+	scheduleRepo   *repository.ScheduleRepository
+}
+
+func NewVisitComplianceService(complianceRepo *repository.VisitComplianceRepository, visitRepo *repository.VisitRepository,
+	scheduleRepo *repository.ScheduleRepository) *VisitComplianceService {
+	return &VisitComplianceService{
+		complianceRepo: complianceRepo,
+		visitRepo:      visitRepo,
+		scheduleRepo:   scheduleRepo,
+	}
+}
+
+// Get the latest compliance check of a visit
+func (s *VisitComplianceService) GetVisitCompliance(ctx context.Context, visitID uuid.UUID) (*model.VisitCompliance, error) {
+	if _, err := s.visitRepo.GetVisitByID(ctx, visitID); err != nil {
+		return nil, err
+	}
+
+	return s.complianceRepo.GetVisitCompliance(ctx, visitID)
+}
+
+// Get the non-compliant visits with a service date in [from, to]
+func (s *VisitComplianceService) GetNonCompliantVisits(ctx context.Context, from, to time.Time, page, limit int) (*model.PaginatedResponse[model.VisitCompliance], error) {
+	if to.Before(from) {
+		return nil, errs.NewBadRequestError("to must not be before from", true, nil, nil, nil)
+	}
+
+	return s.complianceRepo.GetNonCompliantVisits(ctx, from, to, page, limit)
+}
+
+// Check a completed visit again, e.g. after its schedule's service code or
+// caregiver was filled in
+func (s *VisitComplianceService) CheckVisit(ctx context.Context, visitID uuid.UUID) (*model.VisitCompliance, error) {
+	visit, err := s.visitRepo.GetVisitByID(ctx, visitID)
+	if err != nil {
+		return nil, err
+	}
+
+	if visit.Status != model.VisitStatusCompleted {
+		code := "VISIT_NOT_COMPLETED"
+		return nil, errs.NewBadRequestError("Only completed visits are checked for EVV compliance", true, &code, nil, nil)
+	}
+
+	schedule, err := s.scheduleRepo.GetScheduleByID(ctx, visit.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return recordVisitCompliance(ctx, s.complianceRepo, visit, schedule)
+}
+
+// Check every completed visit with a service date in [from, to] again
+func (s *VisitComplianceService) CheckVisits(ctx context.Context, from, to time.Time) (*model.VisitComplianceRun, error) {
+	if to.Before(from) {
+		return nil, errs.NewBadRequestError("to must not be before from", true, nil, nil, nil)
+	}
+
+	ids, err := s.complianceRepo.GetCompletedVisitIDs(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Visits that fail to check are listed in the run; the rest are still checked
+	run := &model.VisitComplianceRun{From: from, To: to, Failures: []model.VisitRunFailure{}}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		compliance, err := s.CheckVisit(ctx, id)
+		if err != nil {
+			run.Failures = append(run.Failures, model.VisitRunFailure{VisitID: id, Error: err.Error()})
+			continue
+		}
+		run.Checked++
+		if !compliance.IsCompliant {
+			run.NonCompliant++
+		}
+	}
+
+	return run, nil
+}
+
+// recordVisitCompliance checks a completed visit for the six required EVV data
+// elements and stores the result
+func recordVisitCompliance(ctx context.Context, complianceRepo *repository.VisitComplianceRepository, visit *model.Visit,
+	schedule *model.Schedule) (*model.VisitCompliance, error) {
+	compliance := model.EvaluateVisitCompliance(visit, schedule, time.Now())
+	if err := complianceRepo.SaveVisitCompliance(ctx, &compliance); err != nil {
+		return nil, err
+	}
+
+	return &compliance, nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
+)
+
+type VisitExceptionService struct {
+	exceptionRepo *repository.VisitExceptionRepository
+	visitRepo     *repository.VisitRepository
+}
+
+func NewVisitExceptionService(exceptionRepo *repository.VisitExceptionRepository, visitRepo *repository.VisitRepository) *VisitExceptionService {
+	return &VisitExceptionService{
+		exceptionRepo: exceptionRepo,
+		visitRepo:     visitRepo,
+	}
+}
+
+// Get reason codes; inactive codes are only listed when asked for
+func (s *VisitExceptionService) GetExceptionReasonCodes(ctx context.Context, includeInactive bool) ([]model.ExceptionReasonCode, error) {
+	return s.exceptionRepo.GetExceptionReasonCodes(ctx, !includeInactive)
+}
+
+// Create a reason code
+func (s *VisitExceptionService) CreateExceptionReasonCode(ctx context.Context, code, description string) (*model.ExceptionReasonCode, error) {
+	return s.exceptionRepo.CreateExceptionReasonCode(ctx, code, description)
+}
+
+// Get an exception reason code
+func (s *VisitExceptionService) GetExceptionReasonCode(ctx context.Context, code string) (*model.ExceptionReasonCode, error) {
+	return s.exceptionRepo.GetExceptionReasonCode(ctx, code)
+}
+
+// Update a reason code. Codes are deactivated rather than deleted since
+// resolved exceptions keep referring to them.
+func (s *VisitExceptionService) UpdateExceptionReasonCode(ctx context.Context, code, description string, isActive bool) (*model.ExceptionReasonCode, error) {
+	reason, err := s.exceptionRepo.GetExceptionReasonCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.exceptionRepo.UpdateExceptionReasonCode(ctx, reason, description, isActive)
+}
+
+// Get the exceptions recorded for a visit
+func (s *VisitExceptionService) GetVisitExceptionsByVisit(ctx context.Context, visitID uuid.UUID) ([]model.VisitException, error) {
+	if _, err := s.visitRepo.GetVisitByID(ctx, visitID); err != nil {
+		return nil, err
+	}
+
+	return s.exceptionRepo.GetVisitExceptionsByVisit(ctx, visitID)
+}
+
+// Get the exception review queue, optionally filtered by status and detection time
+func (s *VisitExceptionService) GetVisitExceptions(ctx context.Context, filter model.VisitExceptionFilter, page, limit int) (*model.PaginatedResponse[model.VisitException], error) {
+	if filter.Status != "" && !model.VisitExceptionStatusMachine.IsValid(filter.Status) {
+		return nil, errs.NewBadRequestError("Invalid visit exception status: "+filter.Status, true, nil, nil, nil)
+	}
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, errs.NewBadRequestError("to must be after from", true, nil, nil, nil)
+	}
+
+	return s.exceptionRepo.GetVisitExceptions(ctx, filter, page, limit)
+}
+
+// Get visit exception by ID
+func (s *VisitExceptionService) GetVisitExceptionByID(ctx context.Context, id uuid.UUID) (*model.VisitException, error) {
+	return s.exceptionRepo.GetVisitExceptionByID(ctx, id)
+}
+
+// Record the caregiver's reason code and explanation and send the exception
+// to the coordinator queue. A rejected exception can be explained again.
+func (s *VisitExceptionService) ExplainVisitException(ctx context.Context, id uuid.UUID, reasonCode, explanation, userID string) (*model.VisitException, error) {
+	if userID == "" {
+		return nil, errs.NewUnauthorizedError("Unauthorized", false)
+	}
+
+	exception, err := s.exceptionRepo.GetVisitExceptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := model.VisitExceptionStatusMachine.Transition(exception.Status, model.VisitExceptionStatusPendingReview); err != nil {
+		return nil, err
+	}
+
+	if err := requireActiveReasonCode(ctx, s.exceptionRepo, reasonCode); err != nil {
+		return nil, err
+	}
+
+	return s.exceptionRepo.ExplainVisitException(ctx, exception, reasonCode, explanation, userID)
+}
+
+// Approve an explained exception
+func (s *VisitExceptionService) ApproveVisitException(ctx context.Context, id uuid.UUID, userID string, comment *string) (*model.VisitException, error) {
+	return s.review(ctx, id, model.VisitExceptionStatusApproved, userID, comment)
+}
+
+// Reject an explained exception, sending it back to the caregiver
+func (s *VisitExceptionService) RejectVisitException(ctx context.Context, id uuid.UUID, userID, comment string) (*model.VisitException, error) {
+	return s.review(ctx, id, model.VisitExceptionStatusRejected, userID, &comment)
+}
+
+func (s *VisitExceptionService) review(ctx context.Context, id uuid.UUID, to, userID string, comment *string) (*model.VisitException, error) {
+	if userID == "" {
+		return nil, errs.NewUnauthorizedError("Unauthorized", false)
+	}
+
+	exception, err := s.exceptionRepo.GetVisitExceptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := model.VisitExceptionStatusMachine.Transition(exception.Status, to); err != nil {
+		return nil, err
+	}
+
+	return s.exceptionRepo.ReviewVisitException(ctx, id, to, userID, comment)
+}
+
+// requireActiveReasonCode rejects a reason code that does not exist or has been retired
+func requireActiveReasonCode(ctx context.Context, exceptionRepo *repository.VisitExceptionRepository, code string) error {
+	reason, err := exceptionRepo.GetExceptionReasonCode(ctx, code)
+	var httpErr *errs.HTTPError
+	if err != nil && !(errors.As(err, &httpErr) && httpErr.Status == http.StatusNotFound) {
+		return err
+	}
+
+	if reason == nil || !reason.IsActive {
+		errCode := "INVALID_REASON_CODE"
+		return errs.NewBadRequestError("Unknown or inactive reason code: "+code, true, &errCode, nil, nil)
+	}
+
+	return nil
+}
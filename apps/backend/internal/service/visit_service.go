@@ -3,9 +3,14 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/config"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/listquery"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/geo"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
 	"github.com/sriniously/go-boilerplate/apps/backend/internal/repository"
 )
@@ -13,97 +18,367 @@ import (
 type VisitService struct {
 	visitRepo  *repository.VisitRepository
 	scheduleRepo *repository.ScheduleRepository
+	exceptionRepo *repository.VisitExceptionRepository
+	uow          *repository.UnitOfWork
+	evvConfig    *config.EVVConfig
 }
 
-func NewVisitService(visitRepo *repository.VisitRepository, scheduleRepo *repository.ScheduleRepository) *VisitService {
+func NewVisitService(visitRepo *repository.VisitRepository, scheduleRepo *repository.ScheduleRepository, exceptionRepo *repository.VisitExceptionRepository,
+	uow *repository.UnitOfWork, evvConfig *config.EVVConfig) *VisitService {
 	return &VisitService{
-		visitRepo:    visitRepo,
-		scheduleRepo: scheduleRepo,
+		visitRepo:     visitRepo,
+		scheduleRepo:  scheduleRepo,
+		exceptionRepo: exceptionRepo,
+		uow:           uow,
+		evvConfig:     evvConfig,
 	}
 }
 
 // Start a visit for a schedule
 func (v *VisitService) StartVisit(ctx context.Context, scheduleID uuid.UUID, startTime time.Time, startLat, startLong float64) (*model.Visit, error) {
+	// Validate time is not in the past
+	if startTime.Before(time.Now().Add(-5 * time.Minute)) {
+		return nil, errs.NewBadRequestError("Start time cannot be in the past", true, nil, nil, nil)
+	}
+
+	return v.startVisit(ctx, scheduleID, startTime, startLat, startLong)
+}
+
+// startVisit clocks in at startTime without checking it against the server
+// clock. Synced offline events are recorded at their device time.
+func (v *VisitService) startVisit(ctx context.Context, scheduleID uuid.UUID, startTime time.Time, startLat, startLong float64) (*model.Visit, error) {
 	// Validate coordinates
 	if !isValidCoordinates(startLat, startLong) {
-		return nil, fmt.Errorf("invalid geolocation coordinates")
+		return nil, errs.NewBadRequestError("Invalid geolocation coordinates", true, nil, nil, nil)
 	}
 
-	// Check if schedule exists
-	_, err := v.scheduleRepo.GetScheduleByID(ctx, scheduleID)
+	var schedule *model.Schedule
+	var visit *model.Visit
+	var geofence *model.GeofenceResult
+
+	// The visit, the schedule's new status and the visit's exceptions are saved together
+	err := v.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		var err error
+
+		// Check if schedule exists
+		schedule, err = repos.Schedule.GetScheduleByID(ctx, scheduleID)
+		if err != nil {
+			return fmt.Errorf("failed to get schedule: %w", err)
+		}
+
+		// Check if visit already exists
+		exists, err := repos.Visit.VisitExistsForSchedule(ctx, scheduleID)
+		if err != nil {
+			return fmt.Errorf("failed to check visit existence: %w", err)
+		}
+
+		if exists {
+			return errs.NewBadRequestError("Visit already started for this schedule", true, nil, nil, nil)
+		}
+
+		// Clocking in moves the schedule to in_progress
+		if err := model.ScheduleStatusMachine.Transition(schedule.Status, model.ScheduleStatusInProgress); err != nil {
+			return err
+		}
+
+		// Verify the caregiver is at the service location
+		geofence, err = v.checkGeofence(schedule, startLat, startLong, "Clock-in")
+		if err != nil {
+			return err
+		}
+
+		// Create visit
+		visit, err = repos.Visit.StartVisit(ctx, scheduleID, startTime, startLat, startLong, geofence)
+		if err != nil {
+			return fmt.Errorf("failed to start visit: %w", err)
+		}
+
+		// Update schedule status to in_progress
+		if err := repos.Schedule.UpdateScheduleStatus(ctx, scheduleID, schedule.Status, model.ScheduleStatusInProgress); err != nil {
+			return fmt.Errorf("failed to update schedule status: %w", err)
+		}
+
+		// A late or out-of-range clock-in needs an explanation before the visit can be verified
+		exceptions := model.DetectClockInExceptions(visit, schedule, geofence, v.evvConfig.LateClockInThreshold, time.Now())
+		if err := repos.VisitException.CreateVisitExceptions(ctx, exceptions); err != nil {
+			return fmt.Errorf("failed to record visit exceptions: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get schedule: %w", err)
+		return nil, err
+	}
+
+	return visit, nil
+}
+
+// End a visit
+func (v *VisitService) EndVisit(ctx context.Context, scheduleID uuid.UUID, endTime time.Time, endLat, endLong float64) (*model.Visit, error) {
+	// Validate end time is not too far in the future
+	if endTime.After(time.Now().Add(1 * time.Hour)) {
+		return nil, errs.NewBadRequestError("End time cannot be more than 1 hour in the future", true, nil, nil, nil)
+	}
+
+	return v.endVisit(ctx, scheduleID, endTime, endLat, endLong)
+}
+
+// endVisit clocks out at endTime without checking it against the server clock
+func (v *VisitService) endVisit(ctx context.Context, scheduleID uuid.UUID, endTime time.Time, endLat, endLong float64) (*model.Visit, error) {
+	// Validate coordinates
+	if !isValidCoordinates(endLat, endLong) {
+		return nil, errs.NewBadRequestError("Invalid geolocation coordinates", true, nil, nil, nil)
+	}
+
+	var updatedVisit *model.Visit
+
+	// The visit's end, the schedule's completion and the visit's exceptions,
+	// compliance check and billing are saved together
+	err := v.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		// Get existing visit
+		visit, err := repos.Visit.GetVisitByScheduleID(ctx, scheduleID)
+		if err != nil {
+			return fmt.Errorf("failed to get visit: %w", err)
+		}
+
+		// Clocking out completes the visit
+		if err := model.VisitStatusMachine.Transition(visit.Status, model.VisitStatusCompleted); err != nil {
+			return err
+		}
+
+		// Validate end time is after start time
+		if endTime.Before(visit.StartTime) {
+			return errs.NewBadRequestError("End time must be after start time", true, nil, nil, nil)
+		}
+
+		// Verify the caregiver is still at the service location
+		schedule, err := repos.Schedule.GetScheduleByID(ctx, scheduleID)
+		if err != nil {
+			return fmt.Errorf("failed to get schedule: %w", err)
+		}
+
+		if err := model.ScheduleStatusMachine.Transition(schedule.Status, model.ScheduleStatusCompleted); err != nil {
+			return err
+		}
+
+		geofence, err := v.checkGeofence(schedule, endLat, endLong, "Clock-out")
+		if err != nil {
+			return err
+		}
+
+		// End visit
+		updatedVisit, err = repos.Visit.EndVisit(ctx, visit.ID, endTime, endLat, endLong, geofence)
+		if err != nil {
+			return fmt.Errorf("failed to end visit: %w", err)
+		}
+		updatedVisit.CalculateDuration()
+
+		// Update schedule status to completed
+		if err := repos.Schedule.UpdateScheduleStatus(ctx, scheduleID, schedule.Status, model.ScheduleStatusCompleted); err != nil {
+			return fmt.Errorf("failed to update schedule status: %w", err)
+		}
+
+		exceptions := model.DetectClockOutExceptions(updatedVisit, schedule, geofence, v.evvConfig.EarlyClockOutThreshold, time.Now())
+		if err := repos.VisitException.CreateVisitExceptions(ctx, exceptions); err != nil {
+			return fmt.Errorf("failed to record visit exceptions: %w", err)
+		}
+		// The visit was read back before these exceptions existed
+		if len(exceptions) > 0 {
+			updatedVisit.IsVerified = false
+		}
+
+		// A completed visit is checked for the required EVV data elements
+		// and billed at its payer's rate
+		return recordCompletedVisit(ctx, repos, updatedVisit, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedVisit, nil
+}
+
+// manualEntryScheduleStatuses are the schedule statuses a visit can be entered
+// after the fact for. Nobody clocking in usually leaves the schedule missed.
+var manualEntryScheduleStatuses = []string{model.ScheduleStatusUpcoming, model.ScheduleStatusMissed}
+
+// Record a visit after the fact for a schedule nobody clocked in to. The entry
+// raises a manual entry exception carrying the coordinator's reason.
+func (v *VisitService) CreateManualVisit(ctx context.Context, scheduleID uuid.UUID, entry model.ManualVisitEntry, userID string) (*model.Visit, error) {
+	if userID == "" {
+		return nil, errs.NewUnauthorizedError("Unauthorized", false)
+	}
+
+	schedule, err := v.scheduleRepo.GetScheduleByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(manualEntryScheduleStatuses, schedule.Status) {
+		code := "SCHEDULE_NOT_ENTERABLE"
+		message := fmt.Sprintf("Cannot enter a visit for a %s schedule", schedule.Status)
+		return nil, errs.NewConflictError(message, true, &code)
 	}
 
-	// Check if visit already exists
 	exists, err := v.visitRepo.VisitExistsForSchedule(ctx, scheduleID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check visit existence: %w", err)
 	}
-
 	if exists {
-		return nil, fmt.Errorf("visit already started for this schedule")
+		return nil, errs.NewBadRequestError("Visit already recorded for this schedule", true, nil, nil, nil)
 	}
 
-	// Validate time is not in the past
-	if startTime.Before(time.Now().Add(-5 * time.Minute)) {
-		return nil, fmt.Errorf("start time cannot be in the past")
+	now := time.Now()
+	if entry.StartTime.After(now) || entry.EndTime != nil && entry.EndTime.After(now) {
+		return nil, errs.NewBadRequestError("Manual visits can only be entered for times in the past", true, nil, nil, nil)
+	}
+	if entry.EndTime != nil && !entry.EndTime.After(entry.StartTime) {
+		return nil, errs.NewBadRequestError("End time must be after start time", true, nil, nil, nil)
 	}
 
-	// Create visit
-	visit, err := v.visitRepo.StartVisit(ctx, scheduleID, startTime, startLat, startLong)
+	if err := requireActiveReasonCode(ctx, v.exceptionRepo, entry.ReasonCode); err != nil {
+		return nil, err
+	}
+
+	// Without recorded coordinates the visit is placed at the service location
+	startLat, startLong, err := manualEntryCoordinates(schedule, entry.StartLatitude, entry.StartLongitude)
 	if err != nil {
-		return nil, fmt.Errorf("failed to start visit: %w", err)
+		return nil, err
 	}
 
-	// Update schedule status to in_progress
-	if err := v.scheduleRepo.UpdateScheduleStatus(ctx, scheduleID, "in_progress"); err != nil {
-		return nil, fmt.Errorf("failed to update schedule status: %w", err)
+	visit := &model.Visit{
+		ScheduleID:     scheduleID,
+		StartTime:      entry.StartTime,
+		StartLatitude:  *startLat,
+		StartLongitude: *startLong,
+		Status:         model.VisitStatusInProgress,
+		IsManualEntry:  true,
+	}
+	visit.ID = uuid.New()
+	scheduleStatus := model.ScheduleStatusInProgress
+
+	if entry.EndTime != nil {
+		endLat, endLong, err := manualEntryCoordinates(schedule, entry.EndLatitude, entry.EndLongitude)
+		if err != nil {
+			return nil, err
+		}
+		visit.EndTime = entry.EndTime
+		visit.EndLatitude = endLat
+		visit.EndLongitude = endLong
+		visit.Status = model.VisitStatusCompleted
+		visit.CalculateDuration()
+		scheduleStatus = model.ScheduleStatusCompleted
 	}
 
-	return visit, nil
+	correction := model.NewVisitCorrection(visit.ID, nil, visit.ClockData(), entry.ReasonCode, entry.Reason, userID, now)
+	exception := model.NewManualEntryException(visit.ID, "Visit entered manually by a coordinator", entry.ReasonCode, entry.Reason, userID, now)
+
+	var created *model.Visit
+	err = v.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		if err := repos.Visit.CreateManualVisit(ctx, visit, &correction, &exception, schedule.Status, scheduleStatus); err != nil {
+			return err
+		}
+
+		created, err = repos.Visit.GetVisitByID(ctx, visit.ID)
+		if err != nil {
+			return err
+		}
+
+		if created.Status == model.VisitStatusCompleted {
+			err = recordCompletedVisit(ctx, repos, created, schedule)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
-// End a visit
-func (v *VisitService) EndVisit(ctx context.Context, scheduleID uuid.UUID, endTime time.Time, endLat, endLong float64) (*model.Visit, error) {
-	// Validate coordinates
-	if !isValidCoordinates(endLat, endLong) {
-		return nil, fmt.Errorf("invalid geolocation coordinates")
+// Correct a visit's clock times or coordinates. The values before the
+// correction are kept in the visit's correction history, and the correction
+// raises a manual entry exception that has to be reviewed again.
+func (v *VisitService) CorrectVisit(ctx context.Context, visitID uuid.UUID, update model.VisitCorrectionUpdate, userID string) (*model.Visit, error) {
+	if userID == "" {
+		return nil, errs.NewUnauthorizedError("Unauthorized", false)
 	}
 
-	// Get existing visit
-	visit, err := v.visitRepo.GetVisitByScheduleID(ctx, scheduleID)
+	visit, err := v.visitRepo.GetVisitByID(ctx, visitID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get visit: %w", err)
+		return nil, err
 	}
 
-	// Check if visit is already completed
-	if visit.Status == "completed" {
-		return nil, fmt.Errorf("visit is already completed")
+	from := visit.Status
+	previous := visit.ClockData()
+	changed := visit.ApplyCorrection(update.VisitClockData)
+	if len(changed) == 0 {
+		code := "NO_CHANGES"
+		return nil, errs.NewBadRequestError("Correction does not change the visit", true, &code, nil, nil)
 	}
 
-	// Validate end time is after start time
-	if endTime.Before(visit.StartTime) {
-		return nil, fmt.Errorf("end time must be after start time")
+	now := time.Now()
+	if visit.StartTime.After(now) || visit.EndTime != nil && visit.EndTime.After(now) {
+		return nil, errs.NewBadRequestError("Corrected times cannot be in the future", true, nil, nil, nil)
+	}
+	if visit.EndTime != nil && !visit.EndTime.After(visit.StartTime) {
+		return nil, errs.NewBadRequestError("End time must be after start time", true, nil, nil, nil)
+	}
+	if visit.EndTime != nil && visit.EndLatitude == nil {
+		return nil, errs.NewBadRequestError("End coordinates are required when setting an end time", true, nil, nil, nil)
 	}
 
-	// Validate end time is not too far in the future
-	if endTime.After(time.Now().Add(1 * time.Hour)) {
-		return nil, fmt.Errorf("end time cannot be more than 1 hour in the future")
+	schedule, err := v.scheduleRepo.GetScheduleByID(ctx, visit.ScheduleID)
+	if err != nil {
+		return nil, err
 	}
 
-	// End visit
-	updatedVisit, err := v.visitRepo.EndVisit(ctx, visit.ID, endTime, endLat, endLong)
+	// Setting the end time of a visit in progress clocks it out
+	if from != visit.Status {
+		if err := model.ScheduleStatusMachine.Transition(schedule.Status, model.ScheduleStatusCompleted); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := requireActiveReasonCode(ctx, v.exceptionRepo, update.ReasonCode); err != nil {
+		return nil, err
+	}
+
+	correction := model.NewVisitCorrection(visit.ID, &previous, visit.ClockData(), update.ReasonCode, update.Reason, userID, now)
+	exception := model.NewManualEntryException(visit.ID, model.CorrectionDetail(changed), update.ReasonCode, update.Reason, userID, now)
+
+	var corrected *model.Visit
+	err = v.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		if err := repos.Visit.CorrectVisit(ctx, visit, from, &correction, &exception); err != nil {
+			return err
+		}
+
+		corrected, err = repos.Visit.GetVisitByID(ctx, visit.ID)
+		if err != nil {
+			return err
+		}
+
+		// Corrected clock data can fill in or remove required EVV data elements
+		// and changes the billable time
+		if corrected.Status == model.VisitStatusCompleted {
+			err = recordCompletedVisit(ctx, repos, corrected, schedule)
+		}
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to end visit: %w", err)
+		return nil, err
 	}
 
-	// Update schedule status to completed
-	if err := v.scheduleRepo.UpdateScheduleStatus(ctx, scheduleID, "completed"); err != nil {
-		return nil, fmt.Errorf("failed to update schedule status: %w", err)
+	return corrected, nil
+}
+
+// Get a visit's manual entry and corrections with the values before and after each
+func (v *VisitService) GetVisitCorrections(ctx context.Context, visitID uuid.UUID) ([]model.VisitCorrection, error) {
+	if _, err := v.visitRepo.GetVisitByID(ctx, visitID); err != nil {
+		return nil, err
 	}
 
-	return updatedVisit, nil
+	return v.visitRepo.GetVisitCorrections(ctx, visitID)
 }
 
 // Get visit by ID
@@ -118,12 +393,17 @@ func (v *VisitService) GetVisitByScheduleID(ctx context.Context, scheduleID uuid
 
 // Update visit status
 func (v *VisitService) UpdateVisitStatus(ctx context.Context, visitID uuid.UUID, status string) error {
-	// Validate status
-	if !isValidVisitStatus(status) {
-		return fmt.Errorf("invalid visit status: %s", status)
+	visit, err := v.visitRepo.GetVisitByID(ctx, visitID)
+	if err != nil {
+		return fmt.Errorf("failed to get visit: %w", err)
+	}
+
+	// Validate the change against the visit lifecycle
+	if err := model.VisitStatusMachine.Transition(visit.Status, status); err != nil {
+		return err
 	}
 
-	return v.visitRepo.UpdateVisitStatus(ctx, visitID, status)
+	return v.visitRepo.UpdateVisitStatus(ctx, visitID, visit.Status, status)
 }
 
 // Get visit statistics
@@ -136,6 +416,11 @@ func (v *VisitService) GetVisitDurationStats(ctx context.Context) (map[string]in
 	return v.visitRepo.GetVisitDurationStats(ctx)
 }
 
+// Get a page of visits matching listing's filters, in its sort
+func (v *VisitService) GetVisits(ctx context.Context, page model.CursorPage, listing *listquery.Query[model.Visit]) (*model.CursorPaginatedResponse[model.Visit], error) {
+	return v.visitRepo.GetVisits(ctx, page, listing)
+}
+
 // Get visits by status
 func (v *VisitService) GetVisitsByStatus(ctx context.Context, status string) ([]model.Visit, error) {
 	return v.visitRepo.GetVisitsByStatus(ctx, status)
@@ -143,7 +428,7 @@ func (v *VisitService) GetVisitsByStatus(ctx context.Context, status string) ([]
 
 // Get active visits (in_progress)
 func (v *VisitService) GetActiveVisits(ctx context.Context) ([]model.Visit, error) {
-	return v.GetVisitsByStatus(ctx, "in_progress")
+	return v.GetVisitsByStatus(ctx, model.VisitStatusInProgress)
 }
 
 // Validate that a visit can be started for a schedule
@@ -165,8 +450,8 @@ func (v *VisitService) ValidateStartVisit(ctx context.Context, scheduleID uuid.U
 	}
 
 	// Check if schedule is in a valid status to start visit
-	if schedule.Status == "completed" {
-		return fmt.Errorf("schedule is already completed")
+	if err := model.ScheduleStatusMachine.Transition(schedule.Status, model.ScheduleStatusInProgress); err != nil {
+		return err
 	}
 
 	return nil
@@ -221,6 +506,62 @@ func (v *VisitService) ValidateVisitData(scheduleID uuid.UUID, startLat, startLo
 	return nil
 }
 
+// checkGeofence compares a clock event with the schedule's service location.
+// It returns nil when the schedule has no coordinates to verify against, and
+// rejects the event when the agency policy does not allow out-of-range clock events.
+func (v *VisitService) checkGeofence(schedule *model.Schedule, lat, long float64, event string) (*model.GeofenceResult, error) {
+	if !schedule.HasServiceLocation() {
+		return nil, nil
+	}
+
+	radius := v.evvConfig.GeofenceRadiusMeters
+	if schedule.GeofenceRadiusMeters != nil {
+		radius = *schedule.GeofenceRadiusMeters
+	}
+
+	distance := geo.DistanceMeters(*schedule.Latitude, *schedule.Longitude, lat, long)
+	result := &model.GeofenceResult{
+		DistanceMeters: distance,
+		WithinGeofence: distance <= float64(radius),
+	}
+
+	if !result.WithinGeofence && v.evvConfig.GeofencePolicy == config.GeofencePolicyReject {
+		code := "OUTSIDE_GEOFENCE"
+		message := fmt.Sprintf("%s location is %.0f meters from the service location (allowed: %d meters)", event, distance, radius)
+		return nil, errs.NewBadRequestError(message, true, &code, nil, nil)
+	}
+
+	return result, nil
+}
+
+// recordCompletedVisit records the compliance check and billing of a visit
+// that was just completed, in the transaction that completed it
+func recordCompletedVisit(ctx context.Context, repos *repository.TxRepositories, visit *model.Visit, schedule *model.Schedule) error {
+	if _, err := recordVisitCompliance(ctx, repos.VisitCompliance, visit, schedule); err != nil {
+		return fmt.Errorf("failed to record visit compliance: %w", err)
+	}
+
+	if _, err := recordVisitBilling(ctx, repos.Billing, repos.Client, visit, schedule); err != nil {
+		return fmt.Errorf("failed to record visit billing: %w", err)
+	}
+
+	return nil
+}
+
+// manualEntryCoordinates returns the coordinates given for a manual entry, or
+// the schedule's service location when none were given
+func manualEntryCoordinates(schedule *model.Schedule, lat, long *float64) (*float64, *float64, error) {
+	if lat != nil && long != nil {
+		return lat, long, nil
+	}
+
+	if !schedule.HasServiceLocation() {
+		return nil, nil, errs.NewBadRequestError("Coordinates are required when the schedule has no service location", true, nil, nil, nil)
+	}
+
+	return schedule.Latitude, schedule.Longitude, nil
+}
+
 // Helper function to check if coordinates are valid
 func isValidCoordinates(lat, long float64) bool {
 	return lat >= -90 && lat <= 90 && long >= -180 && long <= 180
@@ -228,12 +569,7 @@ func isValidCoordinates(lat, long float64) bool {
 
 // Helper function to check if visit status is valid
 func isValidVisitStatus(status string) bool {
-	validStatuses := map[string]bool{
-		"not_started":  true,
-		"in_progress":  true,
-		"completed":    true,
-	}
-	return validStatuses[status]
+	return model.VisitStatusMachine.IsValid(status)
 }
 
 // Get visit summary with calculated duration
@@ -33,6 +33,10 @@ func CreateTestServer(logger *zerolog.Logger, db *TestDB) *server.Server {
 		}
 	}
 
+	if db.Config.EVV == nil {
+		db.Config.EVV = config.DefaultEVVConfig()
+	}
+
 	testServer := &server.Server{
 		Logger: logger,
 		DB: &database.Database{
@@ -0,0 +1,39 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type ListAggregatorBatchesQuery struct {
+	PaginationQuery
+	Status string `query:"status" validate:"omitempty,oneof=pending delivered failed"`
+}
+
+type AggregatorBatchIDParam struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+}
+
+type ListVisitSubmissionsQuery struct {
+	PaginationQuery
+	Status string `query:"status" validate:"omitempty,oneof=pending submitted accepted rejected failed"`
+}
+
+func (r *ListAggregatorBatchesQuery) Validate() error {
+	r.applyDefaults()
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *AggregatorBatchIDParam) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *ListVisitSubmissionsQuery) Validate() error {
+	r.applyDefaults()
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
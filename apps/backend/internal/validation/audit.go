@@ -0,0 +1,23 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type ListAuditEventsQuery struct {
+	PaginationQuery
+	Entity   string     `query:"entity" validate:"omitempty,oneof=schedule visit task"`
+	EntityID *uuid.UUID `query:"entityId"`
+	Actor    string     `query:"actor" validate:"omitempty,max=255"`
+	// Time range as RFC 3339 timestamps; from is inclusive, to is exclusive
+	From string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To   string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+func (r *ListAuditEventsQuery) Validate() error {
+	r.applyDefaults()
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
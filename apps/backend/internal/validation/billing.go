@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type CreateBillingRateRequest struct {
+	Payer       string     `json:"payer" validate:"required,min=1,max=50"`
+	ServiceCode string     `json:"serviceCode" validate:"required,min=1,max=20"`
+	CaregiverID *uuid.UUID `json:"caregiverId,omitempty"`
+	// Minutes in a billable unit; 15 when omitted
+	UnitMinutes   int    `json:"unitMinutes,omitempty" validate:"omitempty,min=1,max=1440"`
+	Rounding      string `json:"rounding" validate:"required,oneof=eight_minute round_down round_up"`
+	UnitRateCents int64  `json:"unitRateCents" validate:"min=0"`
+	// Service dates as YYYY-MM-DD; both inclusive
+	EffectiveFrom string  `json:"effectiveFrom" validate:"required,datetime=2006-01-02"`
+	EffectiveTo   *string `json:"effectiveTo,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type BillingRateIDParam struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+}
+
+type ListBillingRatesQuery struct {
+	PaginationQuery
+	Payer       string `query:"payer" validate:"omitempty,max=50"`
+	ServiceCode string `query:"serviceCode" validate:"omitempty,max=20"`
+}
+
+// BillVisitsRequest selects visits by service date; both dates are inclusive
+type BillVisitsRequest struct {
+	From string `json:"from" validate:"required,datetime=2006-01-02"`
+	To   string `json:"to" validate:"required,datetime=2006-01-02"`
+}
+
+func (r *CreateBillingRateRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *BillingRateIDParam) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *ListBillingRatesQuery) Validate() error {
+	r.applyDefaults()
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *BillVisitsRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
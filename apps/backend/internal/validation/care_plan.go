@@ -0,0 +1,33 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type CarePlanTaskRequest struct {
+	Name        string  `json:"name" validate:"required,min=2,max=255"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+	// Optional tasks may be skipped without affecting visit verification
+	IsRequired *bool `json:"isRequired,omitempty"`
+}
+
+type CreateCarePlanRequest struct {
+	ClientID uuid.UUID             `param:"id" validate:"required"`
+	Name     string                `json:"name" validate:"required,min=2,max=255"`
+	Tasks    []CarePlanTaskRequest `json:"tasks" validate:"required,min=1,max=100,dive"`
+}
+
+type CarePlanIDParam struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+}
+
+func (r *CreateCarePlanRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *CarePlanIDParam) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type CreateCaregiverRequest struct {
+	// Clerk user ID the caregiver signs in with
+	UserID    string  `json:"userId" validate:"required,min=1,max=255"`
+	FirstName string  `json:"firstName" validate:"required,min=1,max=100"`
+	LastName  string  `json:"lastName" validate:"required,min=1,max=100"`
+	Email     *string `json:"email,omitempty" validate:"omitempty,email,max=255"`
+	Phone     *string `json:"phone,omitempty" validate:"omitempty,min=7,max=32"`
+}
+
+type CaregiverIDParam struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+}
+
+type AssignCaregiverRequest struct {
+	ScheduleID  uuid.UUID `param:"id" validate:"required"`
+	CaregiverID uuid.UUID `json:"caregiverId" validate:"required"`
+}
+
+func (r *CreateCaregiverRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *CaregiverIDParam) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *AssignCaregiverRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// A caregiver's own shifts are listed by start time, so they page by number
+type ListMySchedulesQuery struct {
+	PaginationQuery
+	Status string `query:"status" validate:"omitempty,oneof=missed upcoming in_progress completed cancelled"`
+}
+
+func (r *ListMySchedulesQuery) Validate() error {
+	r.applyDefaults()
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// DateLayout is the format of date-only request fields
+const DateLayout = "2006-01-02"
+
+type EmergencyContactRequest struct {
+	Name         string `json:"name" validate:"required,min=1,max=255"`
+	Relationship string `json:"relationship,omitempty" validate:"omitempty,max=100"`
+	Phone        string `json:"phone" validate:"required,min=7,max=32"`
+}
+
+type ClientRequest struct {
+	FirstName string `json:"firstName" validate:"required,min=1,max=100"`
+	LastName  string `json:"lastName" validate:"required,min=1,max=100"`
+	// Date of birth as YYYY-MM-DD
+	DateOfBirth  *string  `json:"dateOfBirth,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Gender       *string  `json:"gender,omitempty" validate:"omitempty,max=50"`
+	MedicaidID   *string  `json:"medicaidId,omitempty" validate:"omitempty,min=4,max=32,alphanum"`
+	Payer        *string  `json:"payer,omitempty" validate:"omitempty,min=1,max=50"`
+	AddressLine1 string   `json:"addressLine1" validate:"required,min=2,max=255"`
+	AddressLine2 *string  `json:"addressLine2,omitempty" validate:"omitempty,max=255"`
+	City         string   `json:"city" validate:"required,min=1,max=100"`
+	State        string   `json:"state" validate:"required,min=2,max=50"`
+	PostalCode   string   `json:"postalCode" validate:"required,min=3,max=20"`
+	Latitude     *float64 `json:"latitude,omitempty" validate:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude    *float64 `json:"longitude,omitempty" validate:"required_with=Latitude,omitempty,min=-180,max=180"`
+	Phone        *string  `json:"phone,omitempty" validate:"omitempty,min=7,max=32"`
+
+	EmergencyContacts []EmergencyContactRequest `json:"emergencyContacts,omitempty" validate:"omitempty,max=10,dive"`
+}
+
+type CreateClientRequest struct {
+	ClientRequest
+}
+
+type UpdateClientRequest struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+	ClientRequest
+	Status string `json:"status,omitempty" validate:"omitempty,oneof=active inactive"`
+}
+
+type ClientIDParam struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+}
+
+type ListClientsQuery struct {
+	PaginationQuery
+	Status string `query:"status" validate:"omitempty,oneof=active inactive"`
+	Search string `query:"q" validate:"omitempty,max=255"`
+}
+
+func (r *CreateClientRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *UpdateClientRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *ClientIDParam) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *ListClientsQuery) Validate() error {
+	r.applyDefaults()
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
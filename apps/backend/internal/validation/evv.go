@@ -4,47 +4,137 @@ import (
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/errs"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/listquery"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+const (
+	DefaultPage  = 1
+	DefaultLimit = 10
+)
+
+// Edit scopes for a schedule generated from a recurring series
+const (
+	ScheduleEditScopeThis   = "this"
+	ScheduleEditScopeFuture = "future"
 )
 
 // Schedule validation structures
 type CreateScheduleRequest struct {
-	ClientName string `json:"clientName" validate:"required,min=2,max=255"`
-	ShiftTime  string `json:"shiftTime" validate:"required,min=5,max=20"` // Format: HH:MM-HH:MM
-	Location   string `json:"location" validate:"required,min=2,max=255"`
+	ClientID   uuid.UUID `json:"clientId" validate:"required"`
+	// Service address; defaults to the client's primary address when omitted
+	Location   string `json:"location,omitempty" validate:"omitempty,min=2,max=255"`
+	// Payer code for the type of service, e.g. T1019; required for EVV compliance
+	ServiceCode *string `json:"serviceCode,omitempty" validate:"omitempty,min=1,max=32"`
+	// Shift window as RFC 3339 timestamps; TimeZone is the IANA zone the shift is worked in
+	ScheduledStart time.Time `json:"scheduledStart" validate:"required"`
+	ScheduledEnd   time.Time `json:"scheduledEnd" validate:"required,gtfield=ScheduledStart"`
+	TimeZone       string    `json:"timeZone" validate:"required,max=64"`
+	// Service location coordinates for geofence verification
+	Latitude             *float64 `json:"latitude,omitempty" validate:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude            *float64 `json:"longitude,omitempty" validate:"required_with=Latitude,omitempty,min=-180,max=180"`
+	GeofenceRadiusMeters *int     `json:"geofenceRadiusMeters,omitempty" validate:"omitempty,min=10,max=5000"`
 }
 
 type UpdateScheduleRequest struct {
-	ClientName *string `json:"clientName,omitempty" validate:"omitempty,min=2,max=255"`
-	ShiftTime  *string `json:"shiftTime,omitempty" validate:"omitempty,min=5,max=20"`
+	ID         uuid.UUID `param:"id" validate:"required"`
+	// Scope "future" applies the edit to this and every later occurrence of the series
+	Scope      string `query:"scope" validate:"omitempty,oneof=this future"`
+	ClientID   *uuid.UUID `json:"clientId,omitempty"`
 	Location   *string `json:"location,omitempty" validate:"omitempty,min=2,max=255"`
+	ServiceCode *string `json:"serviceCode,omitempty" validate:"omitempty,min=1,max=32"`
+	ScheduledStart *time.Time `json:"scheduledStart,omitempty" validate:"required_with=ScheduledEnd"`
+	ScheduledEnd   *time.Time `json:"scheduledEnd,omitempty" validate:"required_with=ScheduledStart"`
+	TimeZone       *string    `json:"timeZone,omitempty" validate:"omitempty,max=64"`
 }
 
+// UpdateScheduleStatusRequest only takes the statuses a coordinator sets by
+// hand; in_progress and completed follow clock-in and clock-out
 type UpdateScheduleStatusRequest struct {
-	Status string `json:"status" validate:"required,oneof=missed upcoming in_progress completed"`
+	ID     uuid.UUID `param:"id" validate:"required"`
+	Status string    `json:"status" validate:"required,oneof=missed cancelled"`
+}
+
+type ScheduleIDParam struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+}
+
+// Listings are filtered and sorted by the remaining query parameters, see
+// model.ScheduleListing, model.VisitListing and model.TaskListing
+type ListSchedulesQuery struct {
+	CursorQuery
+	Listing *listquery.Query[model.Schedule] `query:"-"`
 }
 
+type ListVisitsQuery struct {
+	CursorQuery
+	Listing *listquery.Query[model.Visit] `query:"-"`
+}
+
+type ListTasksQuery struct {
+	CursorQuery
+	Listing *listquery.Query[model.Task] `query:"-"`
+}
+
+// EmptyRequest is used by endpoints that take no input
+type EmptyRequest struct{}
+
 // Visit validation structures
 type StartVisitRequest struct {
+	ScheduleID uuid.UUID `param:"id" validate:"required"`
 	StartTime time.Time `json:"startTime" validate:"required"`
 	StartLat  float64   `json:"startLat" validate:"required,min=-90,max=90"`
 	StartLong float64   `json:"startLong" validate:"required,min=-180,max=180"`
 }
 
 type EndVisitRequest struct {
+	ScheduleID uuid.UUID `param:"id" validate:"required"`
 	EndTime time.Time `json:"endTime" validate:"required"`
 	EndLat  float64   `json:"endLat" validate:"required,min=-90,max=90"`
 	EndLong float64   `json:"endLong" validate:"required,min=-180,max=180"`
 }
 
+// Coordinator entry of a visit after the fact. Coordinates default to the
+// schedule's service location.
+type CreateManualVisitRequest struct {
+	ScheduleID uuid.UUID  `param:"id" validate:"required"`
+	StartTime  time.Time  `json:"startTime" validate:"required"`
+	EndTime    *time.Time `json:"endTime,omitempty"`
+	StartLat   *float64   `json:"startLat,omitempty" validate:"required_with=StartLong,omitempty,min=-90,max=90"`
+	StartLong  *float64   `json:"startLong,omitempty" validate:"required_with=StartLat,omitempty,min=-180,max=180"`
+	EndLat     *float64   `json:"endLat,omitempty" validate:"required_with=EndLong,excluded_without=EndTime,omitempty,min=-90,max=90"`
+	EndLong    *float64   `json:"endLong,omitempty" validate:"required_with=EndLat,excluded_without=EndTime,omitempty,min=-180,max=180"`
+	ReasonCode string     `json:"reasonCode" validate:"required,max=64"`
+	Reason     string     `json:"reason" validate:"required,min=5,max=2000"`
+}
+
+// Coordinator correction of a visit's clock data; omitted fields are kept
+type CorrectVisitRequest struct {
+	ID         uuid.UUID  `param:"id" validate:"required"`
+	StartTime  *time.Time `json:"startTime,omitempty"`
+	EndTime    *time.Time `json:"endTime,omitempty"`
+	StartLat   *float64   `json:"startLat,omitempty" validate:"required_with=StartLong,omitempty,min=-90,max=90"`
+	StartLong  *float64   `json:"startLong,omitempty" validate:"required_with=StartLat,omitempty,min=-180,max=180"`
+	EndLat     *float64   `json:"endLat,omitempty" validate:"required_with=EndLong,omitempty,min=-90,max=90"`
+	EndLong    *float64   `json:"endLong,omitempty" validate:"required_with=EndLat,omitempty,min=-180,max=180"`
+	ReasonCode string     `json:"reasonCode" validate:"required,max=64"`
+	Reason     string     `json:"reason" validate:"required,min=5,max=2000"`
+}
+
 // Task validation structures
 type CreateTaskRequest struct {
-	Name        string  `json:"name" validate:"required,min=2,max=255"`
-	Description *string `json:"description,omitempty" validate:"omitempty,min=10,max=1000"`
+	ScheduleID  uuid.UUID `param:"id" validate:"required"`
+	Name        string    `json:"name" validate:"required,min=2,max=255"`
+	Description *string   `json:"description,omitempty" validate:"omitempty,min=10,max=1000"`
 }
 
 type UpdateTaskStatusRequest struct {
-	Status string  `json:"status" validate:"required,oneof=pending completed not_completed"`
-	Reason *string `json:"reason,omitempty"`
+	ScheduleID uuid.UUID `param:"scheduleId" validate:"required"`
+	TaskID     uuid.UUID `param:"taskId" validate:"required"`
+	Status     string    `json:"status" validate:"required,oneof=pending completed not_completed"`
+	Reason     *string   `json:"reason,omitempty"`
 }
 
 // Pagination validation
@@ -53,10 +143,33 @@ type PaginationQuery struct {
 	Limit int `query:"limit" validate:"min=1,max=100"`
 }
 
+// CursorQuery pages through a listing with the opaque cursors of its
+// responses; the total is only counted when includeTotal is set
+type CursorQuery struct {
+	Cursor       string `query:"cursor"`
+	Limit        int    `query:"limit" validate:"min=1,max=100"`
+	IncludeTotal bool   `query:"includeTotal"`
+
+	cursor *model.Cursor
+}
+
+// Page returns the page selected by the query; it is valid after Validate
+func (r *CursorQuery) Page() model.CursorPage {
+	return model.CursorPage{
+		Cursor:       r.cursor,
+		Limit:        r.Limit,
+		IncludeTotal: r.IncludeTotal,
+	}
+}
+
 type SearchQuery struct {
-	Query string `query:"q" validate:"required,min=1"`
-	Page  int    `query:"page" validate:"min=1"`
-	Limit int    `query:"limit" validate:"min=1,max=100"`
+	Query  string `query:"q" validate:"required,min=1,max=200"`
+	Page   int    `query:"page" validate:"min=1"`
+	Limit  int    `query:"limit" validate:"min=1,max=100"`
+	Status string `query:"status" validate:"omitempty,oneof=missed upcoming in_progress completed cancelled"`
+	// Range of scheduled start times as RFC 3339 timestamps; from is inclusive, to is exclusive
+	From string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To   string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 }
 
 // Validation methods for request bodies
@@ -66,10 +179,10 @@ func (r *CreateScheduleRequest) Validate() error {
 		return err
 	}
 
-	// Additional validation for shift time format
-	if !isValidShiftTime(r.ShiftTime) {
-		return &CustomValidationErrors{
-			{Field: "shiftTime", Message: "Shift time must be in HH:MM-HH:MM format"},
+	// Additional validation for the IANA time zone name
+	if !isValidTimeZone(r.TimeZone) {
+		return CustomValidationErrors{
+			{Field: "timeZone", Message: "Time zone must be a valid IANA time zone (e.g. America/New_York)"},
 		}
 	}
 
@@ -78,7 +191,23 @@ func (r *CreateScheduleRequest) Validate() error {
 
 func (r *UpdateScheduleRequest) Validate() error {
 	validate := validator.New()
-	return validate.Struct(r)
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	if r.ScheduledStart != nil && r.ScheduledEnd != nil && !r.ScheduledEnd.After(*r.ScheduledStart) {
+		return CustomValidationErrors{
+			{Field: "scheduledEnd", Message: "Scheduled end must be after scheduled start"},
+		}
+	}
+
+	if r.TimeZone != nil && !isValidTimeZone(*r.TimeZone) {
+		return CustomValidationErrors{
+			{Field: "timeZone", Message: "Time zone must be a valid IANA time zone (e.g. America/New_York)"},
+		}
+	}
+
+	return nil
 }
 
 func (r *UpdateScheduleStatusRequest) Validate() error {
@@ -96,6 +225,26 @@ func (r *EndVisitRequest) Validate() error {
 	return validate.Struct(r)
 }
 
+func (r *CreateManualVisitRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *CorrectVisitRequest) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	if r.StartTime == nil && r.EndTime == nil && r.StartLat == nil && r.EndLat == nil {
+		return CustomValidationErrors{
+			{Field: "startTime", Message: "At least one of startTime, endTime, startLat/startLong or endLat/endLong is required"},
+		}
+	}
+
+	return nil
+}
+
 func (r *CreateTaskRequest) Validate() error {
 	validate := validator.New()
 	return validate.Struct(r)
@@ -115,20 +264,139 @@ func (r *UpdateTaskStatusRequest) Validate() error {
 }
 
 func (r *PaginationQuery) Validate() error {
+	r.applyDefaults()
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *ScheduleIDParam) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *ListSchedulesQuery) ParseQuery(values map[string][]string) []errs.FieldError {
+	listing, fieldErrors := listquery.Parse(values, model.ScheduleListing, cursorParams...)
+	r.Listing = listing
+	return fieldErrors
+}
+
+func (r *ListSchedulesQuery) Validate() error {
+	if err := r.decodeCursor(); err != nil {
+		return err
+	}
+	if err := checkCursorSort(r.cursor, r.Listing); err != nil {
+		return err
+	}
+
 	validate := validator.New()
 	return validate.Struct(r)
 }
 
+func (r *ListVisitsQuery) ParseQuery(values map[string][]string) []errs.FieldError {
+	listing, fieldErrors := listquery.Parse(values, model.VisitListing, cursorParams...)
+	r.Listing = listing
+	return fieldErrors
+}
+
+func (r *ListVisitsQuery) Validate() error {
+	if err := r.decodeCursor(); err != nil {
+		return err
+	}
+	if err := checkCursorSort(r.cursor, r.Listing); err != nil {
+		return err
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *ListTasksQuery) ParseQuery(values map[string][]string) []errs.FieldError {
+	listing, fieldErrors := listquery.Parse(values, model.TaskListing, cursorParams...)
+	r.Listing = listing
+	return fieldErrors
+}
+
+func (r *ListTasksQuery) Validate() error {
+	if err := r.decodeCursor(); err != nil {
+		return err
+	}
+	if err := checkCursorSort(r.cursor, r.Listing); err != nil {
+		return err
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *EmptyRequest) Validate() error {
+	return nil
+}
+
 func (r *SearchQuery) Validate() error {
+	if r.Page == 0 {
+		r.Page = DefaultPage
+	}
+	if r.Limit == 0 {
+		r.Limit = DefaultLimit
+	}
+
 	validate := validator.New()
 	return validate.Struct(r)
 }
 
+// applyDefaults fills in page and limit when the query string omits them
+func (r *PaginationQuery) applyDefaults() {
+	if r.Page == 0 {
+		r.Page = DefaultPage
+	}
+	if r.Limit == 0 {
+		r.Limit = DefaultLimit
+	}
+}
+
+// decodeCursor applies the default limit and decodes the cursor
+func (r *CursorQuery) decodeCursor() error {
+	if r.Limit == 0 {
+		r.Limit = DefaultLimit
+	}
+
+	r.cursor = nil
+	if r.Cursor != "" {
+		cursor, err := model.DecodeCursor(r.Cursor)
+		if err != nil {
+			return CustomValidationErrors{{Field: "cursor", Message: "is not a cursor returned by this listing"}}
+		}
+		r.cursor = cursor
+	}
+
+	return nil
+}
+
+// cursorParams are the query parameters of CursorQuery, which listings bind
+// themselves
+var cursorParams = []string{"cursor", "limit", "includeTotal"}
+
+// checkCursorSort rejects a cursor returned for a different sort than the
+// listing's, whose position would not mean anything in it
+func checkCursorSort[T any](cursor *model.Cursor, listing *listquery.Query[T]) error {
+	if cursor == nil {
+		return nil
+	}
+	if cursor.Sort != listing.Sort.String() || !listing.ValidPosition(cursor.Value) {
+		return CustomValidationErrors{{Field: "cursor", Message: "was returned for a different sort"}}
+	}
+	return nil
+}
+
 // Helper functions
-func isValidShiftTime(shiftTime string) bool {
-	// Simple validation for HH:MM-HH:MM format
-	return len(shiftTime) >= 5 && len(shiftTime) <= 20 &&
-		   shiftTime[2] == ':' && shiftTime[5] == '-'
+func isValidTimeZone(name string) bool {
+	// Local is process-dependent and never a meaningful zone for a shift
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
 }
 
 // isValidCoordinates validates latitude and longitude
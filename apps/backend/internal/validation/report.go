@@ -0,0 +1,19 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// ReportQuery selects the period of a report and its file format. The format
+// may also be chosen with the Accept header; see report.Negotiate.
+type ReportQuery struct {
+	// Period as RFC 3339 timestamps; from is inclusive, to is exclusive
+	From   string `query:"from" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	To     string `query:"to" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	Format string `query:"format" validate:"omitempty,oneof=csv xlsx pdf"`
+}
+
+func (r *ReportQuery) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
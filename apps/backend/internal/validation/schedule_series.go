@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/lib/rrule"
+)
+
+type CreateScheduleSeriesRequest struct {
+	ClientID    uuid.UUID  `json:"clientId" validate:"required"`
+	CaregiverID *uuid.UUID `json:"caregiverId,omitempty"`
+	// RRule is the RFC 5545 pattern, e.g. FREQ=WEEKLY;BYDAY=MO,WE,FR; bound it with endDate or count
+	RRule     string `json:"rrule" validate:"required,max=255"`
+	StartDate string `json:"startDate" validate:"required,datetime=2006-01-02"`
+	// Local start time of every occurrence in TimeZone
+	StartTime       string   `json:"startTime" validate:"required,datetime=15:04"`
+	DurationMinutes int      `json:"durationMinutes" validate:"required,min=1,max=1440"`
+	TimeZone        string   `json:"timeZone" validate:"required,max=64"`
+	EndDate         *string  `json:"endDate,omitempty" validate:"excluded_with=Count,omitempty,datetime=2006-01-02"`
+	Count           *int     `json:"count,omitempty" validate:"excluded_with=EndDate,omitempty,min=1,max=1000"`
+	ExceptionDates  []string `json:"exceptionDates,omitempty" validate:"omitempty,max=366,dive,datetime=2006-01-02"`
+	// Service address; defaults to the client's primary address when omitted
+	Location             string   `json:"location,omitempty" validate:"omitempty,min=2,max=255"`
+	ServiceCode          *string  `json:"serviceCode,omitempty" validate:"omitempty,min=1,max=32"`
+	Latitude             *float64 `json:"latitude,omitempty" validate:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude            *float64 `json:"longitude,omitempty" validate:"required_with=Latitude,omitempty,min=-180,max=180"`
+	GeofenceRadiusMeters *int     `json:"geofenceRadiusMeters,omitempty" validate:"omitempty,min=10,max=5000"`
+}
+
+type ScheduleSeriesIDParam struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+}
+
+func (r *CreateScheduleSeriesRequest) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	if !isValidTimeZone(r.TimeZone) {
+		return CustomValidationErrors{
+			{Field: "timeZone", Message: "Time zone must be a valid IANA time zone (e.g. America/New_York)"},
+		}
+	}
+
+	rule, err := rrule.Parse(r.RRule)
+	if err != nil {
+		return CustomValidationErrors{
+			{Field: "rrule", Message: "Recurrence rule is invalid: " + err.Error()},
+		}
+	}
+
+	// The series bounds are stored on their own so they can be changed when the series is split
+	if rule.Count > 0 || rule.Until != nil {
+		return CustomValidationErrors{
+			{Field: "rrule", Message: "Use count or endDate instead of COUNT or UNTIL"},
+		}
+	}
+
+	// Both dates use the same layout, so they compare as strings
+	if r.EndDate != nil && *r.EndDate < r.StartDate {
+		return CustomValidationErrors{
+			{Field: "endDate", Message: "End date must not be before start date"},
+		}
+	}
+
+	return nil
+}
+
+func (r *ScheduleSeriesIDParam) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// SyncEventsRequest is a batch of events recorded on a device. SentAt is the
+// device time the batch was sent at and lets the server measure clock skew.
+// A batch holds at most 200 events.
+type SyncEventsRequest struct {
+	SentAt *time.Time         `json:"sentAt,omitempty"`
+	Events []SyncEventRequest `json:"events" validate:"required,min=1,max=200,dive"`
+}
+
+// SyncEventRequest is one clock-in, clock-out or task status change. Clock
+// events carry a schedule and coordinates, task events a task and status.
+type SyncEventRequest struct {
+	ClientEventID uuid.UUID  `json:"clientEventId" validate:"required"`
+	Type          string     `json:"type" validate:"required,oneof=clock_in clock_out task_status"`
+	DeviceTime    time.Time  `json:"deviceTime" validate:"required"`
+	ScheduleID    *uuid.UUID `json:"scheduleId,omitempty" validate:"required_unless=Type task_status"`
+	Latitude      *float64   `json:"latitude,omitempty" validate:"required_unless=Type task_status,omitempty,min=-90,max=90"`
+	Longitude     *float64   `json:"longitude,omitempty" validate:"required_unless=Type task_status,omitempty,min=-180,max=180"`
+	TaskID        *uuid.UUID `json:"taskId,omitempty" validate:"required_if=Type task_status"`
+	Status        *string    `json:"status,omitempty" validate:"required_if=Type task_status,omitempty,oneof=pending completed not_completed"`
+	Reason        *string    `json:"reason,omitempty" validate:"omitempty,max=1000"`
+}
+
+type ListSyncEventsQuery struct {
+	PaginationQuery
+	UserID string `query:"userId" validate:"omitempty,max=255"`
+	Status string `query:"status" validate:"omitempty,oneof=pending applied rejected"`
+	Flag   string `query:"flag" validate:"omitempty,oneof=clock_skew late_arrival"`
+}
+
+func (r *SyncEventsRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *ListSyncEventsQuery) Validate() error {
+	r.applyDefaults()
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -0,0 +1,18 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// TimesheetQuery selects the pay period containing a date, today when empty,
+// and optionally a single caregiver
+type TimesheetQuery struct {
+	Date        string     `query:"date" validate:"omitempty,datetime=2006-01-02"`
+	CaregiverID *uuid.UUID `query:"caregiverId"`
+}
+
+func (r *TimesheetQuery) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -15,6 +16,13 @@ type Validatable interface {
 	Validate() error
 }
 
+// QueryParser is implemented by payloads that read query parameters the
+// binder cannot map onto fields, such as the filters of a listing. It runs
+// after binding and before Validate.
+type QueryParser interface {
+	ParseQuery(values map[string][]string) []errs.FieldError
+}
+
 type CustomValidationError struct {
 	Field   string
 	Message string
@@ -32,6 +40,12 @@ func BindAndValidate(c echo.Context, payload Validatable) error {
 		return errs.NewBadRequestError(message, false, nil, nil, nil)
 	}
 
+	if parser, ok := payload.(QueryParser); ok {
+		if fieldErrors := parser.ParseQuery(c.QueryParams()); fieldErrors != nil {
+			return errs.NewBadRequestError("Invalid query parameters", true, nil, fieldErrors, nil)
+		}
+	}
+
 	if msg, fieldErrors := validateStruct(payload); fieldErrors != nil {
 		return errs.NewBadRequestError(msg, true, nil, fieldErrors, nil)
 	}
@@ -48,9 +62,15 @@ func validateStruct(v Validatable) (string, []errs.FieldError) {
 
 func extractValidationErrors(err error) (string, []errs.FieldError) {
 	var fieldErrors []errs.FieldError
-	validationErrors, ok := err.(validator.ValidationErrors)
-	if !ok {
-		customValidationErrors := err.(CustomValidationErrors)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		// Custom errors may be returned by value or, as NewValidationError does, by pointer
+		var customValidationErrors CustomValidationErrors
+		if pointer := (*CustomValidationErrors)(nil); errors.As(err, &pointer) && pointer != nil {
+			customValidationErrors = *pointer
+		} else if !errors.As(err, &customValidationErrors) {
+			return "Validation failed", []errs.FieldError{{Error: err.Error()}}
+		}
 		for _, err := range customValidationErrors {
 			fieldErrors = append(fieldErrors, errs.FieldError{
 				Field: err.Field,
@@ -88,6 +108,8 @@ func extractValidationErrors(err error) (string, []errs.FieldError) {
 			msg = "must be a valid UUID"
 		case "uuidList":
 			msg = "must be a comma-separated list of valid UUIDs"
+		case "required_with":
+			msg = fmt.Sprintf("is required when %s is set", strings.ToLower(err.Param()))
 		case "dive":
 			msg = "some items are invalid"
 		default:
@@ -0,0 +1,29 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// ListNonCompliantVisitsQuery selects visits by service date; both dates are inclusive
+type ListNonCompliantVisitsQuery struct {
+	PaginationQuery
+	From string `query:"from" validate:"required,datetime=2006-01-02"`
+	To   string `query:"to" validate:"required,datetime=2006-01-02"`
+}
+
+type CheckVisitComplianceRequest struct {
+	From string `json:"from" validate:"required,datetime=2006-01-02"`
+	To   string `json:"to" validate:"required,datetime=2006-01-02"`
+}
+
+func (r *ListNonCompliantVisitsQuery) Validate() error {
+	r.applyDefaults()
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *CheckVisitComplianceRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -0,0 +1,123 @@
+package validation
+
+import (
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sriniously/go-boilerplate/apps/backend/internal/model"
+)
+
+// VisitExceptionStatusAll lists exceptions in every status
+const VisitExceptionStatusAll = "all"
+
+var reasonCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+type ListVisitExceptionsQuery struct {
+	PaginationQuery
+	// Status defaults to pending_review, the coordinator's review queue
+	Status string `query:"status" validate:"omitempty,oneof=open pending_review approved rejected all"`
+	// Range of detection times as RFC 3339 timestamps; from is inclusive, to is exclusive
+	From string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To   string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+type VisitExceptionIDParam struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+}
+
+type VisitIDParam struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+}
+
+type ExplainVisitExceptionRequest struct {
+	ID          uuid.UUID `param:"id" validate:"required"`
+	ReasonCode  string    `json:"reasonCode" validate:"required,max=64"`
+	Explanation string    `json:"explanation" validate:"required,min=5,max=2000"`
+}
+
+type ApproveVisitExceptionRequest struct {
+	ID      uuid.UUID `param:"id" validate:"required"`
+	Comment *string   `json:"comment,omitempty" validate:"omitempty,max=2000"`
+}
+
+type RejectVisitExceptionRequest struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+	// The caregiver needs to know why the explanation was not accepted
+	Comment string `json:"comment" validate:"required,min=5,max=2000"`
+}
+
+type ListExceptionReasonCodesQuery struct {
+	IncludeInactive bool `query:"includeInactive"`
+}
+
+type CreateExceptionReasonCodeRequest struct {
+	// Codes are upper snake case, e.g. GPS_UNAVAILABLE
+	Code        string `json:"code" validate:"required,max=64"`
+	Description string `json:"description" validate:"required,min=2,max=255"`
+}
+
+type UpdateExceptionReasonCodeRequest struct {
+	Code        string `param:"code" validate:"required,max=64"`
+	Description string `json:"description" validate:"required,min=2,max=255"`
+	IsActive    bool   `json:"isActive"`
+}
+
+func (r *ListVisitExceptionsQuery) Validate() error {
+	r.applyDefaults()
+	if r.Status == "" {
+		r.Status = model.VisitExceptionStatusPendingReview
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *VisitExceptionIDParam) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *VisitIDParam) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *ExplainVisitExceptionRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *ApproveVisitExceptionRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *RejectVisitExceptionRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *ListExceptionReasonCodesQuery) Validate() error {
+	return nil
+}
+
+func (r *CreateExceptionReasonCodeRequest) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	if !reasonCodePattern.MatchString(r.Code) {
+		return CustomValidationErrors{
+			{Field: "code", Message: "Code must be upper snake case (e.g. GPS_UNAVAILABLE)"},
+		}
+	}
+
+	return nil
+}
+
+func (r *UpdateExceptionReasonCodeRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}